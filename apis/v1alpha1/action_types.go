@@ -28,8 +28,16 @@ const (
 	InitializeAction        ActionType = "Initialize"
 	GenerateCertAction      ActionType = "GenerateCert"
 	ResizePVCAction         ActionType = "ResizePVC"
+	ExpandPVCAction         ActionType = "ExpandPVC"
+	MigrateStorageAction    ActionType = "MigrateStorage"
+	SnapshotAction          ActionType = "Snapshot"
+	RetainVolumesAction     ActionType = "RetainVolumes"
+	MigrateTLSAction        ActionType = "MigrateTLS"
+	ApplyTemplateAction     ActionType = "ApplyTemplate"
 	PartitionedUpdateAction ActionType = "PartitionedUpdate"
 	SetupRBACAction         ActionType = "SetupRBAC"
 	UnknownAction           ActionType = "Unknown"
 	ExposeIngressAction     ActionType = "ExposeIngressAction"
+	ExposeGatewayAction     ActionType = "ExposeGatewayAction"
+	FinalizeUpgradeAction   ActionType = "FinalizeUpgrade"
 )
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+// MergeTemplate merges the spec of the template under the spec of the cluster with the rules of a
+// JSON merge patch: the fields set in the cluster replace the fields of the template, and objects
+// are merged field by field. The template is rejected if it has fields unknown to the cluster spec.
+//
+// The patch is the JSON of the spec as stored in the API server, not r.Spec: the fields of r.Spec
+// without omitempty would marshal their zero values, like supportsAutoResize: false, and replace the
+// fields of the template the user did not set in the cluster.
+func (r *CrdbCluster) MergeTemplate(template *CrdbClusterTemplate, patch []byte) error {
+	base := template.Spec.Spec.Raw
+	if len(base) == 0 {
+		base = []byte("{}")
+	}
+	if len(patch) == 0 {
+		patch = []byte("{}")
+	}
+
+	merged, err := jsonpatch.MergePatch(base, patch)
+	if err != nil {
+		return fmt.Errorf("the spec of the template %s is not an object: %v", template.Name, err)
+	}
+
+	spec := CrdbClusterSpec{}
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return fmt.Errorf("the spec of the template %s is not a valid cluster spec: %v", template.Name, err)
+	}
+
+	r.Spec = spec
+	return nil
+}
+
+// LastMerged returns a copy of the cluster with the spec last merged with its template, recorded in its
+// status by the operator, or the cluster itself if it has no template or was never merged with it.
+func (r *CrdbCluster) LastMerged() (*CrdbCluster, error) {
+	last := r.Status.Template
+	if r.Spec.TemplateRef == nil || last == nil || last.Spec == nil {
+		return r, nil
+	}
+
+	merged := r.DeepCopy()
+	merged.Spec = CrdbClusterSpec{}
+	if err := json.Unmarshal(last.Spec.Raw, &merged.Spec); err != nil {
+		return nil, fmt.Errorf("failed to read the spec last merged with the template %s: %v", last.Name, err)
+	}
+	merged.Spec.TemplateRef = r.Spec.TemplateRef
+	return merged, nil
+}
+
+// Open returns true if the window is open at the given time, and otherwise how long until it opens.
+func (w *MaintenanceWindow) Open(now time.Time) (bool, time.Duration) {
+	// the webhook rejects an invalid start, a window that cannot be parsed never holds a rollout
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return true, 0
+	}
+
+	now = now.UTC()
+	opens := time.Date(now.Year(), now.Month(), now.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+	if opens.After(now) {
+		opens = opens.AddDate(0, 0, -1)
+	}
+	if now.Before(opens.Add(w.Duration.Duration)) {
+		return true, 0
+	}
+	return false, opens.AddDate(0, 0, 1).Sub(now)
+}
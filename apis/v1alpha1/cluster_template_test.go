@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestMergeTemplate(t *testing.T) {
+	template := func(raw string) *CrdbClusterTemplate {
+		return &CrdbClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Spec:       CrdbClusterTemplateSpec{Spec: runtime.RawExtension{Raw: []byte(raw)}},
+		}
+	}
+
+	t.Run("the cluster fields win over the template", func(t *testing.T) {
+		cluster := &CrdbCluster{}
+
+		require.NoError(t, cluster.MergeTemplate(template(`{
+			"nodes": 3,
+			"cockroachDBVersion": "v24.1.0",
+			"tlsEnabled": true,
+			"additionalLabels": {"env": "prod", "team": "platform"}
+		}`), []byte(`{"nodes": 5, "cockroachDBVersion": "v24.2.0", "additionalLabels": {"team": "payments"}}`)))
+
+		require.Equal(t, int32(5), cluster.Spec.Nodes)
+		require.Equal(t, "v24.2.0", cluster.Spec.CockroachDBVersion)
+		require.True(t, cluster.Spec.TLSEnabled)
+		require.Equal(t, map[string]string{"env": "prod", "team": "payments"}, cluster.Spec.AdditionalLabels)
+	})
+
+	t.Run("an empty template keeps the cluster spec", func(t *testing.T) {
+		cluster := &CrdbCluster{Spec: CrdbClusterSpec{Nodes: 3, CockroachDBVersion: "v24.1.0"}}
+		expected := cluster.Spec.DeepCopy()
+
+		require.NoError(t, cluster.MergeTemplate(template(""), []byte(`{"nodes": 3, "cockroachDBVersion": "v24.1.0"}`)))
+		require.Equal(t, *expected, cluster.Spec)
+	})
+
+	t.Run("the fields the cluster does not set keep the template value", func(t *testing.T) {
+		cluster := &CrdbCluster{}
+
+		require.NoError(t, cluster.MergeTemplate(template(`{
+			"nodes": 3,
+			"tlsEnabled": true,
+			"dataStore": {"supportsAutoResize": true}
+		}`), []byte(`{"nodes": 5, "dataStore": {"pvc": {"spec": {"volumeMode": "Filesystem"}}}}`)))
+
+		require.Equal(t, int32(5), cluster.Spec.Nodes)
+		require.True(t, cluster.Spec.TLSEnabled)
+		require.True(t, cluster.Spec.DataStore.SupportsAutoResize)
+		require.NotNil(t, cluster.Spec.DataStore.VolumeClaim)
+	})
+
+	t.Run("unknown fields are rejected", func(t *testing.T) {
+		cluster := &CrdbCluster{Spec: CrdbClusterSpec{Nodes: 3}}
+
+		err := cluster.MergeTemplate(template(`{"nodeCount": 3}`), []byte(`{"nodes": 3}`))
+		require.ErrorContains(t, err, `the spec of the template shared is not a valid cluster spec`)
+		require.Equal(t, int32(3), cluster.Spec.Nodes)
+	})
+}
+
+func TestValidateTemplateRef(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("the spec is validated after the merge", func(t *testing.T) {
+		cluster := &CrdbCluster{Spec: CrdbClusterSpec{TemplateRef: &TemplateReference{Name: "shared"}}}
+
+		warnings, err := cluster.ValidateCreate(ctx, cluster)
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+
+		require.NoError(t, cluster.Default(ctx, cluster))
+		require.Nil(t, cluster.Spec.SQLPort)
+	})
+
+	t.Run("the template name is required", func(t *testing.T) {
+		cluster := &CrdbCluster{Spec: CrdbClusterSpec{TemplateRef: &TemplateReference{}}}
+
+		_, err := cluster.ValidateCreate(ctx, cluster)
+		require.Equal(t, field.Required(field.NewPath("spec", "templateRef", "name"), "the name of the CrdbClusterTemplate is required"), err)
+	})
+
+	t.Run("the maintenance window is validated", func(t *testing.T) {
+		cluster := &CrdbCluster{Spec: CrdbClusterSpec{TemplateRef: &TemplateReference{
+			Name:              "shared",
+			MaintenanceWindow: &MaintenanceWindow{Start: "02:00", Duration: metav1.Duration{Duration: 25 * time.Hour}},
+		}}}
+
+		_, err := cluster.ValidateCreate(ctx, cluster)
+		require.ErrorContains(t, err, "the duration of the window must be positive and at most 24h")
+
+		cluster.Spec.TemplateRef.MaintenanceWindow = &MaintenanceWindow{Start: "2am", Duration: metav1.Duration{Duration: time.Hour}}
+		_, err = cluster.ValidateCreate(ctx, cluster)
+		require.ErrorContains(t, err, "the start of the window must be HH:MM in UTC")
+	})
+}
+
+func TestMaintenanceWindowOpen(t *testing.T) {
+	window := &MaintenanceWindow{Start: "23:00", Duration: metav1.Duration{Duration: 2 * time.Hour}}
+
+	for _, tt := range []struct {
+		name string
+		now  string
+		open bool
+		wait time.Duration
+	}{
+		{name: "before the window", now: "2026-10-19T22:30:00Z", wait: 30 * time.Minute},
+		{name: "in the window", now: "2026-10-19T23:30:00Z", open: true},
+		{name: "in the window past midnight", now: "2026-10-20T00:30:00Z", open: true},
+		{name: "after the window", now: "2026-10-20T01:00:00Z", wait: 22 * time.Hour},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tt.now)
+			require.NoError(t, err)
+
+			open, wait := window.Open(now)
+			require.Equal(t, tt.open, open)
+			require.Equal(t, tt.wait, wait)
+		})
+	}
+}
+
+func TestValidateTemplatedClusterAgainstLastMerged(t *testing.T) {
+	ctx := context.Background()
+	templated := func(spec CrdbClusterSpec, merged string) *CrdbCluster {
+		spec.TemplateRef = &TemplateReference{Name: "shared"}
+		return &CrdbCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cockroachdb"},
+			Spec:       spec,
+			Status: CrdbClusterStatus{Template: &TemplateStatus{
+				Name:             "shared",
+				ObservedRevision: 1,
+				Spec:             &runtime.RawExtension{Raw: []byte(merged)},
+			}},
+		}
+	}
+
+	t.Run("the deletion protection of the template blocks the deletion", func(t *testing.T) {
+		cluster := templated(CrdbClusterSpec{Nodes: 3}, `{"nodes": 3, "deletionProtection": true}`)
+
+		_, err := cluster.ValidateDelete(ctx, cluster)
+		require.ErrorContains(t, err, "deletion protection is enabled")
+	})
+
+	t.Run("leaving the template does not drop what the template set", func(t *testing.T) {
+		old := templated(CrdbClusterSpec{Nodes: 3}, `{
+			"nodes": 3,
+			"encryption": {"secretName": "keys", "activeKey": "aes-256.key"},
+			"additionalLabels": {"env": "prod"}
+		}`)
+		cluster := &CrdbCluster{ObjectMeta: old.ObjectMeta, Spec: CrdbClusterSpec{Nodes: 3}}
+
+		_, err := cluster.ValidateUpdate(ctx, old, cluster)
+		require.ErrorContains(t, err, "removing encryption is not supported")
+		require.ErrorContains(t, err, "mutating additionalLabels field is not supported")
+	})
+
+	t.Run("a field moves from the cluster to the template", func(t *testing.T) {
+		old := templated(CrdbClusterSpec{Nodes: 3, AdditionalLabels: map[string]string{"env": "prod"}},
+			`{"nodes": 3, "additionalLabels": {"env": "prod"}}`)
+		cluster := old.DeepCopy()
+		cluster.Spec.AdditionalLabels = nil
+
+		_, err := cluster.ValidateUpdate(ctx, old, cluster)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// CrdbClusterTemplateSpec defines the fields shared by the clusters that reference the template.
+type CrdbClusterTemplateSpec struct {
+	// Spec is a partial CrdbCluster spec. It is merged under the spec of each cluster that references the
+	// template with the rules of a JSON merge patch: the fields set in the cluster replace the fields of
+	// the template, and objects are merged field by field.
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +required
+	Spec runtime.RawExtension `json:"spec"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// TemplateReference references the CrdbClusterTemplate of a cluster.
+type TemplateReference struct {
+	// Name of the CrdbClusterTemplate in the namespace of the cluster
+	// +required
+	Name string `json:"name"`
+	// (Optional) MaintenanceWindow is the daily window in which the changes of the template are rolled out to
+	// the cluster. Outside of the window, a running cluster with a change of its template to roll out is not
+	// reconciled until the window opens. Giving the clusters of a template different windows staggers the
+	// rollout of the template.
+	// Default: the changes of the template are rolled out once merged
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// MaintenanceWindow is a window of time that opens every day.
+type MaintenanceWindow struct {
+	// Start is the time of the day the window opens, as HH:MM in UTC
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +required
+	Start string `json:"start"`
+	// Duration is how long the window stays open, at most 24h
+	// +required
+	Duration metav1.Duration `json:"duration"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// TemplateStatus describes the template last merged into the spec of a cluster.
+type TemplateStatus struct {
+	// Name of the CrdbClusterTemplate
+	Name string `json:"name"`
+	// ObservedRevision is the generation of the template last merged and validated
+	ObservedRevision int64 `json:"observedRevision"`
+	// AppliedRevision is the generation of the template last rolled out to the cluster. It is set once the
+	// StatefulSet is deployed or updated with the merged spec, or once the cluster has nothing left to do.
+	// +optional
+	AppliedRevision int64 `json:"appliedRevision,omitempty"`
+	// SpecHash is the SHA-256 of the JSON of Spec
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+	// Spec is the last spec merged with the template that passed validation. The next merged spec is
+	// validated as an update of it, like the webhook validates a change of the spec of a cluster.
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:deepcopy-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=all;cockroachdb,shortName=crdbtemplate
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.metadata.generation`
+// +operator-sdk:csv:customresourcedefinitions:displayName="CockroachDB Cluster Template"
+// +k8s:openapi-gen=true
+
+// CrdbClusterTemplate is the CRD for the specs shared by several cockroachDB clusters
+type CrdbClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CrdbClusterTemplateSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen=true
+
+// CrdbClusterTemplateList contains a list of CrdbClusterTemplate
+type CrdbClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CrdbClusterTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CrdbClusterTemplate{}, &CrdbClusterTemplateList{})
+}
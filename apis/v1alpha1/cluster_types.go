@@ -19,7 +19,9 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
@@ -27,6 +29,16 @@ const (
 	// If this label is set to "true", the operator will stop reconciling the
 	// entire cluster.
 	CrdbOperatorMigrationLabel = "crdb.io/skip-reconcile"
+	// CrdbClusterFinalizer is the finalizer of the clusters whose persistent volumes are deleted
+	// with the cluster. The operator removes it once the volumes are handled.
+	CrdbClusterFinalizer = "crdb.cockroachlabs.com/finalizer"
+	// CrdbTLSMigrationAnnotation is the annotation that requests the migration of a running cluster
+	// to or from TLS. If it is set to "true", tlsEnabled can be changed and the operator restarts
+	// all the nodes at once with the new security mode.
+	CrdbTLSMigrationAnnotation = "crdb.io/tls-migration"
+	// CrdbProfileAnnotation is the annotation that selects the defaulting profile of a cluster
+	// when the profile field is not set.
+	CrdbProfileAnnotation = "crdb.io/profile"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -72,6 +84,10 @@ type CrdbClusterSpec struct {
 	// Default: ""
 	// +optional
 	ClientTLSSecret string `json:"clientTLSSecret,omitempty"`
+	// (Optional) Certificates configures the node and client certificates issued by the operator: the
+	// CA they are issued from, additional names of the node certificate and when they are renewed
+	// +optional
+	Certificates *CertificatesConfig `json:"certificates,omitempty"`
 	// (Optional) The maximum number of pods that can be unavailable during a rolling update.
 	// This number is set in the PodDistruptionBudget and defaults to 1.
 	// +optional
@@ -141,6 +157,10 @@ type CrdbClusterSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cockroach Database Ingress"
 	// +optional
 	Ingress *IngressConfig `json:"ingress,omitempty"`
+	// (Optional) Gateway defines the Gateway API routes used to expose the services through an existing Gateway
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cockroach Database Gateway Routes"
+	// +optional
+	Gateway *GatewayConfig `json:"gateway,omitempty"`
 	// (Optional) LogConfigMap define the config map which contains log configuration used to send the logs through the
 	// proper channels in the cockroachdb. Logging configuration is available for cockroach version v21.1.0 onwards.
 	// The logging configuration is taken in format of yaml file, you can check the logging configuration here (https://www.cockroachlabs.com/docs/stable/configure-logs.html#default-logging-configuration)
@@ -161,6 +181,66 @@ type CrdbClusterSpec struct {
 	// Default : 300
 	// +optional
 	TerminationGracePeriodSecs int64 `json:"terminationGracePeriodSecs,omitempty"`
+	// (Optional) UpgradeStrategy configures how a new CockroachDB version is rolled out
+	// to the pods of the cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cockroach Database Upgrade Strategy"
+	// +optional
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+	// (Optional) Upgrade controls how major version upgrades are finalized and when
+	// a failed upgrade is rolled back
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cockroach Database Upgrade"
+	// +optional
+	Upgrade *UpgradeSpec `json:"upgrade,omitempty"`
+	// (Optional) HealthChecks configures the checks that gate rolling updates and
+	// restarts between pods
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cockroach Database Health Checks"
+	// +optional
+	HealthChecks *HealthCheckSpec `json:"healthChecks,omitempty"`
+	// (Optional) PodTemplate is a pod template that is merged onto the pods generated by
+	// the operator with a strategic merge patch. It adds sidecars, init containers,
+	// volumes, a securityContext or envFrom sources to the db container. Fields managed
+	// by the operator cannot be overridden.
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Template"
+	// +optional
+	PodTemplate *runtime.RawExtension `json:"podTemplate,omitempty"`
+	// (Optional) Overrides are patches applied to the objects generated by the operator,
+	// in order, each time they are reconciled
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Overrides"
+	// +optional
+	Overrides []ResourceOverride `json:"overrides,omitempty"`
+	// (Optional) Services configures the services that expose the cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Services"
+	// +optional
+	Services *ServicesConfig `json:"services,omitempty"`
+	// (Optional) NetworkPolicy generates a NetworkPolicy that only admits the expected traffic to the
+	// CockroachDB pods
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Network Policy"
+	// +optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+	// (Optional) Encryption enables the encryption at rest of the store with keys read from a Secret.
+	// Changing the active key rotates it with a rolling restart of the nodes
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Encryption"
+	// +optional
+	Encryption *EncryptionConfig `json:"encryption,omitempty"`
+	// (Optional) DeletionProtection makes the webhook reject the deletion of the cluster until it is disabled
+	// Default: false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Deletion Protection",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+	// (Optional) Profile is expanded by the mutating webhook into the resources, scheduling constraints,
+	// disruption budget, storage and termination grace period of the nodes. Any field set in the spec
+	// overrides the value of the profile. The crdb.io/profile annotation selects a profile when it is not set.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Profile"
+	// +optional
+	Profile ClusterProfile `json:"profile,omitempty"`
+	// (Optional) TemplateRef references a CrdbClusterTemplate in the namespace of the cluster. The spec of the
+	// template is merged under the fields of the cluster, and the changes of the template are rolled out to the
+	// cluster like the changes of its own spec.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Template Reference"
+	// +optional
+	TemplateRef *TemplateReference `json:"templateRef,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -179,12 +259,73 @@ type CrdbClusterStatus struct {
 	// CrdbContainerImage is the container that will be installed
 	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="CrdbContainerImage",xDescriptors="urn:alm:descriptor:com.tectonic.ui:hidden"
 	CrdbContainerImage string `json:"crdbcontainerimage,omitempty"`
-	// SQLHost is the host to be used with SQL ingress
+	// SQLHost is the host to be used with SQL ingress, or the address of the load balancer
+	// that exposes SQL when there is no SQL ingress
 	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="SQLHost",xDescriptors="urn:alm:descriptor:com.tectonic.ui:hidden"
 	SQLHost string `json:"sqlHost,omitempty"`
+	// Certificates describes the node and client certificates last issued from spec.certificates
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="Certificates",xDescriptors="urn:alm:descriptor:com.tectonic.ui:hidden"
+	Certificates *CertificatesStatus `json:"certificates,omitempty"`
 	// OperatorStatus represent the status of the operator(Failed, Starting, Running or Other)
 	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="OperatorStatus"
 	ClusterStatus string `json:"clusterStatus,omitempty"`
+	// UpgradeSoakStartTime is when the canaries of the upgrade in progress started soaking. The
+	// upgrade holds until the soak duration has passed since then.
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="UpgradeSoakStartTime"
+	UpgradeSoakStartTime *metav1.Time `json:"upgradeSoakStartTime,omitempty"`
+	// PreserveDowngradeVersion is the version the cluster can still be downgraded to
+	// while the last major upgrade is not finalized
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="PreserveDowngradeVersion"
+	PreserveDowngradeVersion string `json:"preserveDowngradeVersion,omitempty"`
+	// UpgradeFinalizationCheckTime is when the operator checks again whether the user finalized
+	// the last major upgrade. The other actions run in the meantime.
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="UpgradeFinalizationCheckTime"
+	UpgradeFinalizationCheckTime *metav1.Time `json:"upgradeFinalizationCheckTime,omitempty"`
+	// RolledBackVersion is the version of the last upgrade that was rolled back
+	// automatically. The upgrade is not retried until another version is requested.
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="RolledBackVersion"
+	RolledBackVersion string `json:"rolledBackVersion,omitempty"`
+	// UpgradeFromVersion is the version the cluster ran when the upgrade in progress started,
+	// a failed upgrade is rolled back to it
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="UpgradeFromVersion"
+	UpgradeFromVersion string `json:"upgradeFromVersion,omitempty"`
+	// UpgradeFromImage is the image the cluster ran when the upgrade in progress started
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="UpgradeFromImage"
+	UpgradeFromImage string `json:"upgradeFromImage,omitempty"`
+	// UpgradePlan lists the versions of a multi-hop upgrade, ending with the requested version
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="UpgradePlan"
+	UpgradePlan []string `json:"upgradePlan,omitempty"`
+	// UpgradeHop is the version of the upgrade plan that is currently rolled out
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="UpgradeHop"
+	UpgradeHop string `json:"upgradeHop,omitempty"`
+	// UpgradePreview describes the pending upgrade while spec.upgrade.dryRun is set
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="UpgradePreview"
+	UpgradePreview *UpgradePreview `json:"upgradePreview,omitempty"`
+	// HealthChecks are the results of the last health checks of a rolling update or restart
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="HealthChecks"
+	HealthChecks []HealthCheckResult `json:"healthChecks,omitempty"`
+	// Encryption reports the store key rolled out to the nodes and the active key ID of each node
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="Encryption"
+	Encryption *EncryptionStatus `json:"encryption,omitempty"`
+	// AutoExpand reports the last check of the capacity of the stores and the size the volumes
+	// of the data store were expanded to
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="AutoExpand"
+	AutoExpand *AutoExpandStatus `json:"autoExpand,omitempty"`
+	// StorageMigration reports the progress of the migration of the data store to a new storage class
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="StorageMigration"
+	StorageMigration *StorageMigrationStatus `json:"storageMigration,omitempty"`
+	// RetainedPVCs reports the PVCs of the decommissioned nodes that are not pruned yet
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="RetainedPVCs"
+	RetainedPVCs *RetainedPVCsStatus `json:"retainedPVCs,omitempty"`
+	// Snapshots lists the snapshots of the volumes taken with the crdb.io/snapshot annotation
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="Snapshots"
+	Snapshots []SnapshotStatus `json:"snapshots,omitempty"`
+	// TLSMigration reports the progress of the migration of a running cluster to or from TLS
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="TLSMigration"
+	TLSMigration *TLSMigrationStatus `json:"tlsMigration,omitempty"`
+	// Template reports the revision of the template last merged into the spec of the cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=status, displayName="Template"
+	Template *TemplateStatus `json:"template,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -280,6 +421,217 @@ type Volume struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PVC Supports Auto Resizing",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	// +optional
 	SupportsAutoResize bool `json:"supportsAutoResize"`
+	// (Optional) Attributes of the store, used to constrain the placement of replicas in zone configurations
+	// +optional
+	Attributes []string `json:"attributes,omitempty"`
+	// (Optional) Stores are the additional stores of each node, each with its own persistent volume
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Additional Stores"
+	// +optional
+	Stores []Store `json:"stores,omitempty"`
+	// (Optional) WALFailover is a persistent volume the nodes fail the write-ahead log over to
+	// when a store stalls
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="WAL Failover Volume"
+	// +optional
+	WALFailover *WALFailoverVolume `json:"walFailover,omitempty"`
+	// (Optional) AutoExpand expands the persistent volumes of the data store when the usage of the store
+	// reaches a threshold. It cannot be combined with Stores.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Auto Expand"
+	// +optional
+	AutoExpand *AutoExpandConfig `json:"autoExpand,omitempty"`
+	// (Optional) VolumeSnapshotClassName is the VolumeSnapshotClass of the snapshots of the volumes taken
+	// with the crdb.io/snapshot annotation. The default class of the CSI driver is used if not set.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Volume Snapshot Class"
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+	// (Optional) DataSource creates the volumes of a new cluster from a snapshot of another cluster in the
+	// same namespace, to clone that cluster. It is only used when the statefulset is created. While it
+	// is set, a NetworkPolicy isolates the nodes of the clone from the nodes of that cluster.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Data Source"
+	// +optional
+	DataSource *SnapshotSource `json:"dataSource,omitempty"`
+	// (Optional) RetentionPolicy defines what happens to the persistent volumes of the nodes when the
+	// cluster is deleted: they are kept with Retain and deleted with Delete.
+	// Default: Retain
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Retention Policy"
+	// +optional
+	RetentionPolicy RetentionPolicy `json:"retentionPolicy,omitempty"`
+	// (Optional) FinalSnapshot takes a snapshot of the volumes of the nodes before they are deleted with
+	// the Delete retention policy. The volumes are kept if the snapshot fails.
+	// Default: false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Final Snapshot",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	// +optional
+	FinalSnapshot bool `json:"finalSnapshot,omitempty"`
+	// (Optional) Prune defines how the PVCs of the decommissioned nodes are deleted with the AutoPrunePVC
+	// feature gate. The PVCs are only deleted once CockroachDB reports their node decommissioned.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PVC Pruning"
+	// +optional
+	Prune *PruneConfig `json:"prune,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
+
+// PruneConfig defines what happens before the PVCs of the decommissioned nodes are deleted.
+type PruneConfig struct {
+	// (Optional) Snapshot takes a VolumeSnapshot of each PVC, with the VolumeSnapshot class of the data
+	// store, and deletes the PVC once the snapshot is ready to use
+	// Default: false
+	// +optional
+	Snapshot bool `json:"snapshot,omitempty"`
+	// (Optional) RetentionDelay is how long the PVCs are kept once they are no longer used
+	// +optional
+	RetentionDelay *metav1.Duration `json:"retentionDelay,omitempty"`
+}
+
+// RetentionPolicy defines what happens to the persistent volumes when the cluster is deleted
+// +kubebuilder:validation:Enum=Retain;Delete
+type RetentionPolicy string
+
+const (
+	// RetainPolicy keeps the persistent volumes of the nodes after the cluster is deleted
+	RetainPolicy RetentionPolicy = "Retain"
+	// DeletePolicy deletes the persistent volumes of the nodes with the cluster
+	DeletePolicy RetentionPolicy = "Delete"
+)
+
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
+
+// SnapshotSource references a snapshot of the volumes of a cluster.
+type SnapshotSource struct {
+	// Cluster is the name of the cluster the snapshot was taken of
+	Cluster string `json:"cluster"`
+	// Snapshot is the name of the snapshot, the value of the crdb.io/snapshot annotation
+	Snapshot string `json:"snapshot"`
+}
+
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
+
+// CertificatesConfig configures the node and client certificates issued by the operator.
+type CertificatesConfig struct {
+	// (Optional) CASecret is the name of a Secret with the certificate (ca.crt) and the private key
+	// (ca.key) of a CA, e.g. an intermediate CA, the node and client certificates are issued from.
+	// The operator generates its own CA if not set.
+	// +optional
+	CASecret string `json:"caSecret,omitempty"`
+	// (Optional) DNSNames are additional DNS names of the node certificate. The hosts of the SQL
+	// and DB Console ingresses or routes are always added.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+	// (Optional) IPAddresses are additional IP addresses of the node certificate
+	// +optional
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+	// (Optional) RenewBefore is how long before their expiration the certificates are reissued
+	// Default: 720h
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+	// (Optional) Clients are the client certificates issued for SQL users of applications. Each one
+	// is written with a connection string to a Secret that follows the Service Binding specification.
+	// +optional
+	Clients []ClientCertificate `json:"clients,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
+
+// ClientCertificate is a client certificate issued for a SQL user. The Secret has the type
+// servicebinding.io/postgresql and the entries type, provider, host, port, username, database,
+// sslmode, uri, ca.crt, tls.crt and tls.key. The SQL user has to exist in the cluster and cannot
+// be root. The Secret is labelled with crdb.io/cluster and crdb.io/cluster-namespace, an existing
+// Secret without these labels is not overwritten.
+type ClientCertificate struct {
+	// User is the SQL user the certificate is issued for
+	User string `json:"user"`
+	// (Optional) Namespace is the namespace of the Secret, the namespace of the cluster if not set.
+	// Another namespace has to be labelled with crdb.io/client-certificates-from set to the
+	// namespace of the cluster.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// (Optional) SecretName is the name of the Secret
+	// Default: <cluster name>-client-<user>
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// (Optional) Database is the database of the connection string
+	// Default: defaultdb
+	// +optional
+	Database string `json:"database,omitempty"`
+	// (Optional) BindingRoot is the directory the Secret is projected to in the application, under
+	// which the connection string refers to the certificate files of the Secret
+	// Default: /bindings
+	// +optional
+	BindingRoot string `json:"bindingRoot,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// CertificatesStatus describes the node and client certificates issued by the operator.
+type CertificatesStatus struct {
+	// CASecret is the Secret of the CA the certificates were issued from, empty for the CA
+	// generated by the operator
+	// +optional
+	CASecret string `json:"caSecret,omitempty"`
+	// SANs are the additional DNS names and IP addresses of the node certificate
+	// +optional
+	SANs []string `json:"sans,omitempty"`
+	// Clients are the client certificates issued for SQL users, with their defaults
+	// +optional
+	Clients []ClientCertificate `json:"clients,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
+
+// AutoExpandConfig defines when and by how much the persistent volumes of the data store are expanded.
+type AutoExpandConfig struct {
+	// (Optional) Threshold is the percentage of the capacity of the store used by a node before the
+	// volumes are expanded
+	// Default: 80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	Threshold int32 `json:"threshold,omitempty"`
+	// Increment is the storage added to the volumes on each expansion
+	Increment resource.Quantity `json:"increment"`
+	// MaxSize is the size the volumes are not expanded beyond
+	MaxSize resource.Quantity `json:"maxSize"`
+	// (Optional) Interval between two checks of the capacity of the stores
+	// Default: 5m
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
+
+// Store is an additional store of the nodes, backed by a persistent volume per node.
+type Store struct {
+	// Name of the store, used as the name of its volume claim template. The store
+	// is mounted at /cockroach/stores/<name>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Spec of the persistent volume claims of the store
+	Spec corev1.PersistentVolumeClaimSpec `json:"spec"`
+	// (Optional) Attributes of the store, used to constrain the placement of replicas in zone configurations
+	// +optional
+	Attributes []string `json:"attributes,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
+
+// WALFailoverVolume is the persistent volume of the write-ahead log failover of the nodes.
+type WALFailoverVolume struct {
+	// Spec of the persistent volume claims of the WAL failover volume
+	Spec corev1.PersistentVolumeClaimSpec `json:"spec"`
 }
 
 // +k8s:openapi-gen=true
@@ -296,8 +648,25 @@ type IngressConfig struct {
 	// Adding/changing the SQL host will result in rolling update of the crdb cluster nodes
 	// +optional
 	SQL *Ingress `json:"sql,omitempty"`
+
+	// (Optional) Mode selects the resources exposing the services: Ingress, or OpenShift Routes
+	// with TLS passthrough.
+	// Default: Ingress
+	// +optional
+	Mode IngressMode `json:"mode,omitempty"`
 }
 
+// IngressMode is the kind of resource used to expose the services
+// +kubebuilder:validation:Enum=Ingress;Route
+type IngressMode string
+
+const (
+	// IngressModeIngress exposes the services with networking.k8s.io Ingresses
+	IngressModeIngress IngressMode = "Ingress"
+	// IngressModeRoute exposes the services with OpenShift Routes
+	IngressModeRoute IngressMode = "Route"
+)
+
 // +kubebuilder:object:generate=true
 // +k8s:openapi-gen=true
 // +k8s:deepcopy-gen=true
@@ -317,6 +686,83 @@ type Ingress struct {
 	Host string `json:"host"`
 }
 
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
+
+// GatewayConfig defines the Gateway API routes attached to an existing Gateway
+type GatewayConfig struct {
+	// (Optional) Route options for UI (HTTP) connections, exposed with an HTTPRoute
+	// +optional
+	UI *GatewayRoute `json:"ui,omitempty"`
+
+	// (Optional) Route options for SQL connections, exposed with a TLSRoute or a TCPRoute
+	// Adding/changing the SQL host of a TLSRoute will result in rolling update of the crdb cluster nodes
+	// +optional
+	SQL *GatewaySQLRoute `json:"sql,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// GatewayRoute defines a route attached to a Gateway
+type GatewayRoute struct {
+	// ParentRef references the Gateway the route attaches to
+	// +required
+	ParentRef GatewayParentReference `json:"parentRef"`
+	// (Optional) Host is the hostname matched by the route
+	// +optional
+	Host string `json:"host,omitempty"`
+	// (Optional) Annotations related to the route resource
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GatewaySQLRouteKind is the kind of the route used for SQL connections
+// +kubebuilder:validation:Enum=TLSRoute;TCPRoute
+type GatewaySQLRouteKind string
+
+const (
+	// TLSRoute routes SQL connections by SNI with TLS passthrough, the nodes keep terminating TLS
+	TLSRoute GatewaySQLRouteKind = "TLSRoute"
+	// TCPRoute routes all the connections of a Gateway listener to SQL
+	TCPRoute GatewaySQLRouteKind = "TCPRoute"
+)
+
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// GatewaySQLRoute defines the route used for SQL connections
+type GatewaySQLRoute struct {
+	GatewayRoute `json:",inline"`
+	// (Optional) Kind of the route, TLSRoute or TCPRoute. A TLSRoute requires a host
+	// Default: TLSRoute
+	// +optional
+	Kind GatewaySQLRouteKind `json:"kind,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// GatewayParentReference references a Gateway and optionally one of its listeners
+type GatewayParentReference struct {
+	// Name of the Gateway
+	// +required
+	Name string `json:"name"`
+	// (Optional) Namespace of the Gateway, defaults to the namespace of the cluster
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// (Optional) SectionName is the name of the Gateway listener
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+	// (Optional) Port of the Gateway listener
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
 // +kubebuilder:object:generate=true
 // +k8s:openapi-gen=true
 // +k8s:deepcopy-gen=true
@@ -332,6 +778,490 @@ type VolumeClaim struct {
 	PersistentVolumeSource corev1.PersistentVolumeClaimVolumeSource `json:"source,omitempty"`
 }
 
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// UpgradeStrategy defines how the pods of the cluster are moved to a new version.
+type UpgradeStrategy struct {
+	// (Optional) Canaries is the number of pods that are upgraded first. The upgrade holds
+	// once the canaries run the new version and only continues if they stay healthy for
+	// the whole soak duration. Otherwise the upgrade is paused and the UpgradePaused
+	// condition is set.
+	// Default: 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Canaries int32 `json:"canaries,omitempty"`
+	// (Optional) SoakDuration is how long the canaries are watched before the upgrade
+	// continues with the remaining pods
+	// Default: 10m
+	// +optional
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+}
+
+// UpgradeFinalizeMode defines who finalizes a major upgrade.
+// +kubebuilder:validation:Enum=Auto;Manual
+type UpgradeFinalizeMode string
+
+const (
+	// UpgradeFinalizeAuto lets the operator finalize the upgrade once the auto-finalize
+	// delay has passed
+	UpgradeFinalizeAuto UpgradeFinalizeMode = "Auto"
+	// UpgradeFinalizeManual leaves the finalization to the user, who has to reset the
+	// cluster.preserve_downgrade_option cluster setting
+	UpgradeFinalizeManual UpgradeFinalizeMode = "Manual"
+)
+
+// ClusterProfile names a set of defaults for the spec of a cluster.
+// +kubebuilder:validation:Enum=dev;production;production-multi-az
+type ClusterProfile string
+
+const (
+	// DevProfile runs small nodes without scheduling constraints, for development and tests
+	DevProfile ClusterProfile = "dev"
+	// ProductionProfile runs nodes with guaranteed resources, spread on different hosts
+	ProductionProfile ClusterProfile = "production"
+	// ProductionMultiAZProfile is the production profile with the nodes spread evenly across zones
+	ProductionMultiAZProfile ClusterProfile = "production-multi-az"
+)
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// UpgradeSpec defines how major upgrades are finalized and rolled back.
+type UpgradeSpec struct {
+	// (Optional) Finalize defines whether the operator finalizes a major upgrade once
+	// every pod runs the new version. Until the upgrade is finalized the cluster can
+	// be downgraded to the previous version.
+	// Default: Manual
+	// +optional
+	Finalize UpgradeFinalizeMode `json:"finalize,omitempty"`
+	// (Optional) AutoFinalizeDelay is how long the operator waits after a major upgrade
+	// completed before it finalizes the upgrade. Only used when finalize is Auto.
+	// Default: 0s
+	// +optional
+	AutoFinalizeDelay *metav1.Duration `json:"autoFinalizeDelay,omitempty"`
+	// (Optional) AutoRollback rolls the pods back to the previous image when health checks
+	// fail during an upgrade that can still be downgraded
+	// Default: false
+	// +optional
+	AutoRollback bool `json:"autoRollback,omitempty"`
+	// (Optional) MultiHop lets the operator reach a cockroachDBVersion that is several
+	// release series ahead of the running version. The operator plans the intermediate
+	// versions from the supported versions and upgrades one hop at a time, each hop
+	// waiting for the previous one to be finalized.
+	// Default: false
+	// +optional
+	MultiHop bool `json:"multiHop,omitempty"`
+	// (Optional) DryRun holds a version change and only writes a preview of the upgrade
+	// to status.upgradePreview. The pods keep running the current version until dryRun
+	// is removed.
+	// Default: false
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// UpgradePreview describes what the operator does to reach the requested version.
+type UpgradePreview struct {
+	// FromVersion is the version the cluster runs
+	FromVersion string `json:"fromVersion,omitempty"`
+	// ToVersion is the requested version
+	ToVersion string `json:"toVersion,omitempty"`
+	// Kind is the release type of the upgrade: PATCH, MAJOR_UPGRADE, MAJOR_ROLLBACK or UNKNOWN
+	Kind string `json:"kind,omitempty"`
+	// Steps are the actions the operator takes, in order
+	Steps []string `json:"steps,omitempty"`
+	// EstimatedDuration is a rough estimate of how long the steps take, without the
+	// time spent waiting for finalization
+	EstimatedDuration *metav1.Duration `json:"estimatedDuration,omitempty"`
+	// Blockers are the reasons the upgrade would not run right now
+	Blockers []string `json:"blockers,omitempty"`
+}
+
+// HealthCheckType is a check run after a pod is updated or restarted.
+// +kubebuilder:validation:Enum=UnderReplicatedRanges;UnavailableRanges;NodeLiveness;Ready;SQL;PrometheusExpression
+type HealthCheckType string
+
+const (
+	// UnderReplicatedRangesCheck waits until no node reports under-replicated ranges
+	UnderReplicatedRangesCheck HealthCheckType = "UnderReplicatedRanges"
+	// UnavailableRangesCheck waits until no node reports unavailable ranges
+	UnavailableRangesCheck HealthCheckType = "UnavailableRanges"
+	// NodeLivenessCheck waits until every node that is not decommissioned is live
+	NodeLivenessCheck HealthCheckType = "NodeLiveness"
+	// ReadyCheck waits until every node answers /health?ready=1
+	ReadyCheck HealthCheckType = "Ready"
+	// SQLCheck waits until the cluster answers SQL queries
+	SQLCheck HealthCheckType = "SQL"
+	// PrometheusExpressionCheck waits until an expression holds on the metrics of every node
+	PrometheusExpressionCheck HealthCheckType = "PrometheusExpression"
+)
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// HealthCheckSpec defines the checks that run after each pod of a rolling update or
+// restart, and how long they are retried.
+type HealthCheckSpec struct {
+	// (Optional) Checks that all have to pass before the next pod is updated or restarted
+	// Default: UnderReplicatedRanges
+	// +optional
+	Checks []HealthCheck `json:"checks,omitempty"`
+	// (Optional) Timeout is how long the checks are retried before the operation fails
+	// Default: 3m
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// (Optional) Interval is the longest wait between two attempts of a failing check
+	// Default: 10s
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// (Optional) StabilizationDelay is how long the operator waits after the checks
+	// passed before it runs them a second time. A node can be evicted shortly after
+	// it started.
+	// Default: 22s
+	// +optional
+	StabilizationDelay *metav1.Duration `json:"stabilizationDelay,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// HealthCheck defines a single health check.
+type HealthCheck struct {
+	// Type of the check
+	// +required
+	Type HealthCheckType `json:"type"`
+	// (Optional) Name of the check in the status. Names have to be unique.
+	// Default: the type, or the expression of a PrometheusExpression check
+	// +optional
+	Name string `json:"name,omitempty"`
+	// (Optional) Expression compares a metric of the _status/vars endpoint of every
+	// node with a number, for instance `ranges_unavailable == 0` or
+	// `liveness_livenodes{} >= 3`. Label matchers select the samples of the metric.
+	// Required for PrometheusExpression checks.
+	// +kubebuilder:validation:Pattern=`^\s*[a-zA-Z_:][a-zA-Z0-9_:]*(\{[^}]*\})?\s*(==|!=|<=|>=|<|>)\s*[-+]?[0-9.eE+-]+\s*$`
+	// +optional
+	Expression string `json:"expression,omitempty"`
+}
+
+// CheckName returns the name of the check in the status.
+func (c HealthCheck) CheckName() string {
+	switch {
+	case c.Name != "":
+		return c.Name
+	case c.Type == PrometheusExpressionCheck:
+		return c.Expression
+	}
+	return string(c.Type)
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// HealthCheckResult is the last result of a health check.
+type HealthCheckResult struct {
+	// Name of the check
+	// +required
+	Name string `json:"name"`
+	// Passed is true if the check passed on its last attempt
+	// +required
+	Passed bool `json:"passed"`
+	// (Optional) Message is the reason the check failed
+	// +optional
+	Message string `json:"message,omitempty"`
+	// The time of the last attempt
+	// +required
+	LastProbeTime metav1.Time `json:"lastProbeTime"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// ServicesConfig defines the services that expose the cluster.
+type ServicesConfig struct {
+	// (Optional) Public configures the public service, and separate SQL and UI services
+	// +optional
+	Public *PublicServiceConfig `json:"public,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// PublicServiceConfig defines the public service that exposes every port of the cluster,
+// and optional services that only expose the SQL or the HTTP port.
+type PublicServiceConfig struct {
+	ServiceConfig `json:",inline"`
+	// (Optional) SQL creates the <name>-sql service that only exposes the SQL port
+	// +optional
+	SQL *ServiceConfig `json:"sql,omitempty"`
+	// (Optional) UI creates the <name>-ui service that only exposes the HTTP port
+	// +optional
+	UI *ServiceConfig `json:"ui,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// ServiceConfig defines how a service is exposed.
+type ServiceConfig struct {
+	// (Optional) Type of the service
+	// Default: ClusterIP
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// (Optional) LoadBalancerClass selects the load balancer implementation of a
+	// LoadBalancer service
+	// +optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+	// (Optional) LoadBalancerSourceRanges restricts the clients of a LoadBalancer service
+	// to these CIDRs
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// (Optional) ExternalTrafficPolicy of a NodePort or LoadBalancer service
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+	// (Optional) Annotations of the service, in addition to the additionalAnnotations
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// NetworkPolicyConfig defines the peers admitted by the generated NetworkPolicy. The CockroachDB pods
+// always accept gRPC from each other, and SQL and HTTP from the operator.
+type NetworkPolicyConfig struct {
+	// (Optional) SQLClients are the peers allowed to connect to the SQL port
+	// +optional
+	SQLClients []v1.NetworkPolicyPeer `json:"sqlClients,omitempty"`
+	// (Optional) HTTPClients are the peers allowed to connect to the HTTP port, for the DB Console
+	// and the metrics
+	// +optional
+	HTTPClients []v1.NetworkPolicyPeer `json:"httpClients,omitempty"`
+	// (Optional) IngressControllers are the peers allowed to connect to the ports exposed with
+	// spec.ingress or spec.gateway. They are required when the cluster is exposed this way
+	// +optional
+	IngressControllers []v1.NetworkPolicyPeer `json:"ingressControllers,omitempty"`
+	// (Optional) Operator are the peers of the operator, connecting to the SQL and HTTP ports
+	// Default: the pods labelled app=cockroach-operator in any namespace
+	// +optional
+	Operator []v1.NetworkPolicyPeer `json:"operator,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// SnapshotConsistency is the point in time the VolumeSnapshots of a snapshot are consistent to
+type SnapshotConsistency string
+
+const (
+	// SnapshotConsistencyVolume reports that each VolumeSnapshot is crash-consistent on its own,
+	// but the VolumeSnapshots of the nodes are taken one after the other while the nodes are
+	// running: together they are not crash-consistent, and a clone may miss the writes a node
+	// acknowledged between two VolumeSnapshots.
+	SnapshotConsistencyVolume SnapshotConsistency = "Volume"
+)
+
+// SnapshotStatus describes a snapshot of the volumes of the nodes.
+type SnapshotStatus struct {
+	// Name of the snapshot
+	Name string `json:"name"`
+	// CreationTime is the time the VolumeSnapshots were created
+	CreationTime metav1.Time `json:"creationTime"`
+	// Consistency is the point in time the VolumeSnapshots are consistent to
+	// +optional
+	Consistency SnapshotConsistency `json:"consistency,omitempty"`
+	// Nodes is the number of nodes whose volumes are in the snapshot
+	Nodes int32 `json:"nodes"`
+	// VolumeSnapshots are the names of the VolumeSnapshot of each PVC
+	VolumeSnapshots []string `json:"volumeSnapshots,omitempty"`
+	// ReadyToUse reports that every VolumeSnapshot can be used as the data source of a clone
+	ReadyToUse bool `json:"readyToUse"`
+	// Error is the error reported by a VolumeSnapshot that failed
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// StorageMigrationPhase is the step of the replacement of a node during a storage class migration
+type StorageMigrationPhase string
+
+const (
+	// StorageMigrationDecommissioning is the phase where the node is decommissioned
+	StorageMigrationDecommissioning StorageMigrationPhase = "Decommissioning"
+	// StorageMigrationReplacing is the phase where the volumes of the decommissioned node are
+	// deleted and the node is replaced by a new one on the new storage class
+	StorageMigrationReplacing StorageMigrationPhase = "Replacing"
+)
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// StorageMigrationStatus describes the migration of the data store to a new storage class. The
+// nodes are replaced one at a time, from the last to the first, while an additional node keeps
+// the ranges fully replicated.
+type StorageMigrationStatus struct {
+	// StorageClass is the storage class the data store is migrated to
+	StorageClass string `json:"storageClass"`
+	// Node is the ordinal of the node being replaced
+	Node int32 `json:"node"`
+	// Phase is the step of the replacement of the node
+	// +optional
+	Phase StorageMigrationPhase `json:"phase,omitempty"`
+	// StartTime is the time the migration started
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// TLSMigrationPhase is the step of the migration of a running cluster to or from TLS
+type TLSMigrationPhase string
+
+const (
+	// TLSMigrationRestarting is the phase where all the nodes are restarted at once with the new
+	// security mode, as insecure and secure nodes cannot be part of the same cluster
+	TLSMigrationRestarting TLSMigrationPhase = "Restarting"
+)
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// TLSMigrationStatus describes the migration of a running cluster to or from TLS. The cluster is
+// unavailable until all the nodes are restarted with the new security mode.
+type TLSMigrationStatus struct {
+	// TLSEnabled is the security mode the cluster is migrated to
+	TLSEnabled bool `json:"tlsEnabled"`
+	// Phase is the step of the migration
+	// +optional
+	Phase TLSMigrationPhase `json:"phase,omitempty"`
+	// StartTime is the time the migration started
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// AutoExpandStatus describes the automatic expansion of the persistent volumes of the data store
+type AutoExpandStatus struct {
+	// Size is the size the volumes were expanded to, unset before the first expansion
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Usage is the highest percentage of the capacity of the store used by a node at the last check
+	Usage int32 `json:"usage"`
+	// LastCheckTime is the time of the last check of the capacity of the stores
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// RetainedPVCsStatus describes the unused PVCs the AutoPrunePVC feature gate keeps after the
+// decommission of their nodes
+type RetainedPVCsStatus struct {
+	// PVCs are kept until their node is decommissioned, their retention delay passes and their
+	// snapshot is ready to use
+	// +optional
+	PVCs []string `json:"pvcs,omitempty"`
+	// Orphaned are the PVCs no node ever ran on, they are kept until they are deleted manually
+	// +optional
+	Orphaned []string `json:"orphaned,omitempty"`
+	// NextCheckTime is the time the PVCs are checked again
+	NextCheckTime metav1.Time `json:"nextCheckTime"`
+}
+
+// EncryptionPlainKey is the key name that disables the encryption of the store, or marks
+// the store as not encrypted yet when used as the old key.
+const EncryptionPlainKey = "plain"
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// EncryptionConfig defines the store keys used to encrypt the store at rest. The keys are files
+// generated with `cockroach gen encryption-key` and stored in a Secret.
+type EncryptionConfig struct {
+	// SecretName is the name of the Secret holding the store keys
+	SecretName string `json:"secretName"`
+	// ActiveKey is the key of the Secret holding the active store key, or plain to
+	// decrypt the store
+	ActiveKey string `json:"activeKey"`
+	// (Optional) OldKey is the key of the Secret holding the previous store key, or plain
+	// if the store was not encrypted
+	// Default: plain
+	// +optional
+	OldKey string `json:"oldKey,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// EncryptionStatus describes the store key rolled out to the nodes
+type EncryptionStatus struct {
+	// ActiveKey is the Secret and key of the store key the nodes were restarted with,
+	// as <secret>/<key>
+	ActiveKey string `json:"activeKey,omitempty"`
+	// Nodes are the active key IDs reported by each node
+	Nodes []NodeEncryptionStatus `json:"nodes,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// NodeEncryptionStatus is the active store key reported by a node
+type NodeEncryptionStatus struct {
+	// Pod is the name of the pod of the node
+	Pod string `json:"pod"`
+	// ActiveKeyID is the ID of the active store key of the first store, or plain if the store is not encrypted
+	ActiveKeyID string `json:"activeKeyID"`
+	// Stores are the active store key IDs reported by each store of the node
+	// +optional
+	Stores []StoreEncryptionStatus `json:"stores,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// StoreEncryptionStatus is the active store key reported by a store of a node
+type StoreEncryptionStatus struct {
+	// Path of the store in the db container
+	Path string `json:"path"`
+	// ActiveKeyID is the ID of the active store key, or plain if the store is not encrypted
+	ActiveKeyID string `json:"activeKeyID"`
+}
+
+// ResourceOverridePatchType is the type of the patch of a resource override.
+// +kubebuilder:validation:Enum=StrategicMerge;JSON
+type ResourceOverridePatchType string
+
+const (
+	// StrategicMergeOverride patches the object with a strategic merge patch
+	StrategicMergeOverride ResourceOverridePatchType = "StrategicMerge"
+	// JSONOverride patches the object with a JSON patch (RFC 6902)
+	JSONOverride ResourceOverridePatchType = "JSON"
+)
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// ResourceOverride is a patch of an object generated by the operator.
+type ResourceOverride struct {
+	// Kind of the patched object: StatefulSet, Service, PodDisruptionBudget, Ingress,
+	// HTTPRoute, TLSRoute, TCPRoute, NetworkPolicy, ServiceAccount, Role, RoleBinding or Job
+	// +required
+	Kind string `json:"kind"`
+	// Name of the patched object
+	// +required
+	Name string `json:"name"`
+	// (Optional) Type of the patch
+	// Default: StrategicMerge
+	// +optional
+	Type ResourceOverridePatchType `json:"type,omitempty"`
+	// Patch in YAML or JSON. A strategic merge patch is a partial object, a JSON patch
+	// is a list of operations.
+	// +required
+	Patch string `json:"patch"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:resource:categories=all;addons
 // +k8s:deepcopy-gen=true
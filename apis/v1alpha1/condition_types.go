@@ -32,6 +32,14 @@ const (
 	CrdbUIIngressExposedCondition ClusterConditionType = "UIIngressExposed"
 	// CrdbSQLIngressExposedCondition string
 	CrdbSQLIngressExposedCondition ClusterConditionType = "SQLIngressExposed"
+	// CrdbUIRouteExposedCondition string
+	CrdbUIRouteExposedCondition ClusterConditionType = "UIRouteExposed"
+	// CrdbSQLRouteExposedCondition string
+	CrdbSQLRouteExposedCondition ClusterConditionType = "SQLRouteExposed"
 	//ClusterRestartCondition string
 	ClusterRestartCondition ClusterConditionType = "RestartedCluster"
+	// UpgradePausedCondition string
+	UpgradePausedCondition ClusterConditionType = "UpgradePaused"
+	// UpgradeFinalizedCondition string
+	UpgradeFinalizedCondition ClusterConditionType = "UpgradeFinalized"
 )
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// profileDefaults are the values a profile expands into.
+type profileDefaults struct {
+	resources                  v1.ResourceRequirements
+	storage                    string
+	terminationGracePeriodSecs int64
+	maxUnavailable             int32
+	// hostAntiAffinity prefers to schedule the nodes on different hosts
+	hostAntiAffinity bool
+	// zoneSpread spreads the nodes evenly across the zones
+	zoneSpread bool
+}
+
+var productionDefaults = profileDefaults{
+	resources: v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("16Gi")},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("16Gi")},
+	},
+	storage:                    "100Gi",
+	terminationGracePeriodSecs: 300,
+	maxUnavailable:             1,
+	hostAntiAffinity:           true,
+}
+
+var profiles = map[ClusterProfile]profileDefaults{
+	DevProfile: {
+		resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("2Gi")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+		},
+		storage:                    "10Gi",
+		terminationGracePeriodSecs: 60,
+		maxUnavailable:             1,
+	},
+	ProductionProfile: productionDefaults,
+	ProductionMultiAZProfile: func() profileDefaults {
+		d := productionDefaults
+		d.zoneSpread = true
+		return d
+	}(),
+}
+
+// SelectedProfile returns the profile of the cluster, or the profile of the crdb.io/profile
+// annotation when the profile field is not set.
+func (r *CrdbCluster) SelectedProfile() ClusterProfile {
+	if r.Spec.Profile != "" {
+		return r.Spec.Profile
+	}
+	return ClusterProfile(r.Annotations[CrdbProfileAnnotation])
+}
+
+// applyProfile sets the fields of the spec that are not set to the values of the selected profile.
+func (r *CrdbCluster) applyProfile() {
+	defaults, ok := profiles[r.SelectedProfile()]
+	if !ok {
+		return
+	}
+
+	// a resource set in the requests or the limits is not defaulted
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		if _, ok := r.Spec.Resources.Requests[name]; ok {
+			continue
+		}
+		if _, ok := r.Spec.Resources.Limits[name]; ok {
+			continue
+		}
+		if request, ok := defaults.resources.Requests[name]; ok {
+			if r.Spec.Resources.Requests == nil {
+				r.Spec.Resources.Requests = v1.ResourceList{}
+			}
+			r.Spec.Resources.Requests[name] = request
+		}
+		if limit, ok := defaults.resources.Limits[name]; ok {
+			if r.Spec.Resources.Limits == nil {
+				r.Spec.Resources.Limits = v1.ResourceList{}
+			}
+			r.Spec.Resources.Limits[name] = limit
+		}
+	}
+
+	if r.Spec.DataStore.HostPath == nil {
+		if r.Spec.DataStore.VolumeClaim == nil {
+			r.Spec.DataStore.VolumeClaim = &VolumeClaim{PersistentVolumeClaimSpec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			}}
+		}
+		claim := &r.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec
+		if claim.VolumeMode == nil {
+			mode := v1.PersistentVolumeFilesystem
+			claim.VolumeMode = &mode
+		}
+		if _, ok := claim.Resources.Requests[v1.ResourceStorage]; !ok {
+			if claim.Resources.Requests == nil {
+				claim.Resources.Requests = v1.ResourceList{}
+			}
+			claim.Resources.Requests[v1.ResourceStorage] = resource.MustParse(defaults.storage)
+		}
+	}
+
+	if r.Spec.TerminationGracePeriodSecs == 0 {
+		r.Spec.TerminationGracePeriodSecs = defaults.terminationGracePeriodSecs
+	}
+
+	if r.Spec.MaxUnavailable == nil && r.Spec.MinAvailable == nil {
+		maxUnavailable := defaults.maxUnavailable
+		r.Spec.MaxUnavailable = &maxUnavailable
+	}
+
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{
+		"app.kubernetes.io/instance":  r.Name,
+		"app.kubernetes.io/component": "database",
+	}}
+
+	if defaults.hostAntiAffinity && r.Spec.Affinity == nil {
+		r.Spec.Affinity = &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{{
+				Weight: 100,
+				PodAffinityTerm: v1.PodAffinityTerm{
+					LabelSelector: selector,
+					TopologyKey:   v1.LabelHostname,
+				},
+			}},
+		}}
+	}
+
+	if defaults.zoneSpread && len(r.Spec.TopologySpreadConstraints) == 0 {
+		r.Spec.TopologySpreadConstraints = []v1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       v1.LabelTopologyZone,
+			WhenUnsatisfiable: v1.DoNotSchedule,
+			LabelSelector:     selector,
+		}}
+	}
+}
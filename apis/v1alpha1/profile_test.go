@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestDefaultProfile(t *testing.T) {
+	ctx := context.Background()
+	fs := v1.PersistentVolumeFilesystem
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{
+		"app.kubernetes.io/instance":  "crdb",
+		"app.kubernetes.io/component": "database",
+	}}
+
+	t.Run("dev", func(t *testing.T) {
+		cluster := &CrdbCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "crdb"},
+			Spec:       CrdbClusterSpec{Profile: DevProfile},
+		}
+		require.NoError(t, cluster.Default(ctx, cluster))
+
+		require.Equal(t, v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("2Gi")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+		}, cluster.Spec.Resources)
+		require.Equal(t, &VolumeClaim{PersistentVolumeClaimSpec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			VolumeMode:  &fs,
+			Resources: v1.VolumeResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		}}, cluster.Spec.DataStore.VolumeClaim)
+		require.Equal(t, int64(60), cluster.Spec.TerminationGracePeriodSecs)
+		require.Equal(t, int32(1), *cluster.Spec.MaxUnavailable)
+		require.Nil(t, cluster.Spec.Affinity)
+		require.Empty(t, cluster.Spec.TopologySpreadConstraints)
+	})
+
+	t.Run("production-multi-az from the annotation", func(t *testing.T) {
+		cluster := &CrdbCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:        "crdb",
+			Annotations: map[string]string{CrdbProfileAnnotation: string(ProductionMultiAZProfile)},
+		}}
+		require.NoError(t, cluster.Default(ctx, cluster))
+
+		require.Equal(t, resource.MustParse("16Gi"), cluster.Spec.Resources.Limits[v1.ResourceMemory])
+		require.Equal(t, resource.MustParse("4"), cluster.Spec.Resources.Requests[v1.ResourceCPU])
+		require.Equal(t, resource.MustParse("100Gi"),
+			cluster.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.Resources.Requests[v1.ResourceStorage])
+		require.Equal(t, int64(300), cluster.Spec.TerminationGracePeriodSecs)
+		require.Equal(t, []v1.WeightedPodAffinityTerm{{
+			Weight:          100,
+			PodAffinityTerm: v1.PodAffinityTerm{LabelSelector: selector, TopologyKey: v1.LabelHostname},
+		}}, cluster.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+		require.Equal(t, []v1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       v1.LabelTopologyZone,
+			WhenUnsatisfiable: v1.DoNotSchedule,
+			LabelSelector:     selector,
+		}}, cluster.Spec.TopologySpreadConstraints)
+	})
+
+	t.Run("fields set in the spec override the profile", func(t *testing.T) {
+		minAvailable := int32(2)
+		affinity := &v1.Affinity{NodeAffinity: &v1.NodeAffinity{}}
+		cluster := &CrdbCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "crdb",
+				Annotations: map[string]string{CrdbProfileAnnotation: string(DevProfile)},
+			},
+			Spec: CrdbClusterSpec{
+				Profile:                    ProductionProfile,
+				MinAvailable:               &minAvailable,
+				Affinity:                   affinity,
+				TerminationGracePeriodSecs: 600,
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Gi")},
+				},
+				DataStore: Volume{HostPath: &v1.HostPathVolumeSource{Path: "/mnt/crdb"}},
+			},
+		}
+		require.NoError(t, cluster.Default(ctx, cluster))
+
+		require.Equal(t, v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("32Gi")},
+		}, cluster.Spec.Resources)
+		require.Nil(t, cluster.Spec.DataStore.VolumeClaim)
+		require.Nil(t, cluster.Spec.MaxUnavailable)
+		require.Equal(t, affinity, cluster.Spec.Affinity)
+		require.Equal(t, int64(600), cluster.Spec.TerminationGracePeriodSecs)
+	})
+}
+
+func TestValidateProfileAnnotation(t *testing.T) {
+	cluster := &CrdbCluster{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{CrdbProfileAnnotation: "staging"},
+	}}
+	errors, _ := cluster.ValidateSpec()
+	require.Equal(t, []error{
+		field.NotSupported(field.NewPath("metadata", "annotations").Key(CrdbProfileAnnotation), "staging",
+			[]string{"dev", "production", "production-multi-az"}),
+	}, errors)
+
+	cluster.Spec.Profile = DevProfile
+	errors, _ = cluster.ValidateSpec()
+	require.Empty(t, errors)
+}
@@ -47,7 +47,7 @@ func (v *Volume) Apply(name string, container string, path string,
 
 		pvc := corev1.PersistentVolumeClaim{
 			ObjectMeta: metaMutator(v.VolumeClaim.PersistentVolumeSource.ClaimName),
-			Spec:       v.VolumeClaim.PersistentVolumeClaimSpec,
+			Spec:       *v.VolumeClaim.PersistentVolumeClaimSpec.DeepCopy(),
 		}
 
 		spec.VolumeClaimTemplates = append(spec.VolumeClaimTemplates, pvc)
@@ -26,6 +26,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -121,6 +122,30 @@ func TestApply(t *testing.T) {
 				assert.Equal(t, "datadir", claim.Name)
 			},
 		},
+		{
+			name: "PVC requests are not shared with the claim template",
+			sts:  sts.DeepCopy(),
+			vol: api.Volume{
+				VolumeClaim: &api.VolumeClaim{
+					PersistentVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: apiresource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+			assertFn: func(t *testing.T, vol *api.Volume, sts *appsv1.StatefulSetSpec) {
+				require.NoError(t, applyFn(vol, sts))
+				require.Len(t, sts.VolumeClaimTemplates, 1)
+
+				sts.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = apiresource.MustParse("2Gi")
+
+				size := vol.VolumeClaim.PersistentVolumeClaimSpec.Resources.Requests[corev1.ResourceStorage]
+				assert.Equal(t, "1Gi", size.String())
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -17,17 +17,28 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -41,6 +52,11 @@ var (
 	DefaultMaxUnavailable int32 = 1
 )
 
+// VersionChangeValidator validates that a running cluster can move from its current version to the
+// cockroachDBVersion it is updated to. The upgrade rules live with the update actor, so the operator
+// sets it at startup, and the version is not validated when it is nil.
+var VersionChangeValidator func(currentVersion, wantVersion string, multiHop bool) error
+
 var (
 	// log is for logging in this package.
 	webhookLog = logf.Log.WithName("webhooks")
@@ -66,6 +82,13 @@ func (r *CrdbCluster) Default(ctx context.Context, obj runtime.Object) error {
 	r = obj.(*CrdbCluster)
 	webhookLog.Info("default", "name", r.Name)
 
+	// the defaults are set by the operator once the spec is merged with the template
+	if r.Spec.TemplateRef != nil {
+		return nil
+	}
+
+	r.applyProfile()
+
 	if r.Spec.GRPCPort == nil {
 		r.Spec.GRPCPort = &DefaultGRPCPort
 	}
@@ -87,22 +110,51 @@ func (r *CrdbCluster) Default(ctx context.Context, obj runtime.Object) error {
 		r.Spec.Image.PullPolicyName = &policy
 	}
 
+	if r.Spec.Upgrade != nil && r.Spec.Upgrade.Finalize == "" {
+		r.Spec.Upgrade.Finalize = UpgradeFinalizeManual
+	}
+
+	if r.Spec.Gateway != nil && r.Spec.Gateway.SQL != nil && r.Spec.Gateway.SQL.Kind == "" {
+		r.Spec.Gateway.SQL.Kind = TLSRoute
+	}
+
+	if r.Spec.Encryption != nil && r.Spec.Encryption.OldKey == "" {
+		r.Spec.Encryption.OldKey = EncryptionPlainKey
+	}
+
+	for i := range r.Spec.Overrides {
+		if r.Spec.Overrides[i].Type == "" {
+			r.Spec.Overrides[i].Type = StrategicMergeOverride
+		}
+	}
+
 	return nil
 }
 
-// +kubebuilder:webhook:path=/validate-crdb-cockroachlabs-com-v1alpha1-crdbcluster,mutating=false,failurePolicy=fail,groups=crdb.cockroachlabs.com,resources=crdbclusters,verbs=create;update,versions=v1alpha1,name=vcrdbcluster.kb.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-crdb-cockroachlabs-com-v1alpha1-crdbcluster,mutating=false,failurePolicy=fail,groups=crdb.cockroachlabs.com,resources=crdbclusters,verbs=create;update;delete,versions=v1alpha1,name=vcrdbcluster.kb.io,sideEffects=None,admissionReviewVersions=v1
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (r *CrdbCluster) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	r = obj.(*CrdbCluster)
 	webhookLog.Info("validate create", "name", r.Name)
 	var errors []error
+
+	// the spec is validated by the operator once merged with the template
+	if r.Spec.TemplateRef != nil {
+		return nil, r.ValidateTemplateRef()
+	}
 	if r.Spec.Ingress != nil {
 		if err := r.ValidateIngress(); err != nil {
 			errors = append(errors, err...)
 		}
 	}
 
+	if r.Spec.Gateway != nil {
+		if err := r.ValidateGateway(); err != nil {
+			errors = append(errors, err...)
+		}
+	}
+
 	if err := r.ValidateCockroachVersion(); err != nil {
 		errors = append(errors, err)
 	}
@@ -111,11 +163,68 @@ func (r *CrdbCluster) ValidateCreate(ctx context.Context, obj runtime.Object) (a
 		errors = append(errors, err)
 	}
 
+	if err := r.ValidateUpgradeStrategy(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateUpgrade(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateHealthChecks(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidatePodTemplate(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateOverrides(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateServices(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateNetworkPolicy(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateEncryption(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateStores(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateAutoExpand(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateDataSource(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateRetentionPolicy(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateCertificates(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	errors = append(errors, r.ValidateAdditionalArgs()...)
+
+	errs, warnings := r.ValidateSpec()
+	errors = append(errors, errs...)
+
 	if len(errors) != 0 {
-		return nil, kerrors.NewAggregate(errors)
+		return warnings, kerrors.NewAggregate(errors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -127,13 +236,54 @@ func (r *CrdbCluster) ValidateUpdate(ctx context.Context, oldObj runtime.Object,
 	oldCluster, ok := oldObj.(*CrdbCluster)
 	if !ok {
 		webhookLog.Info(fmt.Sprintf("unexpected old cluster type %T", oldObj))
-	} else {
+	} else if r.Spec.TemplateRef == nil {
+		// A cluster that keeps its template is validated by the operator once merged, against the
+		// spec last merged. A cluster that leaves its template is validated against that spec.
+		if merged, err := oldCluster.LastMerged(); err != nil {
+			errors = append(errors, err)
+		} else {
+			oldCluster = merged
+		}
+
 		// Validate if labels changed.
 		// k8s does not support changing selector/labels on sts:
 		//  https://github.com/kubernetes/kubernetes/issues/90519.
 		if !reflect.DeepEqual(oldCluster.Spec.AdditionalLabels, r.Spec.AdditionalLabels) {
 			errors = append(errors, fmt.Errorf("mutating additionalLabels field is not supported"))
 		}
+		// The volume claim templates of a statefulset cannot be changed.
+		if !reflect.DeepEqual(oldCluster.storeVolumeNames(), r.storeVolumeNames()) {
+			errors = append(errors, fmt.Errorf("adding or removing dataStore.stores or dataStore.walFailover is not supported"))
+		}
+		// The nodes cannot open an encrypted store without the keys.
+		if oldCluster.Spec.Encryption != nil && r.Spec.Encryption == nil {
+			errors = append(errors, fmt.Errorf("removing encryption is not supported, set encryption.activeKey to %s to decrypt the store", EncryptionPlainKey))
+		}
+		if err := r.ValidateStorageClassMigration(oldCluster); err != nil {
+			errors = append(errors, err)
+		}
+		// The flags managed by the operator are only rejected when the additional arguments change,
+		// the clusters that already set them can still be updated, e.g. to remove their finalizers.
+		if !reflect.DeepEqual(oldCluster.Spec.AdditionalArgs, r.Spec.AdditionalArgs) {
+			errors = append(errors, r.ValidateAdditionalArgs()...)
+		}
+		if err := r.ValidateTLSMigration(oldCluster); err != nil {
+			errors = append(errors, err)
+		}
+		// The certificate of the CA generated by the operator is not kept to issue certificates again.
+		if oldCluster.Spec.Certificates != nil && oldCluster.Spec.Certificates.CASecret != "" &&
+			(r.Spec.Certificates == nil || r.Spec.Certificates.CASecret == "") {
+			errors = append(errors, fmt.Errorf("removing certificates.caSecret is not supported, the certificates have to be issued from a provided CA"))
+		}
+		errors = append(errors, r.ValidateSpecUpdate(oldCluster)...)
+	}
+
+	// the spec is validated by the operator once merged with the template
+	if r.Spec.TemplateRef != nil {
+		if err := r.ValidateTemplateRef(); err != nil {
+			errors = append(errors, err)
+		}
+		return nil, kerrors.NewAggregate(errors)
 	}
 
 	if r.Spec.Ingress != nil {
@@ -142,15 +292,76 @@ func (r *CrdbCluster) ValidateUpdate(ctx context.Context, oldObj runtime.Object,
 		}
 	}
 
+	if r.Spec.Gateway != nil {
+		if err := r.ValidateGateway(); err != nil {
+			errors = append(errors, err...)
+		}
+	}
+
 	if err := r.ValidateCockroachVersion(); err != nil {
 		errors = append(errors, err)
 	}
 
+	if err := r.ValidateUpgradeStrategy(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateUpgrade(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateHealthChecks(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidatePodTemplate(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateOverrides(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateServices(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateNetworkPolicy(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateEncryption(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateStores(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateAutoExpand(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateDataSource(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := r.ValidateRetentionPolicy(); err != nil {
+		errors = append(errors, err)
+	}
+
+	if err := r.ValidateCertificates(); err != nil {
+		errors = append(errors, err...)
+	}
+
+	errs, warnings := r.ValidateSpec()
+	errors = append(errors, errs...)
+
 	if len(errors) != 0 {
-		return nil, kerrors.NewAggregate(errors)
+		return warnings, kerrors.NewAggregate(errors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -158,10 +369,38 @@ func (r *CrdbCluster) ValidateDelete(ctx context.Context, obj runtime.Object) (a
 	r = obj.(*CrdbCluster)
 	webhookLog.Info("validate delete", "name", r.Name)
 
-	// we're not validating anything on delete. This is just a placeholder for now to satisfy the Validator interface
+	// the template of the cluster can enable the deletion protection
+	protected := r.Spec.DeletionProtection
+	if merged, err := r.LastMerged(); err != nil {
+		webhookLog.Info("failed to read the spec last merged with the template", "name", r.Name, "error", err.Error())
+	} else {
+		protected = protected || merged.Spec.DeletionProtection
+	}
+	if protected {
+		return nil, fmt.Errorf("deletion protection is enabled, disable deletionProtection to delete the cluster")
+	}
+
 	return nil, nil
 }
 
+// ValidateTemplateRef validates that the template of the cluster is named, and its maintenance window.
+func (r *CrdbCluster) ValidateTemplateRef() error {
+	templateRef := field.NewPath("spec", "templateRef")
+	if r.Spec.TemplateRef.Name == "" {
+		return field.Required(templateRef.Child("name"), "the name of the CrdbClusterTemplate is required")
+	}
+	if window := r.Spec.TemplateRef.MaintenanceWindow; window != nil {
+		if _, err := time.Parse("15:04", window.Start); err != nil {
+			return field.Invalid(templateRef.Child("maintenanceWindow", "start"), window.Start, "the start of the window must be HH:MM in UTC")
+		}
+		if window.Duration.Duration <= 0 || window.Duration.Duration > 24*time.Hour {
+			return field.Invalid(templateRef.Child("maintenanceWindow", "duration"), window.Duration.Duration.String(),
+				"the duration of the window must be positive and at most 24h")
+		}
+	}
+	return nil
+}
+
 // ValidateIngress validates the ingress configuration used to create ingress resource
 func (r *CrdbCluster) ValidateIngress() (errors []error) {
 	webhookLog.Info("validate ingress", "name", r.Name)
@@ -178,9 +417,138 @@ func (r *CrdbCluster) ValidateIngress() (errors []error) {
 		errors = append(errors, fmt.Errorf("host required for SQL"))
 	}
 
+	if r.Spec.Ingress.Mode == IngressModeRoute {
+		for i, ingress := range []*Ingress{r.Spec.Ingress.UI, r.Spec.Ingress.SQL} {
+			name := []string{"UI", "SQL"}[i]
+			if ingress == nil || ingress.Host == "" {
+				continue
+			}
+			for _, msg := range validation.IsDNS1123Subdomain(ingress.Host) {
+				errors = append(errors, fmt.Errorf("invalid route host for %s: %s", name, msg))
+			}
+			if len(ingress.TLS) > 0 {
+				errors = append(errors, fmt.Errorf("tls is not supported by the %s route, TLS is passed through to the nodes", name))
+			}
+		}
+		if r.Spec.Ingress.SQL != nil && !r.Spec.TLSEnabled {
+			errors = append(errors, fmt.Errorf("the SQL route requires tlsEnabled"))
+		}
+	}
+
 	return
 }
 
+// ValidateGateway validates the configuration of the Gateway API routes
+func (r *CrdbCluster) ValidateGateway() (errors []error) {
+	webhookLog.Info("validate gateway", "name", r.Name)
+
+	gateway := r.Spec.Gateway
+	if gateway.UI == nil && gateway.SQL == nil {
+		errors = append(errors, fmt.Errorf("at least one of UI or SQL routes must be present"))
+	}
+
+	if gateway.UI != nil {
+		if gateway.UI.ParentRef.Name == "" {
+			errors = append(errors, fmt.Errorf("gateway name required for UI route"))
+		}
+		if r.Spec.Ingress != nil && r.Spec.Ingress.UI != nil {
+			errors = append(errors, fmt.Errorf("UI cannot be exposed with both an ingress and a route"))
+		}
+	}
+
+	if gateway.SQL != nil {
+		if gateway.SQL.ParentRef.Name == "" {
+			errors = append(errors, fmt.Errorf("gateway name required for SQL route"))
+		}
+		if gateway.SQL.Kind == TCPRoute && gateway.SQL.Host != "" {
+			errors = append(errors, fmt.Errorf("host cannot be set for a SQL TCPRoute"))
+		}
+		if gateway.SQL.Kind != TCPRoute && gateway.SQL.Host == "" {
+			errors = append(errors, fmt.Errorf("host required for SQL TLSRoute"))
+		}
+		if r.Spec.Ingress != nil && r.Spec.Ingress.SQL != nil {
+			errors = append(errors, fmt.Errorf("SQL cannot be exposed with both an ingress and a route"))
+		}
+	}
+
+	return
+}
+
+// ValidateNetworkPolicy validates that the ingress controllers can reach a cluster exposed with
+// ingresses, routes or gateway routes
+func (r *CrdbCluster) ValidateNetworkPolicy() error {
+	if r.Spec.NetworkPolicy == nil || len(r.Spec.NetworkPolicy.IngressControllers) > 0 {
+		return nil
+	}
+	if r.Spec.Ingress != nil || r.Spec.Gateway != nil {
+		return fmt.Errorf("networkPolicy.ingressControllers is required to expose the cluster with ingress or gateway")
+	}
+	return nil
+}
+
+// ValidateEncryption validates the Secret and the keys of the store encryption
+func (r *CrdbCluster) ValidateEncryption() (errors []error) {
+	config := r.Spec.Encryption
+	if config == nil {
+		return nil
+	}
+	if config.SecretName == "" {
+		errors = append(errors, fmt.Errorf("encryption.secretName is required"))
+	}
+	if config.ActiveKey == "" {
+		errors = append(errors, fmt.Errorf("encryption.activeKey is required"))
+	} else if config.ActiveKey == config.OldKey && config.ActiveKey != EncryptionPlainKey {
+		errors = append(errors, fmt.Errorf("encryption.oldKey has to be different from the active key %s", config.ActiveKey))
+	}
+	return errors
+}
+
+// ValidateCertificates validates that the certificates are issued by the operator, and the
+// additional IP addresses and the renewal delay of the certificates.
+func (r *CrdbCluster) ValidateCertificates() (errors []error) {
+	config := r.Spec.Certificates
+	if config == nil {
+		return nil
+	}
+	if !r.Spec.TLSEnabled {
+		errors = append(errors, fmt.Errorf("certificates requires tlsEnabled"))
+	}
+	if r.Spec.NodeTLSSecret != "" || r.Spec.ClientTLSSecret != "" {
+		errors = append(errors, fmt.Errorf("certificates cannot be used with nodeTLSSecret or clientTLSSecret"))
+	}
+	for _, ip := range config.IPAddresses {
+		if net.ParseIP(ip) == nil {
+			errors = append(errors, fmt.Errorf("certificates.ipAddresses %q is not a valid IP address", ip))
+		}
+	}
+	if config.RenewBefore != nil && config.RenewBefore.Duration <= 0 {
+		errors = append(errors, fmt.Errorf("certificates.renewBefore must be positive"))
+	}
+
+	secrets := map[string]bool{}
+	for i, c := range config.Clients {
+		if c.User == "" {
+			errors = append(errors, fmt.Errorf("certificates.clients[%d].user is required", i))
+			continue
+		}
+		if strings.EqualFold(c.User, "root") {
+			errors = append(errors, fmt.Errorf("certificates.clients[%d].user cannot be root", i))
+		}
+		namespace, name := c.Namespace, c.SecretName
+		if namespace == "" {
+			namespace = r.Namespace
+		}
+		if name == "" {
+			name = fmt.Sprintf("%s-client-%s", r.Name, c.User)
+		}
+		if secrets[namespace+"/"+name] {
+			errors = append(errors, fmt.Errorf("certificates.clients[%d] uses the secret %s/%s of another client", i, namespace, name))
+		}
+		secrets[namespace+"/"+name] = true
+	}
+	return errors
+}
+
 // ValidateCockroachVersion validates the cockroachdb version or image provided
 func (r *CrdbCluster) ValidateCockroachVersion() error {
 	if r.Spec.CockroachDBVersion == "" && (r.Spec.Image == nil || r.Spec.Image.Name == "") {
@@ -207,3 +575,509 @@ func (r *CrdbCluster) ValidateVolumeMode() error {
 	}
 	return nil
 }
+
+// ValidateStores validates the names and the volume modes of the additional stores and of the
+// WAL failover volume.
+func (r *CrdbCluster) ValidateStores() (errors []error) {
+	validateVolumeMode := func(field string, spec v1.PersistentVolumeClaimSpec) {
+		if spec.VolumeMode != nil && *spec.VolumeMode != v1.PersistentVolumeFilesystem {
+			errors = append(errors, fmt.Errorf("%s.volumeMode must be Filesystem", field))
+		}
+	}
+
+	// the names of the volume claim templates of the first store and of the WAL failover volume
+	names := map[string]bool{"datadir": true, "wal-failover": true}
+	for i, store := range r.Spec.DataStore.Stores {
+		field := fmt.Sprintf("dataStore.stores[%d]", i)
+		if store.Name == "" {
+			errors = append(errors, fmt.Errorf("%s.name is required", field))
+		} else if names[store.Name] {
+			errors = append(errors, fmt.Errorf("%s.name %s is already used", field, store.Name))
+		}
+		names[store.Name] = true
+		validateVolumeMode(field+".spec", store.Spec)
+	}
+
+	if r.Spec.DataStore.WALFailover != nil {
+		validateVolumeMode("dataStore.walFailover.spec", r.Spec.DataStore.WALFailover.Spec)
+	}
+	return errors
+}
+
+// ValidateDataSource validates that the volumes of a clone are restored from a snapshot of another
+// cluster.
+func (r *CrdbCluster) ValidateDataSource() (errors []error) {
+	source := r.Spec.DataStore.DataSource
+	if source == nil {
+		return nil
+	}
+	if r.Spec.DataStore.VolumeClaim == nil {
+		errors = append(errors, fmt.Errorf("dataStore.dataSource requires dataStore.pvc"))
+	}
+	if source.Cluster == "" {
+		errors = append(errors, fmt.Errorf("dataStore.dataSource.cluster is required"))
+	} else if source.Cluster == r.Name {
+		errors = append(errors, fmt.Errorf("dataStore.dataSource.cluster must be another cluster"))
+	}
+	if source.Snapshot == "" {
+		errors = append(errors, fmt.Errorf("dataStore.dataSource.snapshot is required"))
+	}
+	return errors
+}
+
+// ValidateRetentionPolicy validates that the final snapshot is only requested when the persistent
+// volumes are deleted with the cluster.
+func (r *CrdbCluster) ValidateRetentionPolicy() error {
+	if r.Spec.DataStore.FinalSnapshot && r.Spec.DataStore.RetentionPolicy != DeletePolicy {
+		return fmt.Errorf("dataStore.finalSnapshot requires dataStore.retentionPolicy Delete")
+	}
+	return nil
+}
+
+// ValidateAutoExpand validates that the data store is expanded by a positive increment up to a size
+// that is not less than the requested storage. Only the usage of the first store is read, the nodes
+// with several stores are not expanded.
+func (r *CrdbCluster) ValidateAutoExpand() error {
+	config := r.Spec.DataStore.AutoExpand
+	if config == nil {
+		return nil
+	}
+	claim := r.Spec.DataStore.VolumeClaim
+	if claim == nil {
+		return fmt.Errorf("dataStore.autoExpand requires dataStore.pvc")
+	}
+	if len(r.Spec.DataStore.Stores) > 0 {
+		return fmt.Errorf("dataStore.autoExpand is not supported with dataStore.stores")
+	}
+	if config.Increment.Sign() <= 0 {
+		return fmt.Errorf("dataStore.autoExpand.increment must be positive")
+	}
+	if size := claim.PersistentVolumeClaimSpec.Resources.Requests.Storage(); config.MaxSize.Cmp(*size) < 0 {
+		return fmt.Errorf("dataStore.autoExpand.maxSize must not be less than the requested storage %s", size)
+	}
+	return nil
+}
+
+// managedFlags are the flags of the cockroach start command set by the operator, with the field
+// to use instead.
+var managedFlags = map[string]string{
+	"--advertise-addr": "the nodes advertise the address of their pod",
+	"--advertise-host": "the nodes advertise the address of their pod",
+	"--certs-dir":      "use tlsEnabled instead",
+	"--http-addr":      "use httpPort instead",
+	"--http-port":      "use httpPort instead",
+	"--insecure":       "use tlsEnabled instead",
+	"--listen-addr":    "use grpcPort instead",
+	"--sql-addr":       "use sqlPort instead",
+}
+
+// ValidateSpec validates the profile annotation, the number of nodes, the additional arguments and
+// the resources of the nodes. The settings that work but are not recommended are returned as warnings.
+func (r *CrdbCluster) ValidateSpec() (errors []error, warnings admission.Warnings) {
+	spec := field.NewPath("spec")
+
+	if profile, ok := r.Annotations[CrdbProfileAnnotation]; ok && r.Spec.Profile == "" {
+		if _, ok := profiles[ClusterProfile(profile)]; !ok {
+			errors = append(errors, field.NotSupported(field.NewPath("metadata", "annotations").Key(CrdbProfileAnnotation), profile,
+				[]string{string(DevProfile), string(ProductionProfile), string(ProductionMultiAZProfile)}))
+		}
+	}
+
+	if r.Spec.TLSEnabled && r.Spec.Nodes < 3 {
+		warnings = append(warnings, fmt.Sprintf("%s: a cluster of %d nodes does not survive the failure of a node, use at least 3 nodes",
+			spec.Child("nodes"), r.Spec.Nodes))
+	}
+
+	for i, arg := range r.Spec.AdditionalArgs {
+		path := spec.Child("additionalArgs").Index(i)
+		for _, f := range strings.Fields(arg) {
+			if strings.SplitN(f, "=", 2)[0] == "--join" {
+				warnings = append(warnings, fmt.Sprintf("%s: --join replaces the addresses of the nodes the operator joins", path))
+			}
+		}
+	}
+
+	request, hasRequest := r.Spec.Resources.Requests[v1.ResourceMemory]
+	limit, hasLimit := r.Spec.Resources.Limits[v1.ResourceMemory]
+	if hasRequest && hasLimit && request.Cmp(limit) != 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: the memory request %s differs from the limit %s, the nodes can be evicted when the host runs out of memory",
+			spec.Child("resources", "requests", "memory"), request.String(), limit.String()))
+	}
+
+	return errors, warnings
+}
+
+// ValidateAdditionalArgs validates that the additional arguments do not set the flags managed by the
+// operator. It only runs on the updates that change the additional arguments, so that the clusters
+// created before the check can still be updated.
+func (r *CrdbCluster) ValidateAdditionalArgs() (errors []error) {
+	for i, arg := range r.Spec.AdditionalArgs {
+		path := field.NewPath("spec", "additionalArgs").Index(i)
+		for _, f := range strings.Fields(arg) {
+			name := strings.SplitN(f, "=", 2)[0]
+			if detail, ok := managedFlags[name]; ok {
+				errors = append(errors, field.Forbidden(path, fmt.Sprintf("%s is set by the operator, %s", name, detail)))
+			}
+		}
+	}
+	return errors
+}
+
+// ValidateSpecUpdate validates that the persistent volumes do not shrink and that the storage class
+// of the additional stores and of the WAL failover volume does not change. On a running cluster, it
+// also validates that the ports do not change and that the version only changes to a supported
+// version.
+func (r *CrdbCluster) ValidateSpecUpdate(old *CrdbCluster) (errors []error) {
+	spec := field.NewPath("spec")
+	dataStore := spec.Child("dataStore")
+
+	validateClaim := func(path *field.Path, oldSpec, newSpec v1.PersistentVolumeClaimSpec, migrated bool) {
+		oldSize, hasOld := oldSpec.Resources.Requests[v1.ResourceStorage]
+		newSize, hasNew := newSpec.Resources.Requests[v1.ResourceStorage]
+		if hasOld && hasNew && newSize.Cmp(oldSize) < 0 {
+			errors = append(errors, field.Forbidden(path.Child("resources", "requests", "storage"),
+				fmt.Sprintf("persistent volumes cannot shrink from %s to %s", oldSize.String(), newSize.String())))
+		}
+		if !migrated && !reflect.DeepEqual(oldSpec.StorageClassName, newSpec.StorageClassName) {
+			errors = append(errors, field.Forbidden(path.Child("storageClassName"),
+				"the storage class cannot change, only the volumes of dataStore.pvc are migrated to a new storage class"))
+		}
+	}
+
+	if old.Spec.DataStore.VolumeClaim != nil && r.Spec.DataStore.VolumeClaim != nil {
+		validateClaim(dataStore.Child("pvc", "spec"),
+			old.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec, r.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec, true)
+	}
+	for i, store := range r.Spec.DataStore.Stores {
+		for _, oldStore := range old.Spec.DataStore.Stores {
+			if oldStore.Name == store.Name {
+				validateClaim(dataStore.Child("stores").Index(i).Child("spec"), oldStore.Spec, store.Spec, false)
+			}
+		}
+	}
+	if old.Spec.DataStore.WALFailover != nil && r.Spec.DataStore.WALFailover != nil {
+		validateClaim(dataStore.Child("walFailover", "spec"), old.Spec.DataStore.WALFailover.Spec, r.Spec.DataStore.WALFailover.Spec, false)
+	}
+
+	if !old.isInitialized() {
+		return errors
+	}
+
+	for _, port := range []struct {
+		name     string
+		old, new *int32
+	}{
+		{"grpcPort", old.Spec.GRPCPort, r.Spec.GRPCPort},
+		{"sqlPort", old.Spec.SQLPort, r.Spec.SQLPort},
+		{"httpPort", old.Spec.HTTPPort, r.Spec.HTTPPort},
+	} {
+		if port.old != nil && port.new != nil && *port.old != *port.new {
+			errors = append(errors, field.Invalid(spec.Child(port.name), *port.new,
+				fmt.Sprintf("the port of a running cluster cannot change from %d", *port.old)))
+		}
+	}
+
+	if VersionChangeValidator != nil && r.Spec.CockroachDBVersion != "" && r.Spec.CockroachDBVersion != old.Spec.CockroachDBVersion {
+		current := old.Status.Version
+		if current == "" {
+			current = old.Spec.CockroachDBVersion
+		}
+		multiHop := r.Spec.Upgrade != nil && r.Spec.Upgrade.MultiHop
+		if current != "" {
+			if err := VersionChangeValidator(current, r.Spec.CockroachDBVersion, multiHop); err != nil {
+				errors = append(errors, field.Invalid(spec.Child("cockroachDBVersion"), r.Spec.CockroachDBVersion, err.Error()))
+			}
+		}
+	}
+
+	return errors
+}
+
+// isInitialized returns true once the nodes of the cluster have been initialized.
+func (r *CrdbCluster) isInitialized() bool {
+	for _, c := range r.Status.Conditions {
+		if c.Type == CrdbInitializedCondition {
+			return c.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// storeVolumeNames returns the names of the additional stores, and of the WAL failover volume if any.
+func (r *CrdbCluster) storeVolumeNames() []string {
+	var names []string
+	for _, store := range r.Spec.DataStore.Stores {
+		names = append(names, store.Name)
+	}
+	if r.Spec.DataStore.WALFailover != nil {
+		names = append(names, "wal-failover")
+	}
+	return names
+}
+
+// ValidateStorageClassMigration validates that a storage class migration targets an explicit storage
+// class, and that the storage class of the data store and the number of nodes do not change while the
+// nodes are replaced by the migration.
+func (r *CrdbCluster) ValidateStorageClassMigration(old *CrdbCluster) error {
+	if r.Spec.DataStore.VolumeClaim != nil && old.Spec.DataStore.VolumeClaim != nil &&
+		r.storageClassName() == "" && old.storageClassName() != "" {
+		return fmt.Errorf("dataStore.pvc.spec.storageClassName cannot be unset, set it to the name of the storage class to migrate to")
+	}
+
+	migration := old.Status.StorageMigration
+	if migration == nil {
+		return nil
+	}
+	if r.storageClassName() != migration.StorageClass {
+		return fmt.Errorf("dataStore.pvc.spec.storageClassName cannot change while the migration to the storage class %q is in progress", migration.StorageClass)
+	}
+	if r.Spec.Nodes != old.Spec.Nodes {
+		return fmt.Errorf("nodes cannot change while the migration to the storage class %q is in progress", migration.StorageClass)
+	}
+	return nil
+}
+
+// ValidateTLSMigration validates that tlsEnabled only changes when the migration of the running
+// cluster is requested, since insecure and secure nodes cannot be part of the same cluster, and that
+// it does not change again until the migration is complete.
+func (r *CrdbCluster) ValidateTLSMigration(old *CrdbCluster) error {
+	if r.Spec.TLSEnabled == old.Spec.TLSEnabled {
+		return nil
+	}
+	if migration := old.Status.TLSMigration; migration != nil && migration.TLSEnabled != r.Spec.TLSEnabled {
+		return fmt.Errorf("tlsEnabled cannot change while the migration to tlsEnabled %t is in progress", migration.TLSEnabled)
+	}
+	if r.Annotations[CrdbTLSMigrationAnnotation] != "true" {
+		return fmt.Errorf("changing tlsEnabled restarts all the nodes at once, set the %s annotation to \"true\" to migrate the cluster", CrdbTLSMigrationAnnotation)
+	}
+	return nil
+}
+
+// storageClassName returns the storage class of the data store, empty for the default storage class.
+func (r *CrdbCluster) storageClassName() string {
+	if r.Spec.DataStore.VolumeClaim == nil || r.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.StorageClassName == nil {
+		return ""
+	}
+	return *r.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.StorageClassName
+}
+
+// ValidateUpgradeStrategy validates that the canaries leave at least one pod to be upgraded after the soak.
+func (r *CrdbCluster) ValidateUpgradeStrategy() error {
+	strategy := r.Spec.UpgradeStrategy
+	if strategy == nil {
+		return nil
+	}
+	if strategy.Canaries < 0 {
+		return fmt.Errorf("upgradeStrategy.canaries must not be negative")
+	}
+	if strategy.Canaries >= r.Spec.Nodes {
+		return fmt.Errorf("upgradeStrategy.canaries must be less than the number of nodes")
+	}
+	if strategy.SoakDuration != nil && strategy.SoakDuration.Duration < 0 {
+		return fmt.Errorf("upgradeStrategy.soakDuration must not be negative")
+	}
+	return nil
+}
+
+// ValidateUpgrade validates the finalization and multi-hop settings of major upgrades.
+func (r *CrdbCluster) ValidateUpgrade() error {
+	upgrade := r.Spec.Upgrade
+	if upgrade == nil {
+		return nil
+	}
+	if upgrade.AutoFinalizeDelay != nil && upgrade.AutoFinalizeDelay.Duration < 0 {
+		return fmt.Errorf("upgrade.autoFinalizeDelay must not be negative")
+	}
+	if upgrade.MultiHop && r.Spec.CockroachDBVersion == "" {
+		return fmt.Errorf("upgrade.multiHop requires the cockroachDBVersion field to be set")
+	}
+	return nil
+}
+
+// ValidateHealthChecks validates the timings of the health checks and that every check has a unique name.
+func (r *CrdbCluster) ValidateHealthChecks() error {
+	spec := r.Spec.HealthChecks
+	if spec == nil {
+		return nil
+	}
+	if spec.Timeout != nil && spec.Timeout.Duration <= 0 {
+		return fmt.Errorf("healthChecks.timeout must be positive")
+	}
+	if spec.Interval != nil && spec.Interval.Duration <= 0 {
+		return fmt.Errorf("healthChecks.interval must be positive")
+	}
+	if spec.StabilizationDelay != nil && spec.StabilizationDelay.Duration < 0 {
+		return fmt.Errorf("healthChecks.stabilizationDelay must not be negative")
+	}
+
+	names := map[string]bool{}
+	for _, check := range spec.Checks {
+		if check.Type == PrometheusExpressionCheck && check.Expression == "" {
+			return fmt.Errorf("healthChecks.checks of type %s require an expression", PrometheusExpressionCheck)
+		}
+		if names[check.CheckName()] {
+			return fmt.Errorf("healthChecks.checks has more than one check named %q", check.CheckName())
+		}
+		names[check.CheckName()] = true
+	}
+	return nil
+}
+
+// managedVolumes are the volumes of the pods that are managed by the operator.
+var managedVolumes = map[string]bool{"datadir": true, "certs": true, "emptydir": true}
+
+// ValidatePodTemplate validates that the pod template is a valid pod template and that it does not
+// override fields managed by the operator.
+func (r *CrdbCluster) ValidatePodTemplate() error {
+	if r.Spec.PodTemplate == nil || len(r.Spec.PodTemplate.Raw) == 0 {
+		return nil
+	}
+
+	template := v1.PodTemplateSpec{}
+	decoder := json.NewDecoder(bytes.NewReader(r.Spec.PodTemplate.Raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&template); err != nil {
+		return fmt.Errorf("podTemplate is not a valid pod template: %v", err)
+	}
+
+	var managed []string
+	for key := range template.Labels {
+		if strings.HasPrefix(key, "app.kubernetes.io/") {
+			managed = append(managed, "metadata.labels."+key)
+		}
+	}
+	for key := range template.Annotations {
+		if strings.HasPrefix(key, "crdb.io/") {
+			managed = append(managed, "metadata.annotations."+key)
+		}
+	}
+
+	spec := template.Spec
+	for field, set := range map[string]bool{
+		"spec.serviceAccountName":            spec.ServiceAccountName != "",
+		"spec.automountServiceAccountToken":  spec.AutomountServiceAccountToken != nil,
+		"spec.terminationGracePeriodSeconds": spec.TerminationGracePeriodSeconds != nil,
+		"spec.affinity":                      spec.Affinity != nil,
+		"spec.tolerations":                   len(spec.Tolerations) > 0,
+		"spec.topologySpreadConstraints":     len(spec.TopologySpreadConstraints) > 0,
+		"spec.nodeSelector":                  len(spec.NodeSelector) > 0,
+		"spec.imagePullSecrets":              len(spec.ImagePullSecrets) > 0,
+		"spec.priorityClassName":             spec.PriorityClassName != "",
+	} {
+		if set {
+			managed = append(managed, field)
+		}
+	}
+
+	for _, volume := range spec.Volumes {
+		if managedVolumes[volume.Name] {
+			managed = append(managed, fmt.Sprintf("spec.volumes[%s]", volume.Name))
+		}
+	}
+
+	for _, container := range spec.Containers {
+		if container.Name != "db" {
+			continue
+		}
+		path := "spec.containers[db]."
+		for field, set := range map[string]bool{
+			"image":           container.Image != "",
+			"imagePullPolicy": container.ImagePullPolicy != "",
+			"command":         len(container.Command) > 0,
+			"args":            len(container.Args) > 0,
+			"ports":           len(container.Ports) > 0,
+			"env":             len(container.Env) > 0,
+			"resources":       len(container.Resources.Limits) > 0 || len(container.Resources.Requests) > 0,
+			"readinessProbe":  container.ReadinessProbe != nil,
+			"lifecycle":       container.Lifecycle != nil,
+		} {
+			if set {
+				managed = append(managed, path+field)
+			}
+		}
+		for _, mount := range container.VolumeMounts {
+			if managedVolumes[mount.Name] {
+				managed = append(managed, fmt.Sprintf("%svolumeMounts[%s]", path, mount.Name))
+			}
+		}
+	}
+
+	for _, container := range spec.InitContainers {
+		if container.Name == "db-init" {
+			managed = append(managed, "spec.initContainers[db-init]")
+		}
+	}
+
+	if len(managed) > 0 {
+		sort.Strings(managed)
+		return fmt.Errorf("podTemplate overrides fields managed by the operator: %s", strings.Join(managed, ", "))
+	}
+	return nil
+}
+
+// ValidateOverrides validates that every override has a patch of its type.
+func (r *CrdbCluster) ValidateOverrides() (errors []error) {
+	for i, override := range r.Spec.Overrides {
+		field := fmt.Sprintf("overrides[%d]", i)
+		if override.Kind == "" || override.Name == "" {
+			errors = append(errors, fmt.Errorf("%s requires a kind and a name", field))
+			continue
+		}
+
+		patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%s.patch is not valid YAML or JSON: %v", field, err))
+			continue
+		}
+
+		switch override.Type {
+		case JSONOverride:
+			if _, err := jsonpatch.DecodePatch(patch); err != nil {
+				errors = append(errors, fmt.Errorf("%s.patch is not a JSON patch: %v", field, err))
+			}
+		default:
+			var object map[string]interface{}
+			if err := json.Unmarshal(patch, &object); err != nil || object == nil {
+				errors = append(errors, fmt.Errorf("%s.patch is not a strategic merge patch, it has to be an object", field))
+			}
+		}
+	}
+	return errors
+}
+
+// ValidateServices validates that the settings of each service match its type.
+func (r *CrdbCluster) ValidateServices() (errors []error) {
+	if r.Spec.Services == nil || r.Spec.Services.Public == nil {
+		return nil
+	}
+
+	public := r.Spec.Services.Public
+	for field, config := range map[string]*ServiceConfig{
+		"services.public":     &public.ServiceConfig,
+		"services.public.sql": public.SQL,
+		"services.public.ui":  public.UI,
+	} {
+		if config == nil {
+			continue
+		}
+		if config.Type != v1.ServiceTypeLoadBalancer {
+			if config.LoadBalancerClass != nil {
+				errors = append(errors, fmt.Errorf("%s.loadBalancerClass requires the LoadBalancer type", field))
+			}
+			if len(config.LoadBalancerSourceRanges) > 0 {
+				errors = append(errors, fmt.Errorf("%s.loadBalancerSourceRanges requires the LoadBalancer type", field))
+			}
+		}
+		if config.ExternalTrafficPolicy != "" && config.Type != v1.ServiceTypeLoadBalancer && config.Type != v1.ServiceTypeNodePort {
+			errors = append(errors, fmt.Errorf("%s.externalTrafficPolicy requires the NodePort or LoadBalancer type", field))
+		}
+		for _, cidr := range config.LoadBalancerSourceRanges {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				errors = append(errors, fmt.Errorf("%s.loadBalancerSourceRanges contains an invalid CIDR %q", field, cidr))
+			}
+		}
+	}
+
+	sort.Slice(errors, func(i, j int) bool { return errors[i].Error() < errors[j].Error() })
+	return errors
+}
@@ -20,10 +20,17 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func TestCrdbClusterDefault(t *testing.T) {
@@ -64,6 +71,36 @@ func TestValidateIngress(t *testing.T) {
 			},
 			expected: []error{fmt.Errorf("host required for UI")},
 		},
+		{
+			name: "route hosts are validated",
+			cluster: &CrdbCluster{
+				Spec: CrdbClusterSpec{
+					Ingress: &IngressConfig{
+						Mode: IngressModeRoute,
+						UI:   &Ingress{Host: "*.test.com", TLS: []networkingv1.IngressTLS{{SecretName: "ui"}}},
+						SQL:  &Ingress{Host: "sql.test.com"},
+					},
+				},
+			},
+			expected: []error{
+				fmt.Errorf("invalid route host for UI: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')"),
+				fmt.Errorf("tls is not supported by the UI route, TLS is passed through to the nodes"),
+				fmt.Errorf("the SQL route requires tlsEnabled"),
+			},
+		},
+		{
+			name: "valid routes",
+			cluster: &CrdbCluster{
+				Spec: CrdbClusterSpec{
+					TLSEnabled: true,
+					Ingress: &IngressConfig{
+						Mode: IngressModeRoute,
+						UI:   &Ingress{Host: "ui.test.com"},
+						SQL:  &Ingress{Host: "sql.test.com"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -272,3 +309,552 @@ func TestUpdateCrdbClusterLabels(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateUpgradeStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy *UpgradeStrategy
+		errMsg   string
+	}{
+		{
+			name: "no upgrade strategy",
+		},
+		{
+			name:     "canaries and soak duration",
+			strategy: &UpgradeStrategy{Canaries: 1, SoakDuration: &metav1.Duration{Duration: time.Minute}},
+		},
+		{
+			name:     "every node is a canary",
+			strategy: &UpgradeStrategy{Canaries: 3},
+			errMsg:   "upgradeStrategy.canaries must be less than the number of nodes",
+		},
+		{
+			name:     "negative soak duration",
+			strategy: &UpgradeStrategy{Canaries: 1, SoakDuration: &metav1.Duration{Duration: -time.Minute}},
+			errMsg:   "upgradeStrategy.soakDuration must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &CrdbCluster{Spec: CrdbClusterSpec{Nodes: 3, UpgradeStrategy: tt.strategy}}
+			err := cluster.ValidateUpgradeStrategy()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestValidateUpgrade(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{Upgrade: &UpgradeSpec{}}}
+	require.NoError(t, cluster.Default(context.Background(), cluster))
+	require.Equal(t, UpgradeFinalizeManual, cluster.Spec.Upgrade.Finalize)
+	require.NoError(t, cluster.ValidateUpgrade())
+
+	cluster.Spec.Upgrade = &UpgradeSpec{
+		Finalize:          UpgradeFinalizeAuto,
+		AutoFinalizeDelay: &metav1.Duration{Duration: -time.Hour},
+	}
+	require.EqualError(t, cluster.ValidateUpgrade(), "upgrade.autoFinalizeDelay must not be negative")
+
+	// the upgrade plan is built from the supported versions
+	cluster.Spec.Upgrade = &UpgradeSpec{MultiHop: true}
+	cluster.Spec.Image = &PodImage{Name: "cockroachdb/cockroach:v24.1.5"}
+	require.EqualError(t, cluster.ValidateUpgrade(), "upgrade.multiHop requires the cockroachDBVersion field to be set")
+	cluster.Spec.Image = nil
+	cluster.Spec.CockroachDBVersion = "v24.1.5"
+	require.NoError(t, cluster.ValidateUpgrade())
+}
+
+func TestValidateHealthChecks(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   *HealthCheckSpec
+		errMsg string
+	}{
+		{
+			name: "no health checks",
+		},
+		{
+			name: "checks and timings",
+			spec: &HealthCheckSpec{
+				Checks: []HealthCheck{
+					{Type: UnavailableRangesCheck},
+					{Type: PrometheusExpressionCheck, Expression: "ranges_underreplicated == 0"},
+					{Type: PrometheusExpressionCheck, Expression: "ranges_underreplicated == 0", Name: "replication"},
+				},
+				Timeout:            &metav1.Duration{Duration: 5 * time.Minute},
+				StabilizationDelay: &metav1.Duration{},
+			},
+		},
+		{
+			name:   "zero interval",
+			spec:   &HealthCheckSpec{Interval: &metav1.Duration{}},
+			errMsg: "healthChecks.interval must be positive",
+		},
+		{
+			name:   "missing expression",
+			spec:   &HealthCheckSpec{Checks: []HealthCheck{{Type: PrometheusExpressionCheck}}},
+			errMsg: "healthChecks.checks of type PrometheusExpression require an expression",
+		},
+		{
+			name:   "duplicate names",
+			spec:   &HealthCheckSpec{Checks: []HealthCheck{{Type: SQLCheck}, {Type: SQLCheck}}},
+			errMsg: `healthChecks.checks has more than one check named "SQL"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &CrdbCluster{Spec: CrdbClusterSpec{HealthChecks: tt.spec}}
+			err := cluster.ValidateHealthChecks()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestValidatePodTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		errMsg   string
+	}{
+		{
+			name: "no pod template",
+		},
+		{
+			name: "sidecar, volume and envFrom",
+			template: `{"metadata":{"annotations":{"vault.hashicorp.com/agent-inject":"true"}},
+				"spec":{"securityContext":{"runAsNonRoot":true},
+				"containers":[{"name":"db","envFrom":[{"secretRef":{"name":"env"}}]},{"name":"sidecar","image":"busybox"}],
+				"volumes":[{"name":"extra","configMap":{"name":"extra"}}]}}`,
+		},
+		{
+			name:     "unknown field",
+			template: `{"spec":{"sidecars":[]}}`,
+			errMsg:   `podTemplate is not a valid pod template: json: unknown field "sidecars"`,
+		},
+		{
+			name: "managed fields",
+			template: `{"metadata":{"labels":{"app.kubernetes.io/name":"other"}},
+				"spec":{"serviceAccountName":"other","containers":[{"name":"db","image":"other",
+				"volumeMounts":[{"name":"datadir","mountPath":"/data"}]}],"initContainers":[{"name":"db-init"}]}}`,
+			errMsg: "podTemplate overrides fields managed by the operator: metadata.labels.app.kubernetes.io/name, " +
+				"spec.containers[db].image, spec.containers[db].volumeMounts[datadir], spec.initContainers[db-init], " +
+				"spec.serviceAccountName",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &CrdbCluster{}
+			if tt.template != "" {
+				cluster.Spec.PodTemplate = &runtime.RawExtension{Raw: []byte(tt.template)}
+			}
+			err := cluster.ValidatePodTemplate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{Overrides: []ResourceOverride{
+		{Kind: "Service", Name: "crdb-public", Patch: "spec:\n  type: LoadBalancer\n"},
+		{Kind: "StatefulSet", Name: "crdb", Type: JSONOverride, Patch: `[{"op": "add", "path": "/metadata/labels/team", "value": "db"}]`},
+	}}}
+	require.NoError(t, cluster.Default(context.Background(), cluster))
+	require.Equal(t, StrategicMergeOverride, cluster.Spec.Overrides[0].Type)
+	require.Empty(t, cluster.ValidateOverrides())
+
+	cluster.Spec.Overrides = []ResourceOverride{
+		{Kind: "Service", Patch: "spec: {}"},
+		{Kind: "Service", Name: "crdb", Type: StrategicMergeOverride, Patch: "- op: remove"},
+		{Kind: "Service", Name: "crdb", Type: JSONOverride, Patch: "spec: {}"},
+	}
+	errs := cluster.ValidateOverrides()
+	require.Len(t, errs, 3)
+	require.EqualError(t, errs[0], "overrides[0] requires a kind and a name")
+	require.EqualError(t, errs[1], "overrides[1].patch is not a strategic merge patch, it has to be an object")
+	require.Contains(t, errs[2].Error(), "overrides[2].patch is not a JSON patch")
+}
+
+func TestValidateServices(t *testing.T) {
+	class := "internal"
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{Services: &ServicesConfig{Public: &PublicServiceConfig{
+		ServiceConfig: ServiceConfig{Type: v1.ServiceTypeNodePort, ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal},
+		SQL: &ServiceConfig{
+			Type:                     v1.ServiceTypeLoadBalancer,
+			LoadBalancerClass:        &class,
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+		},
+	}}}}
+	require.Empty(t, cluster.ValidateServices())
+
+	cluster.Spec.Services.Public.ServiceConfig = ServiceConfig{
+		LoadBalancerClass:     &class,
+		ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+	}
+	cluster.Spec.Services.Public.SQL.LoadBalancerSourceRanges = []string{"10.0.0.0"}
+	errs := cluster.ValidateServices()
+	require.Len(t, errs, 3)
+	require.EqualError(t, errs[0], "services.public.externalTrafficPolicy requires the NodePort or LoadBalancer type")
+	require.EqualError(t, errs[1], "services.public.loadBalancerClass requires the LoadBalancer type")
+	require.EqualError(t, errs[2], `services.public.sql.loadBalancerSourceRanges contains an invalid CIDR "10.0.0.0"`)
+}
+
+func TestValidateGateway(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{Gateway: &GatewayConfig{
+		UI:  &GatewayRoute{ParentRef: GatewayParentReference{Name: "gateway"}},
+		SQL: &GatewaySQLRoute{GatewayRoute: GatewayRoute{ParentRef: GatewayParentReference{Name: "gateway"}, Host: "sql.test.com"}},
+	}}}
+	require.NoError(t, cluster.Default(context.Background(), cluster))
+	require.Equal(t, TLSRoute, cluster.Spec.Gateway.SQL.Kind)
+	require.Empty(t, cluster.ValidateGateway())
+
+	cluster.Spec.Gateway.SQL.Kind = TCPRoute
+	require.Equal(t, []error{fmt.Errorf("host cannot be set for a SQL TCPRoute")}, cluster.ValidateGateway())
+
+	cluster.Spec.Gateway.SQL = &GatewaySQLRoute{Kind: TLSRoute}
+	cluster.Spec.Ingress = &IngressConfig{SQL: &Ingress{Host: "sql.test.com"}}
+	require.Equal(t, []error{
+		fmt.Errorf("gateway name required for SQL route"),
+		fmt.Errorf("host required for SQL TLSRoute"),
+		fmt.Errorf("SQL cannot be exposed with both an ingress and a route"),
+	}, cluster.ValidateGateway())
+
+	cluster.Spec.Gateway = &GatewayConfig{}
+	require.Equal(t, []error{fmt.Errorf("at least one of UI or SQL routes must be present")}, cluster.ValidateGateway())
+}
+
+func TestValidateNetworkPolicy(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{
+		NetworkPolicy: &NetworkPolicyConfig{},
+		Ingress:       &IngressConfig{UI: &Ingress{Host: "ui.test.com"}},
+	}}
+	require.EqualError(t, cluster.ValidateNetworkPolicy(),
+		"networkPolicy.ingressControllers is required to expose the cluster with ingress or gateway")
+
+	cluster.Spec.NetworkPolicy.IngressControllers = []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"}},
+	}}
+	require.NoError(t, cluster.ValidateNetworkPolicy())
+
+	cluster.Spec.Ingress = nil
+	cluster.Spec.NetworkPolicy.IngressControllers = nil
+	require.NoError(t, cluster.ValidateNetworkPolicy())
+}
+
+func TestValidateEncryption(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{Encryption: &EncryptionConfig{}}}
+	require.Equal(t, []error{
+		fmt.Errorf("encryption.secretName is required"),
+		fmt.Errorf("encryption.activeKey is required"),
+	}, cluster.ValidateEncryption())
+
+	cluster.Spec.Encryption = &EncryptionConfig{SecretName: "store-keys", ActiveKey: "aes-128.key", OldKey: "aes-128.key"}
+	require.Equal(t, []error{
+		fmt.Errorf("encryption.oldKey has to be different from the active key aes-128.key"),
+	}, cluster.ValidateEncryption())
+
+	cluster.Spec.Encryption.OldKey = EncryptionPlainKey
+	require.Empty(t, cluster.ValidateEncryption())
+
+	cluster.Spec.Encryption.ActiveKey = EncryptionPlainKey
+	require.Empty(t, cluster.ValidateEncryption())
+}
+
+func TestValidateCertificates(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{
+		NodeTLSSecret: "node-certs",
+		Certificates: &CertificatesConfig{
+			CASecret:    "corporate-ca",
+			IPAddresses: []string{"10.0.0.1", "cockroachdb.example.com"},
+			RenewBefore: &metav1.Duration{},
+		},
+	}}
+	require.Equal(t, []error{
+		fmt.Errorf("certificates requires tlsEnabled"),
+		fmt.Errorf("certificates cannot be used with nodeTLSSecret or clientTLSSecret"),
+		fmt.Errorf(`certificates.ipAddresses "cockroachdb.example.com" is not a valid IP address`),
+		fmt.Errorf("certificates.renewBefore must be positive"),
+	}, cluster.ValidateCertificates())
+
+	cluster.Spec.TLSEnabled = true
+	cluster.Spec.NodeTLSSecret = ""
+	cluster.Spec.Certificates.IPAddresses = []string{"10.0.0.1", "fd00::1"}
+	cluster.Spec.Certificates.RenewBefore = &metav1.Duration{Duration: 24 * time.Hour}
+	require.Empty(t, cluster.ValidateCertificates())
+
+	cluster.ObjectMeta = metav1.ObjectMeta{Name: "cockroachdb", Namespace: "db"}
+	cluster.Spec.Certificates.Clients = []ClientCertificate{
+		{User: "app"},
+		{},
+		{User: "reporting", SecretName: "cockroachdb-client-app"},
+		{User: "app", Namespace: "app"},
+		{User: "root", SecretName: "root"},
+	}
+	require.Equal(t, []error{
+		fmt.Errorf("certificates.clients[1].user is required"),
+		fmt.Errorf("certificates.clients[2] uses the secret db/cockroachdb-client-app of another client"),
+		fmt.Errorf("certificates.clients[4].user cannot be root"),
+	}, cluster.ValidateCertificates())
+}
+
+func TestValidateStores(t *testing.T) {
+	block := v1.PersistentVolumeBlock
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{DataStore: Volume{
+		Stores: []Store{
+			{Name: "store-1"},
+			{Name: "store-1"},
+			{Name: "datadir"},
+			{},
+		},
+		WALFailover: &WALFailoverVolume{Spec: v1.PersistentVolumeClaimSpec{VolumeMode: &block}},
+	}}}
+	require.Equal(t, []error{
+		fmt.Errorf("dataStore.stores[1].name store-1 is already used"),
+		fmt.Errorf("dataStore.stores[2].name datadir is already used"),
+		fmt.Errorf("dataStore.stores[3].name is required"),
+		fmt.Errorf("dataStore.walFailover.spec.volumeMode must be Filesystem"),
+	}, cluster.ValidateStores())
+
+	cluster.Spec.DataStore.Stores = []Store{{Name: "store-1"}, {Name: "store-2"}}
+	cluster.Spec.DataStore.WALFailover = &WALFailoverVolume{}
+	require.Empty(t, cluster.ValidateStores())
+}
+
+func TestValidateAutoExpand(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{DataStore: Volume{
+		AutoExpand: &AutoExpandConfig{},
+	}}}
+	require.EqualError(t, cluster.ValidateAutoExpand(), "dataStore.autoExpand requires dataStore.pvc")
+
+	cluster.Spec.DataStore.VolumeClaim = &VolumeClaim{PersistentVolumeClaimSpec: v1.PersistentVolumeClaimSpec{
+		Resources: v1.VolumeResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+		},
+	}}
+	require.EqualError(t, cluster.ValidateAutoExpand(), "dataStore.autoExpand.increment must be positive")
+
+	cluster.Spec.DataStore.AutoExpand.Increment = resource.MustParse("5Gi")
+	cluster.Spec.DataStore.AutoExpand.MaxSize = resource.MustParse("5Gi")
+	require.EqualError(t, cluster.ValidateAutoExpand(),
+		"dataStore.autoExpand.maxSize must not be less than the requested storage 10Gi")
+
+	cluster.Spec.DataStore.AutoExpand.MaxSize = resource.MustParse("100Gi")
+	require.NoError(t, cluster.ValidateAutoExpand())
+
+	cluster.Spec.DataStore.Stores = []Store{{Name: "store-2"}}
+	require.EqualError(t, cluster.ValidateAutoExpand(), "dataStore.autoExpand is not supported with dataStore.stores")
+}
+
+func TestValidateStorageClassMigration(t *testing.T) {
+	standard, ssd := "standard", "ssd"
+	old := &CrdbCluster{Spec: CrdbClusterSpec{Nodes: 3, DataStore: Volume{
+		VolumeClaim: &VolumeClaim{PersistentVolumeClaimSpec: v1.PersistentVolumeClaimSpec{StorageClassName: &standard}},
+	}}}
+	cluster := old.DeepCopy()
+	cluster.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.StorageClassName = &ssd
+	require.NoError(t, cluster.ValidateStorageClassMigration(old))
+
+	old = cluster.DeepCopy()
+	old.Status.StorageMigration = &StorageMigrationStatus{StorageClass: ssd, Node: 1}
+	require.NoError(t, cluster.ValidateStorageClassMigration(old))
+
+	cluster.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.StorageClassName = &standard
+	require.EqualError(t, cluster.ValidateStorageClassMigration(old),
+		`dataStore.pvc.spec.storageClassName cannot change while the migration to the storage class "ssd" is in progress`)
+
+	cluster.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.StorageClassName = &ssd
+	cluster.Spec.Nodes = 4
+	require.EqualError(t, cluster.ValidateStorageClassMigration(old),
+		`nodes cannot change while the migration to the storage class "ssd" is in progress`)
+
+	cluster.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.StorageClassName = nil
+	require.EqualError(t, cluster.ValidateStorageClassMigration(old),
+		"dataStore.pvc.spec.storageClassName cannot be unset, set it to the name of the storage class to migrate to")
+}
+
+func TestValidateTLSMigration(t *testing.T) {
+	old := &CrdbCluster{Spec: CrdbClusterSpec{Nodes: 3}}
+	cluster := old.DeepCopy()
+	require.NoError(t, cluster.ValidateTLSMigration(old))
+
+	cluster.Spec.TLSEnabled = true
+	require.EqualError(t, cluster.ValidateTLSMigration(old),
+		`changing tlsEnabled restarts all the nodes at once, set the crdb.io/tls-migration annotation to "true" to migrate the cluster`)
+
+	cluster.Annotations = map[string]string{CrdbTLSMigrationAnnotation: "true"}
+	require.NoError(t, cluster.ValidateTLSMigration(old))
+
+	old = cluster.DeepCopy()
+	old.Status.TLSMigration = &TLSMigrationStatus{TLSEnabled: true}
+	cluster.Spec.TLSEnabled = false
+	require.EqualError(t, cluster.ValidateTLSMigration(old),
+		"tlsEnabled cannot change while the migration to tlsEnabled true is in progress")
+}
+
+func TestValidateDataSource(t *testing.T) {
+	cluster := &CrdbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+		Spec: CrdbClusterSpec{DataStore: Volume{
+			DataSource: &SnapshotSource{Cluster: "staging"},
+		}},
+	}
+	require.Equal(t, []error{
+		fmt.Errorf("dataStore.dataSource requires dataStore.pvc"),
+		fmt.Errorf("dataStore.dataSource.cluster must be another cluster"),
+		fmt.Errorf("dataStore.dataSource.snapshot is required"),
+	}, cluster.ValidateDataSource())
+
+	cluster.Spec.DataStore.VolumeClaim = &VolumeClaim{}
+	cluster.Spec.DataStore.DataSource = &SnapshotSource{Cluster: "production", Snapshot: "nightly"}
+	require.Empty(t, cluster.ValidateDataSource())
+}
+
+func TestValidateRetentionPolicy(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{DataStore: Volume{FinalSnapshot: true}}}
+	require.Equal(t, fmt.Errorf("dataStore.finalSnapshot requires dataStore.retentionPolicy Delete"),
+		cluster.ValidateRetentionPolicy())
+
+	cluster.Spec.DataStore.RetentionPolicy = DeletePolicy
+	require.NoError(t, cluster.ValidateRetentionPolicy())
+}
+
+func TestValidateDelete(t *testing.T) {
+	ctx := context.Background()
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{DeletionProtection: true}}
+	_, err := cluster.ValidateDelete(ctx, cluster)
+	require.Error(t, err)
+
+	cluster.Spec.DeletionProtection = false
+	_, err = cluster.ValidateDelete(ctx, cluster)
+	require.NoError(t, err)
+}
+
+func TestValidateSpec(t *testing.T) {
+	cluster := &CrdbCluster{Spec: CrdbClusterSpec{
+		Nodes:          1,
+		TLSEnabled:     true,
+		AdditionalArgs: []string{"--cache=25%", "--listen-addr=:26259 --certs-dir=/certs", "--join=other-cluster:26258"},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+		},
+	}}
+
+	require.Equal(t, []error{
+		field.Forbidden(field.NewPath("spec", "additionalArgs").Index(1), "--listen-addr is set by the operator, use grpcPort instead"),
+		field.Forbidden(field.NewPath("spec", "additionalArgs").Index(1), "--certs-dir is set by the operator, use tlsEnabled instead"),
+	}, cluster.ValidateAdditionalArgs())
+
+	errors, warnings := cluster.ValidateSpec()
+	require.Empty(t, errors)
+	require.Equal(t, admission.Warnings{
+		"spec.nodes: a cluster of 1 nodes does not survive the failure of a node, use at least 3 nodes",
+		"spec.additionalArgs[2]: --join replaces the addresses of the nodes the operator joins",
+		"spec.resources.requests.memory: the memory request 2Gi differs from the limit 4Gi, the nodes can be evicted when the host runs out of memory",
+	}, warnings)
+
+	cluster.Spec.Nodes = 3
+	cluster.Spec.AdditionalArgs = []string{"--cache=25%"}
+	cluster.Spec.Resources.Requests[v1.ResourceMemory] = resource.MustParse("4Gi")
+	errors, warnings = cluster.ValidateSpec()
+	require.Empty(t, errors)
+	require.Empty(t, warnings)
+	require.Empty(t, cluster.ValidateAdditionalArgs())
+}
+
+func TestValidateUpdateManagedFlags(t *testing.T) {
+	old := &CrdbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "crdb", Finalizers: []string{"crdb.io/finalizer"}},
+		Spec: CrdbClusterSpec{
+			Nodes:              3,
+			CockroachDBVersion: "v23.1.0",
+			AdditionalArgs:     []string{"--listen-addr=:26259"},
+		},
+	}
+
+	// the clusters created with a managed flag can still be updated
+	cluster := old.DeepCopy()
+	cluster.Finalizers = nil
+	_, err := cluster.ValidateUpdate(context.Background(), old, cluster)
+	require.NoError(t, err)
+
+	// the managed flags are rejected when the additional arguments change
+	cluster.Spec.AdditionalArgs = []string{"--listen-addr=:26259", "--cache=25%"}
+	_, err = cluster.ValidateUpdate(context.Background(), old, cluster)
+	require.ErrorContains(t, err, "--listen-addr is set by the operator")
+}
+
+func TestValidateSpecUpdate(t *testing.T) {
+	defer func(validator func(string, string, bool) error) { VersionChangeValidator = validator }(VersionChangeValidator)
+	VersionChangeValidator = func(current, want string, multiHop bool) error {
+		if multiHop {
+			return nil
+		}
+		return fmt.Errorf("can not upgrade from %s to %s in one step", current, want)
+	}
+
+	standard, ssd := "standard", "ssd"
+	claim := func(size string, class *string) v1.PersistentVolumeClaimSpec {
+		return v1.PersistentVolumeClaimSpec{
+			StorageClassName: class,
+			Resources: v1.VolumeResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(size)},
+			},
+		}
+	}
+	port := func(p int32) *int32 { return &p }
+
+	old := &CrdbCluster{Spec: CrdbClusterSpec{
+		CockroachDBVersion: "v23.1.3",
+		SQLPort:            port(26257),
+		DataStore: Volume{
+			VolumeClaim: &VolumeClaim{PersistentVolumeClaimSpec: claim("100Gi", &standard)},
+			Stores:      []Store{{Name: "store-1", Spec: claim("100Gi", &standard)}},
+			WALFailover: &WALFailoverVolume{Spec: claim("10Gi", &standard)},
+		},
+	}}
+
+	cluster := old.DeepCopy()
+	cluster.Spec.CockroachDBVersion = "v24.1.0"
+	cluster.Spec.SQLPort = port(26000)
+	cluster.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec = claim("50Gi", &ssd)
+	cluster.Spec.DataStore.Stores[0].Spec = claim("200Gi", &ssd)
+	cluster.Spec.DataStore.WALFailover.Spec = claim("5Gi", &standard)
+
+	storageErrors := []error{
+		field.Forbidden(field.NewPath("spec", "dataStore", "pvc", "spec", "resources", "requests", "storage"),
+			"persistent volumes cannot shrink from 100Gi to 50Gi"),
+		field.Forbidden(field.NewPath("spec", "dataStore", "stores").Index(0).Child("spec", "storageClassName"),
+			"the storage class cannot change, only the volumes of dataStore.pvc are migrated to a new storage class"),
+		field.Forbidden(field.NewPath("spec", "dataStore", "walFailover", "spec", "resources", "requests", "storage"),
+			"persistent volumes cannot shrink from 10Gi to 5Gi"),
+	}
+	require.Equal(t, storageErrors, cluster.ValidateSpecUpdate(old))
+
+	// the ports and the version of a running cluster are validated
+	old.Status.Conditions = []ClusterCondition{{Type: CrdbInitializedCondition, Status: metav1.ConditionTrue}}
+	require.Equal(t, append(storageErrors,
+		field.Invalid(field.NewPath("spec", "sqlPort"), int32(26000), "the port of a running cluster cannot change from 26257"),
+		field.Invalid(field.NewPath("spec", "cockroachDBVersion"), "v24.1.0", "can not upgrade from v23.1.3 to v24.1.0 in one step"),
+	), cluster.ValidateSpecUpdate(old))
+
+	cluster = old.DeepCopy()
+	cluster.Spec.CockroachDBVersion = "v24.1.0"
+	cluster.Spec.Upgrade = &UpgradeSpec{MultiHop: true}
+	cluster.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec = claim("200Gi", &ssd)
+	require.Empty(t, cluster.ValidateSpecUpdate(old))
+}
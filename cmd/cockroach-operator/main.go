@@ -20,9 +20,11 @@ import (
 	"flag"
 	"os"
 	"strings"
+	"time"
 
 	crdbv1alpha1 "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/controller"
+	"github.com/cockroachdb/cockroach-operator/pkg/update"
 	"github.com/cockroachdb/cockroach-operator/pkg/utilfeature"
 	"github.com/go-logr/logr"
 	"go.uber.org/zap/zapcore"
@@ -55,6 +57,7 @@ func init() {
 func main() {
 	var metricsAddr, featureGatesString, leaderElectionID string
 	var enableLeaderElection, skipWebhookConfig bool
+	var webhookCertRenewBefore time.Duration
 	var err error
 
 	// use zap logging cli options
@@ -70,6 +73,8 @@ func main() {
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&skipWebhookConfig, "skip-webhook-config", false,
 		"When set, don't setup webhook TLS certificates. Useful in OpenShift where this step is handled already.")
+	flag.DurationVar(&webhookCertRenewBefore, "webhook-cert-renew-before", 30*24*time.Hour,
+		"How long before expiry the webhook certificate and CA are renewed by the running manager.")
 	flag.Parse()
 
 	// create logger using zap cli options
@@ -127,6 +132,8 @@ func main() {
 		os.Exit(1)
 	}
 
+	// the webhook rejects the versions the update actor cannot move a running cluster to
+	crdbv1alpha1.VersionChangeValidator = update.ValidateVersionChange
 	if err := (&crdbv1alpha1.CrdbCluster{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to setup webhook")
 		os.Exit(1)
@@ -147,6 +154,18 @@ func main() {
 			setupLog.Error(err, "failed to setup TLS")
 			os.Exit(1)
 		}
+
+		// keep the webhook certificate valid while the manager runs
+		renewer, err := NewWebhookCertRenewer(os.Getenv("NAMESPACE"), certDir, webhookCertRenewBefore)
+		if err != nil {
+			setupLog.Error(err, "failed to create webhook certificate renewer")
+			os.Exit(1)
+		}
+
+		if err := mgr.Add(renewer); err != nil {
+			setupLog.Error(err, "unable to add webhook certificate renewer")
+			os.Exit(1)
+		}
 	}
 
 	setupLog.Info("starting manager")
@@ -20,29 +20,31 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/cockroachdb/cockroach-operator/pkg/resource"
 	"github.com/cockroachdb/cockroach-operator/pkg/security"
 	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
+	admissionv1 "k8s.io/client-go/kubernetes/typed/admissionregistration/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// webhookCertCheckInterval is how often the running manager checks whether the webhook certificate needs renewal.
+const webhookCertCheckInterval = time.Hour
+
 // SetupWebhookTLS ensures that the webhook TLS secret exists, the necesary files are in place, and that the webhook
 // client configuration has the correct CABundle for TLS. This should be called before starting the controller manager
 // to ensure everything is in place at startup.
 //
-// Certificate rotation is as simple as deleting the pod and letting the deployment start a new one. If you're using
-// your own certificates, be sure to update them before deleting the pod.
+// Once the manager is running, the certificate is renewed by the WebhookCertRenewer. If you're using your own CA, replace
+// the cockroach-operator-webhook-ca secret and the webhook certificate is reissued on the next check.
 func SetupWebhookTLS(ctx context.Context, ns, dir string) error {
-	cfg, err := ctrl.GetConfig()
-	if err != nil {
-		return errors.Wrap(err, "failed to get REST config")
-	}
-
-	cs, err := kubernetes.NewForConfig(cfg)
+	cs, err := newClientset()
 	if err != nil {
-		return errors.Wrap(err, "failed to create client set")
+		return err
 	}
 
 	webhookAPI := cs.AdmissionregistrationV1()
@@ -75,6 +77,148 @@ func SetupWebhookTLS(ctx context.Context, ns, dir string) error {
 	return nil
 }
 
+// WebhookCertRenewer periodically checks the webhook certificate served from dir and reissues it before it expires,
+// or as soon as the webhook CA secret changes. The webhook configurations are patched with both the new and the
+// previous CA before the new certificate is written, so the K8s API server trusts whichever one is being served while
+// the webhook server hot-reloads the files.
+type WebhookCertRenewer struct {
+	ns          string
+	dir         string
+	interval    time.Duration
+	renewBefore time.Duration
+
+	secrets  resource.WebhookCASecretsInterface
+	webhooks admissionv1.AdmissionregistrationV1Interface
+
+	// ca is the last CA patched into the webhook configurations
+	ca security.Certificate
+}
+
+// NewWebhookCertRenewer creates a WebhookCertRenewer for the webhook certificate in dir. Add it to the manager to run
+// it in the background.
+func NewWebhookCertRenewer(ns, dir string, renewBefore time.Duration) (*WebhookCertRenewer, error) {
+	cs, err := newClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookCertRenewer{
+		ns:          ns,
+		dir:         dir,
+		interval:    webhookCertCheckInterval,
+		renewBefore: renewBefore,
+		secrets:     cs.CoreV1().Secrets(ns),
+		webhooks:    cs.AdmissionregistrationV1(),
+	}, nil
+}
+
+// NeedLeaderElection returns false since every replica serves the webhook with its own certificate.
+func (r *WebhookCertRenewer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks the webhook certificate every interval until the context is done. Failures are logged and retried on
+// the next check.
+func (r *WebhookCertRenewer) Start(ctx context.Context) error {
+	log := logr.FromContextOrDiscard(ctx).WithName("webhook-cert-renewer")
+
+	ca, err := resource.FindOrCreateWebhookCA(ctx, r.secrets)
+	if err != nil {
+		log.Error(err, "failed to find webhook CA certificate")
+	}
+	r.ca = ca
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.renew(ctx); err != nil {
+				log.Error(err, "failed to renew webhook certificate")
+			}
+		}
+	}
+}
+
+func (r *WebhookCertRenewer) renew(ctx context.Context) error {
+	log := logr.FromContextOrDiscard(ctx).WithName("webhook-cert-renewer")
+	now := time.Now()
+
+	ca, rotated, err := resource.RenewWebhookCA(ctx, r.secrets, r.renewBefore, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to renew webhook CA certificate")
+	}
+
+	needsRenewal := rotated
+	if !needsRenewal {
+		current, err := readWebhookSecrets(r.dir)
+		if err != nil {
+			log.Error(err, "failed to read the webhook certificate, reissuing it")
+			needsRenewal = true
+		} else if needsRenewal, err = resource.WebhookCertificateNeedsRenewal(current, ca, r.renewBefore, now); err != nil {
+			log.Error(err, "failed to check the webhook certificate, reissuing it")
+			needsRenewal = true
+		}
+	}
+
+	if !needsRenewal {
+		return nil
+	}
+
+	log.Info("renewing webhook certificate")
+	cert, err := resource.CreateWebhookCertificate(ctx, r.secrets, r.ns)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook certificate")
+	}
+
+	// trust both CAs until the new certificate is served
+	bundle := resource.WebhookCABundle(ca, r.ca)
+	if err := resource.PatchMutatingWebhookConfig(ctx, r.webhooks.MutatingWebhookConfigurations(), bundle); err != nil {
+		return errors.Wrap(err, "failed to patch mutating webhook")
+	}
+
+	if err := resource.PatchValidatingWebhookConfig(ctx, r.webhooks.ValidatingWebhookConfigurations(), bundle); err != nil {
+		return errors.Wrap(err, "failed to patch validating webhook")
+	}
+
+	// the webhook server watches the files and reloads the certificate
+	if err := writeWebhookSecrets(cert, r.dir); err != nil {
+		return errors.Wrap(err, "failed to write webhook certificate to disk")
+	}
+
+	r.ca = ca
+	return nil
+}
+
+func newClientset() (*kubernetes.Clientset, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get REST config")
+	}
+
+	cs, err := kubernetes.NewForConfig(cfg)
+	return cs, errors.Wrap(err, "failed to create client set")
+}
+
+func readWebhookSecrets(dir string) (security.Certificate, error) {
+	crt, err := os.ReadFile(filepath.Join(dir, "tls.crt"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read TLS certificate")
+	}
+
+	key, err := os.ReadFile(filepath.Join(dir, "tls.key"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read TLS private key")
+	}
+
+	return resource.CertificateFromSecret(&corev1.Secret{
+		Data: map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key},
+	}), nil
+}
+
 func writeWebhookSecrets(cert security.Certificate, dir string) error {
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		return errors.Wrap(err, "failed to create certs directory")
@@ -18,10 +18,15 @@ package actor
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"time"
 
 	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/database"
 	"github.com/cockroachdb/cockroach-operator/pkg/resource"
 
+	"github.com/cockroachdb/errors"
 	"github.com/go-logr/logr"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -61,6 +66,17 @@ func (e ValidationError) Error() string {
 	return e.Err.Error()
 }
 
+// RequeueAfterErr is returned by actions that wait for a point in time. The request
+// is requeued once the wait is over and the action is not marked as failed.
+type RequeueAfterErr struct {
+	Err   error
+	After time.Duration
+}
+
+func (e RequeueAfterErr) Error() string {
+	return e.Err.Error()
+}
+
 // Actor is one action against the cluster if the cluster resource state can be handled
 type Actor interface {
 	Act(context.Context, *resource.Cluster, logr.Logger) error
@@ -83,3 +99,44 @@ type action struct {
 	scheme    *runtime.Scheme
 	config    *rest.Config
 }
+
+// openDatabase opens a connection to the system database of the cluster.
+func (a action) openDatabase(ctx context.Context, cluster *resource.Cluster, log logr.Logger) (*sql.DB, error) {
+	// test to see if we are running inside of Kubernetes
+	// If we are running inside of k8s we will not find this file.
+	runningInsideK8s := inK8s("/var/run/secrets/kubernetes.io/serviceaccount/token")
+
+	serviceName := cluster.PublicServiceAddress()
+	if runningInsideK8s {
+		log.V(DEBUGLEVEL).Info("operator is running inside of kubernetes, connecting to service for db connection")
+	} else {
+		serviceName = fmt.Sprintf("%s-0.%s.%s", cluster.Name(), cluster.Name(), cluster.Namespace())
+		log.V(DEBUGLEVEL).Info("operator is NOT inside of kubernetes, connecting to pod ordinal zero for db connection")
+	}
+
+	// The connection needs to use the discovery service name because of the
+	// hostnames in the SSL certificates
+	conn := &database.DBConnection{
+		Ctx:              ctx,
+		Client:           a.client,
+		RestConfig:       a.config,
+		ServiceName:      serviceName,
+		Namespace:        cluster.Namespace(),
+		DatabaseName:     "system",
+		Port:             cluster.Spec().SQLPort,
+		RunningInsideK8s: runningInsideK8s,
+	}
+
+	if cluster.Spec().TLSEnabled {
+		conn.UseSSL = true
+		conn.ClientCertificateSecretName = cluster.ClientTLSSecretName()
+		conn.RootCertificateSecretName = cluster.NodeTLSSecretName()
+	}
+
+	db, err := database.NewDbConnection(conn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create database connection")
+	}
+	log.V(DEBUGLEVEL).Info("opened db connection")
+	return db, nil
+}
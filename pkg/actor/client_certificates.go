@@ -0,0 +1,271 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/labels"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/security"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// serviceBindingSecretType is the type of the Secrets of the client certificates, as defined by
+	// the Service Binding specification for PostgreSQL compatible databases.
+	serviceBindingSecretType corev1.SecretType = "servicebinding.io/postgresql"
+	// caCrtKey is the entry of the CA certificate in the Secrets of the certificates.
+	caCrtKey = "ca.crt"
+	// clientCertificateClusterLabel and clientCertificateNamespaceLabel record on the Secrets of the
+	// client certificates the name and the namespace of the cluster that owns them. Only these
+	// Secrets are updated and deleted.
+	clientCertificateClusterLabel   = "crdb.io/cluster"
+	clientCertificateNamespaceLabel = "crdb.io/cluster-namespace"
+	// clientCertificatesFromLabel opts a namespace in to the Secrets of the client certificates of
+	// the clusters of the namespace given as its value.
+	clientCertificatesFromLabel = "crdb.io/client-certificates-from"
+)
+
+// issueClientCertificates issues the client certificates of the SQL users of spec.certificates.clients
+// and writes them to their Service Binding Secrets. The certificates are reissued with the node
+// certificate, when they expire soon or were not issued from the provided CA. The Secrets of the
+// users removed from the spec are deleted, if they are owned by the cluster.
+func (rc *generateCert) issueClientCertificates(ctx context.Context, log logr.Logger, cluster *resource.Cluster) error {
+	issued := map[string]api.ClientCertificate{}
+	if status := cluster.Status().Certificates; status != nil {
+		for _, c := range status.Clients {
+			issued[c.Namespace+"/"+c.SecretName] = c
+		}
+	}
+
+	clients := cluster.ClientCertificates()
+	if len(clients) > 0 {
+		// the CA of the root client certificate is the CA bundle the nodes trust
+		root, err := resource.LoadTLSSecret(cluster.ClientTLSSecretName(),
+			resource.NewKubeResource(ctx, rc.client, cluster.Namespace(), kube.DefaultPersister))
+		if err != nil {
+			return errors.Wrap(err, "failed to get client TLS secret")
+		}
+		if err := os.WriteFile(filepath.Join(rc.CertsDir, "ca.crt"), root.CA(), 0644); err != nil {
+			return errors.Wrap(err, "failed to write CA cert")
+		}
+	}
+
+	for _, c := range clients {
+		key := c.Namespace + "/" + c.SecretName
+		previous, ok := issued[key]
+		delete(issued, key)
+		if err := rc.issueClientCertificate(ctx, log, cluster, c, ok && reflect.DeepEqual(previous, c)); err != nil {
+			return errors.Wrapf(err, "failed to issue the client certificate of user %s", c.User)
+		}
+	}
+
+	for _, c := range issued {
+		if err := deleteClientSecret(ctx, rc.client, cluster, c, log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteClientSecrets deletes the Secrets of the client certificates of the spec and of the status
+// that are owned by the cluster, once it is deleted.
+func deleteClientSecrets(ctx context.Context, cl client.Client, cluster *resource.Cluster, log logr.Logger) error {
+	clients := cluster.ClientCertificates()
+	if status := cluster.Status().Certificates; status != nil {
+		clients = append(clients, status.Clients...)
+	}
+	for _, c := range clients {
+		if err := deleteClientSecret(ctx, cl, cluster, c, log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteClientSecret deletes the Secret of a client certificate, if it is owned by the cluster.
+func deleteClientSecret(ctx context.Context, cl client.Client, cluster *resource.Cluster, c api.ClientCertificate, log logr.Logger) error {
+	secret := &corev1.Secret{}
+	err := cl.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.SecretName}, secret)
+	if kube.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to get secret %s/%s", c.Namespace, c.SecretName)
+	}
+	if !clientSecretOwned(cluster, secret) {
+		log.Info("keeping the client certificate secret, it is not owned by the cluster", "user", c.User, "secret", c.SecretName)
+		return nil
+	}
+	log.Info("deleting the client certificate secret", "user", c.User, "namespace", c.Namespace, "secret", c.SecretName)
+	if err := cl.Delete(ctx, secret); kube.IgnoreNotFound(err) != nil {
+		return errors.Wrapf(err, "failed to delete secret %s/%s", c.Namespace, c.SecretName)
+	}
+	return nil
+}
+
+// clientSecretOwned returns true if the Secret is labelled as a client certificate Secret of the cluster.
+func clientSecretOwned(cluster *resource.Cluster, secret *corev1.Secret) bool {
+	return secret.Labels[clientCertificateClusterLabel] == cluster.Name() &&
+		secret.Labels[clientCertificateNamespaceLabel] == cluster.Namespace()
+}
+
+// clientSecretNamespaceAllowed returns an error unless the Secrets of the client certificates can be
+// written to the namespace: the namespace of the cluster, or a namespace labelled with
+// crdb.io/client-certificates-from set to the namespace of the cluster.
+func clientSecretNamespaceAllowed(ctx context.Context, cl client.Client, cluster *resource.Cluster, namespace string) error {
+	if namespace == cluster.Namespace() {
+		return nil
+	}
+	ns := &corev1.Namespace{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+	if ns.Labels[clientCertificatesFromLabel] != cluster.Namespace() {
+		return errors.Newf("namespace %s does not accept the client certificates of the clusters of namespace %s, "+
+			"label it with %s=%s", namespace, cluster.Namespace(), clientCertificatesFromLabel, cluster.Namespace())
+	}
+	return nil
+}
+
+// issueClientCertificate issues the client certificate of a SQL user and writes its Secret, unless
+// the certificate in the Secret is still current. An existing Secret that is not owned by the
+// cluster is never overwritten.
+func (rc *generateCert) issueClientCertificate(ctx context.Context, log logr.Logger, cluster *resource.Cluster,
+	c api.ClientCertificate, unchanged bool) error {
+	if err := clientSecretNamespaceAllowed(ctx, rc.client, cluster, c.Namespace); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: c.SecretName, Namespace: c.Namespace}}
+	err := rc.client.Get(ctx, client.ObjectKeyFromObject(secret), secret)
+	if kube.IgnoreNotFound(err) != nil {
+		return errors.Wrapf(err, "failed to get secret %s/%s", c.Namespace, c.SecretName)
+	}
+	if err == nil && !clientSecretOwned(cluster, secret) {
+		return errors.Newf("secret %s/%s exists and is not owned by the cluster, refusing to overwrite it",
+			c.Namespace, c.SecretName)
+	}
+
+	cert := secret.Data[corev1.TLSCertKey]
+	current := err == nil && unchanged && !rc.Renewed && len(cert) > 0 &&
+		!certificateExpiresWithin(cert, cluster.CertificateRenewBefore()) &&
+		(rc.CACert == nil || certificateSignedBy(cert, rc.CACert))
+	if current {
+		log.V(DEBUGLEVEL).Info("not updating client certificate", "user", c.User)
+		return nil
+	}
+
+	err = errors.Wrap(
+		security.CreateClientPair(
+			rc.CertsDir,
+			rc.CAKey,
+			certificateLifetime,
+			overwriteFiles,
+			security.SQLUsername{U: c.User},
+			generatePKCS8Key),
+		"failed to generate client certificate and key")
+	if err != nil {
+		return err
+	}
+
+	ca, err := os.ReadFile(filepath.Join(rc.CertsDir, "ca.crt"))
+	if err != nil {
+		return errors.Wrap(err, "unable to read ca.crt")
+	}
+	pemCert, err := os.ReadFile(filepath.Join(rc.CertsDir, fmt.Sprintf("client.%s.crt", c.User)))
+	if err != nil {
+		return errors.Wrapf(err, "unable to read client.%s.crt", c.User)
+	}
+	pemKey, err := os.ReadFile(filepath.Join(rc.CertsDir, fmt.Sprintf("client.%s.key", c.User)))
+	if err != nil {
+		return errors.Wrapf(err, "unable to read client.%s.key", c.User)
+	}
+
+	data := connectionSecretData(cluster, c)
+	data[caCrtKey] = ca
+	data[corev1.TLSCertKey] = pemCert
+	data[corev1.TLSPrivateKeyKey] = pemKey
+
+	_, err = kube.DefaultPersister(ctx, rc.client, secret, func() error {
+		secret.Type = serviceBindingSecretType
+		secret.Labels = labels.Common(cluster.Unwrap()).Selector(nil)
+		secret.Labels[clientCertificateClusterLabel] = cluster.Name()
+		secret.Labels[clientCertificateNamespaceLabel] = cluster.Namespace()
+		secret.Data = data
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write secret %s/%s", c.Namespace, c.SecretName)
+	}
+
+	log.Info("issued the client certificate of a SQL user", "user", c.User, "secret", c.SecretName)
+	return nil
+}
+
+// connectionSecretData returns the Service Binding entries of the Secret of a client certificate.
+// The connection string refers to the certificate files of the Secret projected under the binding
+// root of the application. The host is the SQL host of the cluster, or its public service.
+func connectionSecretData(cluster *resource.Cluster, c api.ClientCertificate) map[string][]byte {
+	host := cluster.Status().SQLHost
+	if host == "" {
+		host = cluster.PublicServiceAddress()
+	}
+	port := api.DefaultSQLPort
+	if cluster.Spec().SQLPort != nil {
+		port = *cluster.Spec().SQLPort
+	}
+
+	dir := path.Join(c.BindingRoot, c.SecretName)
+	query := url.Values{
+		"sslmode":     []string{"verify-full"},
+		"sslrootcert": []string{path.Join(dir, caCrtKey)},
+		"sslcert":     []string{path.Join(dir, corev1.TLSCertKey)},
+		"sslkey":      []string{path.Join(dir, corev1.TLSPrivateKeyKey)},
+	}
+	uri := url.URL{
+		Scheme:   "postgresql",
+		User:     url.User(c.User),
+		Host:     net.JoinHostPort(host, fmt.Sprint(port)),
+		Path:     "/" + c.Database,
+		RawQuery: query.Encode(),
+	}
+
+	return map[string][]byte{
+		"type":     []byte("postgresql"),
+		"provider": []byte("cockroachdb"),
+		"host":     []byte(host),
+		"port":     []byte(fmt.Sprint(port)),
+		"username": []byte(c.User),
+		"database": []byte(c.Database),
+		"sslmode":  []byte("verify-full"),
+		"uri":      []byte(uri.String()),
+	}
+}
@@ -87,6 +87,15 @@ func (r *clusterRestart) Act(ctx context.Context, cluster *resource.Cluster, log
 		return err
 	}
 	healthChecker := healthchecker.NewHealthChecker(cluster, r.clientset, r.config)
+	healthChecker.SetClient(r.client)
+	if healthChecker.NeedsDatabase() {
+		db, err := r.openDatabase(ctx, cluster, log)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		healthChecker.SetDatabase(db)
+	}
 	if strings.EqualFold(restartType, api.ClusterRestartType(api.RollingRestart).String()) {
 		log.V(DEBUGLEVEL).Info("initiating rolling restart action")
 		if err := r.rollingSts(ctx, statefulSet.DeepCopy(), log, healthChecker); err != nil {
@@ -19,6 +19,9 @@ package actor
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/clustersql"
@@ -31,21 +34,27 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func newDecommission(cl client.Client, config *rest.Config, clientset kubernetes.Interface) Actor {
+func newDecommission(cl client.Client, config *rest.Config, clientset kubernetes.Interface, recorder record.EventRecorder) Actor {
 	return &decommission{
-		action: newAction(nil, cl, config, clientset),
+		action:   newAction(nil, cl, config, clientset),
+		recorder: recorder,
 	}
 }
 
 // decommission performs the initialization of the new cluster
 type decommission struct {
 	action
+
+	recorder record.EventRecorder
 }
 
 // GetActionType returns  api.DecommissionAction used to set the cluster status errors
@@ -74,9 +83,16 @@ func (d decommission) Act(ctx context.Context, cluster *resource.Cluster, log lo
 		return NotReadyErr{Err: errors.New("decommission statefulset does not have all replicas up")}
 	}
 
+	prunePVC := utilfeature.DefaultMutableFeatureGate.Enabled(features.AutoPrunePVC)
+	pvcPruner := newPVCPruner(d.config, d.clientset, d.client, d.recorder, cluster, ss.Name, log)
+
 	nodes := uint(cluster.Spec().Nodes)
 	log.Info("replicas decommissioning", "status.CurrentReplicas", status.CurrentReplicas, "expected", cluster.Spec().Nodes)
 	if status.CurrentReplicas <= cluster.Spec().Nodes {
+		// the PVCs retained by the last decommission are pruned once they can be
+		if prunePVC && cluster.Status().RetainedPVCs != nil {
+			return pruneLater(cluster, pvcPruner.Prune(ctx), d.recorder, log)
+		}
 		return nil
 	}
 	// test to see if we are running inside of Kubernetes
@@ -123,12 +139,6 @@ func (d decommission) Act(ctx context.Context, cluster *resource.Cluster, log lo
 	}
 
 	drainer := scale.NewCockroachNodeDrainer(log, cluster.Namespace(), ss.Name, d.config, d.clientset, cluster.Spec().TLSEnabled, 3*timeout)
-	pvcPruner := scale.PersistentVolumePruner{
-		Namespace:   cluster.Namespace(),
-		StatefulSet: ss.Name,
-		ClientSet:   d.clientset,
-		Logger:      log,
-	}
 	//we should start scale down
 	scaler := scale.Scaler{
 		Logger: log,
@@ -138,9 +148,16 @@ func (d decommission) Act(ctx context.Context, cluster *resource.Cluster, log lo
 			Name:      ss.Name,
 		},
 		Drainer:   drainer,
-		PVCPruner: &pvcPruner,
+		PVCPruner: pvcPruner,
+	}
+	err = scaler.EnsureScale(ctx, nodes, *cluster.Spec().GRPCPort, prunePVC)
+	// the nodes are removed, the PVCs retained by the pruner are tracked in the status
+	var retained scale.PVCsRetainedErr
+	if errors.As(err, &retained) {
+		cluster.SetTrue(api.DecommissionCondition)
+		return pruneLater(cluster, err, d.recorder, log)
 	}
-	if err := scaler.EnsureScale(ctx, nodes, *cluster.Spec().GRPCPort, utilfeature.DefaultMutableFeatureGate.Enabled(features.AutoPrunePVC)); err != nil {
+	if err != nil {
 		/// now check if the decommissionStaleErr and update status
 		log.Error(err, "decommission failed")
 		cluster.SetFalse(api.DecommissionCondition)
@@ -148,6 +165,71 @@ func (d decommission) Act(ctx context.Context, cluster *resource.Cluster, log lo
 	}
 	// TO DO @alina we will need to save the status foreach action
 	cluster.SetTrue(api.DecommissionCondition)
+	cluster.SetRetainedPVCsStatus(nil)
 	log.V(DEBUGLEVEL).Info("decommission completed", "cond", ss.Status.Conditions)
 	return nil
 }
+
+// newPVCPruner returns the pruner of the PVCs of the decommissioned nodes. A PVC is only deleted once
+// CockroachDB reports its node decommissioned, after the retention delay and the snapshot requested
+// in spec.dataStore.prune. Each PVC deleted is recorded in an event.
+func newPVCPruner(config *rest.Config, clientset kubernetes.Interface, cl client.Client, recorder record.EventRecorder,
+	cluster *resource.Cluster, stsName string, log logr.Logger) *scale.PersistentVolumePruner {
+	pruner := &scale.PersistentVolumePruner{
+		Namespace:   cluster.Namespace(),
+		StatefulSet: stsName,
+		ClientSet:   clientset,
+		Logger:      log,
+		Checker:     scale.NewCockroachDecommissionChecker(log, cluster.Namespace(), stsName, config, clientset, cluster.Spec().TLSEnabled),
+	}
+	if prune := cluster.Spec().DataStore.Prune; prune != nil {
+		if prune.RetentionDelay != nil {
+			pruner.RetentionDelay = prune.RetentionDelay.Duration
+		}
+		if prune.Snapshot {
+			pruner.Snapshotter = pruneSnapshotter{client: cl, cluster: cluster}
+		}
+	}
+	pruner.Audit = func(pvc corev1.PersistentVolumeClaim, nodeID uint) {
+		log.Info("pruned PVC", "name", pvc.Name, "uid", pvc.UID, "node", nodeID)
+		message := fmt.Sprintf("Deleted PVC %s of node %d", pvc.Name, nodeID)
+		if pruner.Snapshotter != nil {
+			message += fmt.Sprintf(", its data is kept in the VolumeSnapshot %s", prunedVolumeSnapshotName(pvc))
+		}
+		recorder.Event(cluster.Unwrap(), corev1.EventTypeNormal, "PVCPruned", message)
+	}
+	return pruner
+}
+
+// pruneLater requeues the action when the pruner retained PVCs, until they are checked again. The
+// other actions run in the meantime. The status of the retained PVCs is cleared once they are
+// pruned.
+func pruneLater(cluster *resource.Cluster, err error, recorder record.EventRecorder, log logr.Logger) error {
+	var retained scale.PVCsRetainedErr
+	if !errors.As(err, &retained) {
+		if err == nil {
+			cluster.SetRetainedPVCsStatus(nil)
+		}
+		return err
+	}
+	retainPVCs(cluster, retained, recorder, log)
+	return RequeueAfterErr{Err: err, After: retained.RetryAfter}
+}
+
+// retainPVCs records the PVCs retained by the pruner in the status, for the decommission action to
+// prune them once they are checked again. A warning event reports the PVCs no node ran on when they
+// are first found.
+func retainPVCs(cluster *resource.Cluster, retained scale.PVCsRetainedErr, recorder record.EventRecorder, log logr.Logger) {
+	previous := cluster.Status().RetainedPVCs
+	if len(retained.Orphaned) > 0 && (previous == nil || !reflect.DeepEqual(previous.Orphaned, retained.Orphaned)) {
+		log.Info("keeping the PVCs no node ran on", "pvcs", retained.Orphaned)
+		recorder.Eventf(cluster.Unwrap(), corev1.EventTypeWarning, "PVCsOrphaned",
+			"No node ran on the PVCs %s, they are kept until they are deleted", strings.Join(retained.Orphaned, ", "))
+	}
+
+	cluster.SetRetainedPVCsStatus(&api.RetainedPVCsStatus{
+		PVCs:          retained.PVCs,
+		Orphaned:      retained.Orphaned,
+		NextCheckTime: metav1.NewTime(time.Now().Add(retained.RetryAfter)),
+	})
+}
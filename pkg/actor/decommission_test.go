@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/scale"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/go-logr/zapr"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestPruneLater(t *testing.T) {
+	cluster := resource.NewCluster(testutil.NewBuilder("crdb").WithPVDataStore("1Gi").Cr())
+	recorder := record.NewFakeRecorder(2)
+	log := zapr.NewLogger(zaptest.NewLogger(t))
+
+	// the retained PVCs are recorded in the status and checked again later
+	retained := scale.PVCsRetainedErr{
+		PVCs:       []string{"datadir-crdb-3"},
+		Orphaned:   []string{"datadir-crdb-4"},
+		RetryAfter: time.Minute,
+	}
+	err := actor.PruneLater(&cluster, retained, recorder, log)
+	var requeue actor.RequeueAfterErr
+	require.ErrorAs(t, err, &requeue)
+	require.Equal(t, time.Minute, requeue.After)
+
+	status := cluster.Status().RetainedPVCs
+	require.NotNil(t, status)
+	require.Equal(t, []string{"datadir-crdb-3"}, status.PVCs)
+	require.Equal(t, []string{"datadir-crdb-4"}, status.Orphaned)
+	require.True(t, status.NextCheckTime.After(time.Now()))
+	require.Contains(t, <-recorder.Events, "Warning PVCsOrphaned")
+
+	// the orphaned PVCs are only reported once
+	require.Error(t, actor.PruneLater(&cluster, retained, recorder, log))
+	require.Empty(t, recorder.Events)
+
+	// a failure keeps the status
+	require.EqualError(t, actor.PruneLater(&cluster, errors.New("boom"), recorder, log), "boom")
+	require.NotNil(t, cluster.Status().RetainedPVCs)
+
+	// the status is cleared once the PVCs are pruned
+	require.NoError(t, actor.PruneLater(&cluster, nil, recorder, log))
+	require.Nil(t, cluster.Status().RetainedPVCs)
+}
@@ -21,17 +21,20 @@ import (
 
 	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/ptr"
 	"github.com/cockroachdb/cockroach-operator/pkg/resource"
 	"github.com/cockroachdb/errors"
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func newDeploy(scheme *runtime.Scheme, cl client.Client, kd kube.KubernetesDistribution, clientset kubernetes.Interface) Actor {
+func newDeploy(scheme *runtime.Scheme, cl client.Client, config *rest.Config, kd kube.KubernetesDistribution, clientset kubernetes.Interface) Actor {
 	return &deploy{
-		action: newAction(scheme, cl, nil, clientset),
+		action: newAction(scheme, cl, config, clientset),
 		kd:     kd,
 	}
 }
@@ -59,15 +62,33 @@ func (d deploy) Act(ctx context.Context, cluster *resource.Cluster, log logr.Log
 		return errors.Wrap(err, "failed to get Kubernetes distribution")
 	}
 
-	labelSelector := r.Labels.Selector(cluster.Spec().AdditionalLabels)
-	builders := []resource.Builder{
-		resource.DiscoveryServiceBuilder{Cluster: cluster, Selector: labelSelector},
-		resource.PublicServiceBuilder{Cluster: cluster, Selector: labelSelector},
-		resource.StatefulSetBuilder{Cluster: cluster, Selector: labelSelector, Telemetry: kubernetesDistro},
-		resource.PdbBuilder{Cluster: cluster, Selector: labelSelector},
+	rotation, err := d.encryptionKeyRotation(ctx, cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the encryption key of the statefulset")
+	}
+	// The new store key is rolled out by a rolling restart of the operator, that checks the health
+	// of the cluster between the pods. The pods are held on their current revision until then.
+	var partition *int32
+	if rotation && managedRestartEnabled() {
+		partition = ptr.Int32(cluster.Spec().Nodes)
 	}
 
+	labelSelector := r.Labels.Selector(cluster.Spec().AdditionalLabels)
+	builders := deployBuilders(cluster, labelSelector, kube.TelemetryChannel(kubernetesDistro), partition)
+
 	for _, b := range builders {
+		if sb, ok := b.(resource.StatefulSetBuilder); ok {
+			if err := d.restoreVolumes(ctx, cluster, sb, log); err != nil {
+				return errors.Wrap(err, "failed to restore the volumes from a snapshot")
+			}
+		}
+		if _, ok := b.(resource.StatefulSetBuilder); ok && partition != nil {
+			log.Info("rotating the encryption key", "key", cluster.EncryptionKeyReference())
+			if err := requestRollingRestart(ctx, d.client, cluster); err != nil {
+				return errors.Wrap(err, "failed to request a rolling restart")
+			}
+		}
+
 		changed, err := resource.Reconciler{
 			ManagedResource: r,
 			Builder:         b,
@@ -85,6 +106,134 @@ func (d deploy) Act(ctx context.Context, cluster *resource.Cluster, log logr.Log
 		}
 	}
 
+	for _, obj := range disabledResources(cluster) {
+		if err := d.deleteIfOwned(ctx, cluster, obj); err != nil {
+			return errors.Wrapf(err, "failed to delete %s", obj.GetName())
+		}
+	}
+
+	address, err := sqlLoadBalancerAddress(ctx, d.clientset, cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the SQL load balancer address")
+	}
+	if cluster.SQLExternalHost() == "" && address != cluster.Status().SQLHost {
+		log.Info("SQL load balancer address changed", "address", address)
+		cluster.SetSQLHost(address)
+		// the node certificate has to be valid for the new address
+		if address != "" && cluster.Spec().TLSEnabled && cluster.Spec().NodeTLSSecret == "" {
+			cluster.SetFalse(api.CertificateGenerated)
+		}
+	}
+
+	if encryptionStatusStale(cluster) {
+		status, err := d.encryptionStatus(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		cluster.SetEncryptionStatus(status)
+	}
+
 	log.Info("deployed database")
 	return nil
 }
+
+// deployBuilders returns the builders of the resources managed by the deploy action. A non nil
+// partition holds the pods of the statefulset on their current revision.
+func deployBuilders(cluster *resource.Cluster, selector map[string]string, telemetry string, partition *int32) []resource.Builder {
+	var builders []resource.Builder
+	// the nodes of a clone are isolated before they start
+	if cluster.IsClone() {
+		builders = append(builders, resource.CloneNetworkPolicyBuilder{Cluster: cluster, Selector: selector})
+	}
+
+	builders = append(builders,
+		resource.DiscoveryServiceBuilder{Cluster: cluster, Selector: selector},
+		resource.PublicServiceBuilder{Cluster: cluster, Selector: selector},
+	)
+
+	if config := cluster.PublicServiceConfig(); config != nil {
+		if config.SQL != nil {
+			builders = append(builders, resource.SQLServiceBuilder{Cluster: cluster, Selector: selector})
+		}
+		if config.UI != nil {
+			builders = append(builders, resource.UIServiceBuilder{Cluster: cluster, Selector: selector})
+		}
+	}
+
+	builders = append(builders,
+		resource.StatefulSetBuilder{Cluster: cluster, Selector: selector, Telemetry: telemetry, Partition: partition},
+		resource.PdbBuilder{Cluster: cluster, Selector: selector},
+	)
+
+	if cluster.IsNetworkPolicyEnabled() {
+		builders = append(builders, resource.NetworkPolicyBuilder{Cluster: cluster, Selector: selector})
+	}
+
+	return builders
+}
+
+// disabledResources returns placeholders of the optional resources that are not configured
+// and have to be removed if they exist: the dedicated SQL and UI services and the NetworkPolicies.
+func disabledResources(cluster *resource.Cluster) []client.Object {
+	var objs []client.Object
+	config := cluster.PublicServiceConfig()
+	if config == nil || config.SQL == nil {
+		objs = append(objs, resource.SQLServiceBuilder{Cluster: cluster}.Placeholder())
+	}
+	if config == nil || config.UI == nil {
+		objs = append(objs, resource.UIServiceBuilder{Cluster: cluster}.Placeholder())
+	}
+	if !cluster.IsNetworkPolicyEnabled() {
+		objs = append(objs, resource.NetworkPolicyBuilder{Cluster: cluster}.Placeholder())
+	}
+	if !cluster.IsClone() {
+		objs = append(objs, resource.CloneNetworkPolicyBuilder{Cluster: cluster}.Placeholder())
+	}
+	return objs
+}
+
+// isOwned fetches the object and returns true if it exists and is controlled by the cluster.
+func isOwned(ctx context.Context, cl client.Client, cluster *resource.Cluster, obj client.Object) (bool, error) {
+	key := client.ObjectKey{Namespace: cluster.Namespace(), Name: obj.GetName()}
+	if err := cl.Get(ctx, key, obj); kube.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return metav1.IsControlledBy(obj, cluster.Unwrap()), nil
+}
+
+func (d deploy) deleteIfOwned(ctx context.Context, cluster *resource.Cluster, obj client.Object) error {
+	owned, err := isOwned(ctx, d.client, cluster, obj)
+	if err != nil || !owned {
+		return err
+	}
+	return kube.IgnoreNotFound(d.client.Delete(ctx, obj))
+}
+
+// sqlLoadBalancerAddress returns the hostname or IP assigned to the load balancer
+// exposing SQL, or an empty string if SQL is not exposed through a load balancer
+// or the address is not allocated yet.
+func sqlLoadBalancerAddress(ctx context.Context, clientset kubernetes.Interface, cluster *resource.Cluster) (string, error) {
+	name := cluster.SQLLoadBalancerServiceName()
+	if name == "" {
+		return "", nil
+	}
+
+	svc, err := clientset.CoreV1().Services(cluster.Namespace()).Get(ctx, name, metav1.GetOptions{})
+	if kube.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.Hostname != "" {
+			return ingress.Hostname, nil
+		}
+		if ingress.IP != "" {
+			return ingress.IP, nil
+		}
+	}
+	return "", nil
+}
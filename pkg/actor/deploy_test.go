@@ -29,6 +29,10 @@ import (
 	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 type key struct {
@@ -74,7 +78,7 @@ func TestDeploysNotInitalizedClusterAfterVersionChecker(t *testing.T) {
 	cluster.SetTrue(api.CrdbVersionChecked)
 
 	mock := kube.MockKubernetesDistribution()
-	deploy := actor.NewDeploy(scheme, client, mock, nil)
+	deploy := actor.NewDeploy(scheme, client, nil, mock, nil)
 	t.Log(cluster.Status().Conditions)
 
 	testLog := zapr.NewLogger(zaptest.NewLogger(t))
@@ -86,3 +90,36 @@ func TestDeploysNotInitalizedClusterAfterVersionChecker(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestSQLLoadBalancerAddress(t *testing.T) {
+	ctx := context.Background()
+	builder := testutil.NewBuilder("cockroachdb").Namespaced("default")
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "cockroachdb-public", Namespace: "default"},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}},
+		}},
+	}
+	clientset := fake.NewSimpleClientset(svc)
+
+	// the public service is not a load balancer
+	address, err := actor.SQLLoadBalancerAddress(ctx, clientset, builder.Cluster())
+	require.NoError(t, err)
+	require.Empty(t, address)
+
+	lb := builder.WithPublicServices(&api.PublicServiceConfig{
+		ServiceConfig: api.ServiceConfig{Type: corev1.ServiceTypeLoadBalancer},
+	})
+	address, err = actor.SQLLoadBalancerAddress(ctx, clientset, lb.Cluster())
+	require.NoError(t, err)
+	require.Equal(t, "203.0.113.10", address)
+
+	// the dedicated SQL service is not created yet
+	sql := builder.WithPublicServices(&api.PublicServiceConfig{
+		SQL: &api.ServiceConfig{Type: corev1.ServiceTypeLoadBalancer},
+	})
+	address, err = actor.SQLLoadBalancerAddress(ctx, clientset, sql.Cluster())
+	require.NoError(t, err)
+	require.Empty(t, address)
+}
@@ -18,6 +18,8 @@ package actor
 
 import (
 	"context"
+	"reflect"
+	"time"
 
 	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/condition"
@@ -32,6 +34,7 @@ import (
 	kubetypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -49,19 +52,27 @@ type clusterDirector struct {
 	config     *rest.Config
 }
 
-func NewDirector(scheme *runtime.Scheme, cl client.Client, config *rest.Config, clientset kubernetes.Interface) Director {
+func NewDirector(scheme *runtime.Scheme, cl client.Client, config *rest.Config, clientset kubernetes.Interface,
+	recorder record.EventRecorder) Director {
 	kd := kube.NewKubernetesDistribution()
 	actors := map[api.ActionType]Actor{
 		api.ClusterRestartAction:    newClusterRestart(cl, config, clientset),
 		api.SetupRBACAction:         newSetupRBACAction(scheme, cl),
-		api.DecommissionAction:      newDecommission(cl, config, clientset),
+		api.DecommissionAction:      newDecommission(cl, config, clientset, recorder),
 		api.VersionCheckerAction:    newVersionChecker(scheme, cl, clientset),
 		api.GenerateCertAction:      newGenerateCert(cl),
 		api.PartitionedUpdateAction: newPartitionedUpdate(cl, config, clientset),
+		api.MigrateTLSAction:        newMigrateTLS(scheme, cl, config, kd, clientset, recorder),
+		api.MigrateStorageAction:    newMigrateStorage(scheme, cl, config, clientset, recorder),
 		api.ResizePVCAction:         newResizePVC(scheme, cl, clientset),
-		api.DeployAction:            newDeploy(scheme, cl, kd, clientset),
+		api.DeployAction:            newDeploy(scheme, cl, config, kd, clientset),
 		api.InitializeAction:        newInitialize(scheme, cl, config, clientset),
 		api.ExposeIngressAction:     newExposeIngress(scheme, cl, config, clientset),
+		api.ExposeGatewayAction:     newExposeGateway(scheme, cl, config, clientset),
+		api.FinalizeUpgradeAction:   newFinalizeUpgrade(cl, config, clientset),
+		api.SnapshotAction:          newSnapshot(cl, clientset, recorder),
+		api.RetainVolumesAction:     newRetainVolumes(cl, clientset, recorder),
+		api.ExpandPVCAction:         newExpandPVC(cl, config, clientset, recorder),
 	}
 	return &clusterDirector{
 		actors:     actors,
@@ -78,6 +89,14 @@ func (cd *clusterDirector) GetActor(aType api.ActionType) Actor {
 }
 
 func (cd *clusterDirector) GetActorToExecute(ctx context.Context, cluster *resource.Cluster, log logr.Logger) (Actor, error) {
+	if cd.needsVolumeRetention(cluster) {
+		return cd.actors[api.RetainVolumesAction], nil
+	}
+	// nothing else is reconciled once the cluster is deleted
+	if cluster.IsBeingDeleted() {
+		return nil, nil
+	}
+
 	if cd.needsRestart(cluster) {
 		return cd.actors[api.ClusterRestartAction], nil
 	}
@@ -111,10 +130,24 @@ func (cd *clusterDirector) GetActorToExecute(ctx context.Context, cluster *resou
 		return cd.actors[api.GenerateCertAction], nil
 	}
 
+	if cd.needsTLSMigration(cluster, ss) {
+		return cd.actors[api.MigrateTLSAction], nil
+	}
+
 	if cd.needsPartitionedUpdate(cluster, ss) {
 		return cd.actors[api.PartitionedUpdateAction], nil
 	}
 
+	// A previewed dry run holds the version change, the actors that follow keep the version
+	// deployed on the statefulset instead of rolling the pods to the requested one.
+	if upgradePreviewed(cluster, ss) {
+		cluster.HoldVersion(ss.Annotations[resource.CrdbVersionAnnotation], ss.Annotations[resource.CrdbContainerImageAnnotation])
+	}
+
+	if cd.needsStorageMigration(cluster, ss) {
+		return cd.actors[api.MigrateStorageAction], nil
+	}
+
 	if cd.needsPVCResize(cluster, ss) {
 		return cd.actors[api.ResizePVCAction], nil
 	}
@@ -130,16 +163,47 @@ func (cd *clusterDirector) GetActorToExecute(ctx context.Context, cluster *resou
 		return cd.actors[api.InitializeAction], nil
 	}
 
-	processIngress, err := cd.processIngress(ctx, cluster)
+	processIngress, err := cd.processIngress(ctx, cluster, log)
 	if err != nil {
 		return nil, err
 	} else if processIngress {
 		return cd.actors[api.ExposeIngressAction], nil
 	}
 
+	if cd.needsUpgradeFinalization(cluster) {
+		return cd.actors[api.FinalizeUpgradeAction], nil
+	}
+
+	processGateway, err := cd.processGateway(ctx, cluster)
+	if err != nil {
+		return nil, err
+	} else if processGateway {
+		return cd.actors[api.ExposeGatewayAction], nil
+	}
+
+	if cd.needsSnapshot(cluster) {
+		return cd.actors[api.SnapshotAction], nil
+	}
+
+	if cd.needsPVCAutoExpand(cluster) {
+		return cd.actors[api.ExpandPVCAction], nil
+	}
+
 	return nil, nil
 }
 
+func (cd *clusterDirector) needsVolumeRetention(cluster *resource.Cluster) bool {
+	// The retention policy is applied,
+	// - when a deleted cluster still has the operator finalizer
+	// - when the finalizer must be added for the Delete retention policy or the client certificates,
+	//   or removed otherwise
+
+	if cluster.IsBeingDeleted() {
+		return cluster.HasFinalizer()
+	}
+	return cluster.HasFinalizer() != cluster.NeedsFinalizer()
+}
+
 func (cd *clusterDirector) needsRestart(cluster *resource.Cluster) bool {
 	conditions := cluster.Status().Conditions
 	featureClusterRestartEnabled := utilfeature.DefaultMutableFeatureGate.Enabled(features.ClusterRestart)
@@ -190,6 +254,9 @@ func (cd *clusterDirector) needsDecommission(cluster *resource.Cluster, ss *apps
 	// - the cluster must be initialized
 	// - the current number of nodes must match the previously specified number of nodes, and that number must exceed the
 	//   currently specified number of nodes
+	// - no storage class migration must be in progress, it removes the node it added itself
+	// The PVCs retained by the last decommission are pruned once all the replicas are up, when they
+	// are due to be checked again.
 
 	if !featureDecommissionEnabled {
 		return false
@@ -197,9 +264,20 @@ func (cd *clusterDirector) needsDecommission(cluster *resource.Cluster, ss *apps
 	if !conditionInitializedTrue {
 		return false
 	}
+	if cluster.Status().StorageMigration != nil {
+		return false
+	}
 
 	status := &ss.Status
-	return status.CurrentReplicas == status.Replicas && status.CurrentReplicas > cluster.Spec().Nodes
+	if status.CurrentReplicas != status.Replicas {
+		return false
+	}
+	if status.CurrentReplicas > cluster.Spec().Nodes {
+		return true
+	}
+	retained := cluster.Status().RetainedPVCs
+	return utilfeature.DefaultMutableFeatureGate.Enabled(features.AutoPrunePVC) &&
+		retained != nil && !time.Now().Before(retained.NextCheckTime.Time)
 }
 
 func (cd *clusterDirector) needsVersionCheck(cluster *resource.Cluster) bool {
@@ -234,17 +312,55 @@ func (cd *clusterDirector) needsCertificateGeneration(cluster *resource.Cluster)
 	// - the certificate should not already be generated
 	// - TLS should be enabled and a certificate should not already be provided
 	// - Regenerate if SQL Host is changed
+	// - Reissue if the CA or the additional names of spec.certificates changed
+	// - Renew if the node certificate expires soon
 
 	if conditionCertificateGeneratedTrue {
-		if cluster.IsSQLIngressEnabled() && cluster.Status().SQLHost != cluster.Spec().Ingress.SQL.Host {
+		if host := cluster.SQLExternalHost(); host != "" && cluster.Status().SQLHost != host {
 			return true
 		}
-		return false
+		if cluster.Spec().NodeTLSSecret != "" {
+			return false
+		}
+		if !reflect.DeepEqual(cluster.Status().Certificates, cluster.CertificatesStatus()) {
+			return true
+		}
+		return certificateExpiresSoon(cluster)
 	}
 
 	return cluster.Spec().TLSEnabled && cluster.Spec().NodeTLSSecret == ""
 }
 
+func (cd *clusterDirector) needsTLSMigration(cluster *resource.Cluster, ss *appsv1.StatefulSet) bool {
+	conditions := cluster.Status().Conditions
+	conditionInitializedTrue := condition.True(api.CrdbInitializedCondition, conditions)
+
+	// In order to migrate a running cluster to or from TLS,
+	// - the cluster must be initialized
+	// - a migration must be in progress, or the security mode of the statefulset deployed
+	//   must not match tlsEnabled
+
+	if !conditionInitializedTrue {
+		return false
+	}
+	if cluster.Status().TLSMigration != nil {
+		return true
+	}
+
+	deployed, ok := resource.StatefulSetTLSEnabled(ss)
+	return ok && deployed != cluster.Spec().TLSEnabled
+}
+
+// certificateExpiresSoon returns true if the node certificate issued by the operator expires
+// within the renewal delay.
+func certificateExpiresSoon(cluster *resource.Cluster) bool {
+	expiration, err := time.Parse(time.RFC3339, cluster.GetAnnotationCertExpiration())
+	if err != nil {
+		return false
+	}
+	return time.Until(expiration) < cluster.CertificateRenewBefore()
+}
+
 func (cd *clusterDirector) needsPartitionedUpdate(cluster *resource.Cluster, ss *appsv1.StatefulSet) bool {
 	conditions := cluster.Status().Conditions
 	featureVersionValidatorEnabled := utilfeature.DefaultMutableFeatureGate.Enabled(features.CrdbVersionValidator)
@@ -254,7 +370,9 @@ func (cd *clusterDirector) needsPartitionedUpdate(cluster *resource.Cluster, ss
 	// In order to do a partitioned update,
 	// - the cluster should be initialized
 	// - if the version validator is enabled, the version must be checked
-	// - the current and desired versions should be non-empty and they must not match
+	// - the update must be paused, soaking or rolled back, or
+	// - the current and desired versions should be non-empty and they must not match, and
+	//   the change must not be a dry run that was already previewed
 
 	if !conditionInitializedTrue {
 		return false
@@ -262,10 +380,56 @@ func (cd *clusterDirector) needsPartitionedUpdate(cluster *resource.Cluster, ss
 	if featureVersionValidatorEnabled && !conditionVersionCheckedTrue {
 		return false
 	}
+	// Only the partitioned update may resume a paused or soaking update, a deploy would
+	// roll the pods that are not canaries all at once. The same goes for a rolled back
+	// update, a deploy would move every pod to the version that failed.
+	if condition.True(api.UpgradePausedCondition, conditions) || cluster.Status().UpgradeSoakStartTime != nil ||
+		cluster.Status().RolledBackVersion != "" {
+		return true
+	}
 
 	versionWanted := cluster.GetVersionAnnotation()
 	currentVersion := ss.Annotations[resource.CrdbVersionAnnotation]
-	return currentVersion != versionWanted && currentVersion != "" && versionWanted != ""
+	return currentVersion != versionWanted && currentVersion != "" && versionWanted != "" &&
+		!upgradePreviewed(cluster, ss)
+}
+
+// upgradePreviewed returns true if a dry run holds a version change whose preview is already
+// recorded in the status.
+func upgradePreviewed(cluster *resource.Cluster, ss *appsv1.StatefulSet) bool {
+	preview := cluster.Status().UpgradePreview
+	if !cluster.UpgradeDryRun() || preview == nil {
+		return false
+	}
+	currentVersion := ss.Annotations[resource.CrdbVersionAnnotation]
+	return currentVersion != "" && preview.FromVersion == currentVersion &&
+		preview.ToVersion == cluster.GetVersionAnnotation()
+}
+
+func (cd *clusterDirector) needsStorageMigration(cluster *resource.Cluster, ss *appsv1.StatefulSet) bool {
+	conditions := cluster.Status().Conditions
+	featureDecommissionEnabled := utilfeature.DefaultMutableFeatureGate.Enabled(features.Decommission)
+	conditionInitializedTrue := condition.True(api.CrdbInitializedCondition, conditions)
+
+	// In order to migrate the data store to a new storage class,
+	// - the decommission feature must be enabled, the nodes are decommissioned to be replaced
+	// - the cluster must be initialized
+	// - a migration must be in progress, or the storage class of the data store volume claim template
+	//   deployed must not match the storage class currently specified
+
+	if !featureDecommissionEnabled {
+		return false
+	}
+	if !conditionInitializedTrue {
+		return false
+	}
+	if cluster.Status().StorageMigration != nil {
+		return true
+	}
+
+	template := resource.DataStoreClaimTemplate(ss)
+	return template != nil && cluster.Spec().DataStore.VolumeClaim != nil &&
+		claimStorageClass(template) != cluster.StorageClassName()
 }
 
 func (cd *clusterDirector) needsPVCResize(cluster *resource.Cluster, ss *appsv1.StatefulSet) bool {
@@ -276,8 +440,8 @@ func (cd *clusterDirector) needsPVCResize(cluster *resource.Cluster, ss *appsv1.
 	// In order to resize PVCs,
 	// - the resize PVC feature should be enabled
 	// - the cluster must be initialized
-	// - the data store's volume claim must not be nil and the stateful set must specify nonzero volume claim templates
-	// - the size of the PVCs deployed must not match the size currently specified
+	// - the size of one of the volume claim templates deployed, for the data store, an additional store or the
+	//   WAL failover volume, must not match the size currently specified
 
 	if !featureResizePVCEnabled {
 		return false
@@ -286,12 +450,56 @@ func (cd *clusterDirector) needsPVCResize(cluster *resource.Cluster, ss *appsv1.
 		return false
 	}
 
-	if cluster.Spec().DataStore.VolumeClaim == nil || len(ss.Spec.VolumeClaimTemplates) == 0 {
+	return len(claimTemplatesToResize(cluster, ss)) > 0
+}
+
+func (cd *clusterDirector) needsSnapshot(cluster *resource.Cluster) bool {
+	conditions := cluster.Status().Conditions
+	conditionInitializedTrue := condition.True(api.CrdbInitializedCondition, conditions)
+
+	// In order to take or check a snapshot,
+	// - the cluster must be initialized
+	// - the snapshot annotation must name a snapshot that was not taken yet, or a snapshot taken
+	//   must be neither ready to use nor failed
+
+	if !conditionInitializedTrue {
+		return false
+	}
+
+	if name := cluster.GetAnnotationSnapshot(); name != "" && cluster.Snapshot(name) == nil {
+		return true
+	}
+	for _, snapshot := range cluster.Status().Snapshots {
+		if !snapshot.ReadyToUse && snapshot.Error == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (cd *clusterDirector) needsPVCAutoExpand(cluster *resource.Cluster) bool {
+	conditions := cluster.Status().Conditions
+	featureResizePVCEnabled := utilfeature.DefaultMutableFeatureGate.Enabled(features.ResizePVC)
+	conditionInitializedTrue := condition.True(api.CrdbInitializedCondition, conditions)
+
+	// In order to check the capacity of the stores,
+	// - the data store must have auto-expand enabled
+	// - the resize PVC feature should be enabled, it resizes the volumes once expanded
+	// - the cluster must be initialized
+	// - the auto-expand interval must have passed since the last check
+
+	if cluster.Spec().DataStore.AutoExpand == nil {
 		return false
 	}
-	stsStorageSizeDeployed := ss.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage()
-	stsStorageSizeSet := cluster.Spec().DataStore.VolumeClaim.PersistentVolumeClaimSpec.Resources.Requests.Storage()
-	return !stsStorageSizeDeployed.Equal(stsStorageSizeSet.DeepCopy())
+	if !featureResizePVCEnabled {
+		return false
+	}
+	if !conditionInitializedTrue {
+		return false
+	}
+
+	status := cluster.Status().AutoExpand
+	return status == nil || time.Since(status.LastCheckTime.Time) >= cluster.AutoExpandInterval()
 }
 
 func (cd *clusterDirector) needsDeploy(ctx context.Context, cluster *resource.Cluster, log logr.Logger) (bool, error) {
@@ -305,7 +513,8 @@ func (cd *clusterDirector) needsDeploy(ctx context.Context, cluster *resource.Cl
 	// - the cluster initialized condition must be true or false (not unknown)
 	// - if the version validator is enabled, the version must be checked
 	// - at least one of the discovery service, public service, stateful set, and pod distribution budget specs must have
-	//   changed in some way
+	//   changed in some way, a disabled SQL or UI service or NetworkPolicy must be removed, the address of the SQL
+	//   load balancer must have changed, or the encryption status must be refreshed
 
 	if !conditionInitializedTrue && !conditionInitializedFalse {
 		return false, nil
@@ -322,12 +531,7 @@ func (cd *clusterDirector) needsDeploy(ctx context.Context, cluster *resource.Cl
 	}
 
 	labelSelector := r.Labels.Selector(cluster.Spec().AdditionalLabels)
-	builders := []resource.Builder{
-		resource.DiscoveryServiceBuilder{Cluster: cluster, Selector: labelSelector},
-		resource.PublicServiceBuilder{Cluster: cluster, Selector: labelSelector},
-		resource.StatefulSetBuilder{Cluster: cluster, Selector: labelSelector, Telemetry: kubernetesDistro},
-		resource.PdbBuilder{Cluster: cluster, Selector: labelSelector},
-	}
+	builders := deployBuilders(cluster, labelSelector, kube.TelemetryChannel(kubernetesDistro), nil)
 
 	for _, b := range builders {
 		hasChanged, err := resource.Reconciler{
@@ -343,7 +547,27 @@ func (cd *clusterDirector) needsDeploy(ctx context.Context, cluster *resource.Cl
 			return true, nil
 		}
 	}
-	return false, nil
+
+	for _, obj := range disabledResources(cluster) {
+		if owned, err := isOwned(ctx, cd.client, cluster, obj); err != nil {
+			return false, err
+		} else if owned {
+			return true, nil
+		}
+	}
+
+	if encryptionStatusStale(cluster) {
+		return true, nil
+	}
+
+	if cluster.SQLExternalHost() != "" {
+		return false, nil
+	}
+	address, err := sqlLoadBalancerAddress(ctx, cd.clientset, cluster)
+	if err != nil {
+		return false, err
+	}
+	return address != cluster.Status().SQLHost, nil
 }
 
 func (cd *clusterDirector) needsInitialization(cluster *resource.Cluster) bool {
@@ -365,7 +589,7 @@ func (cd *clusterDirector) needsInitialization(cluster *resource.Cluster) bool {
 	return true
 }
 
-func (cd *clusterDirector) processIngress(ctx context.Context, cluster *resource.Cluster) (bool, error) {
+func (cd *clusterDirector) processIngress(ctx context.Context, cluster *resource.Cluster, log logr.Logger) (bool, error) {
 	conditions := cluster.Status().Conditions
 	conditionInitializedTrue := condition.True(api.CrdbInitializedCondition, conditions)
 	uiIngressConditionTrue := condition.True(api.CrdbUIIngressExposedCondition, conditions)
@@ -373,7 +597,7 @@ func (cd *clusterDirector) processIngress(ctx context.Context, cluster *resource
 
 	// In order to expose ingress,
 	// - the cluster initialized condition must be true
-	// - if there is a change in ingress resource
+	// - if there is a change in ingress resource, or a resource of the other mode (Ingress or Route) must be removed
 
 	if !conditionInitializedTrue {
 		return false, nil
@@ -384,17 +608,21 @@ func (cd *clusterDirector) processIngress(ctx context.Context, cluster *resource
 		return true, nil
 	}
 
-	v1Ingress := cd.actors[api.ExposeIngressAction].(*exposeIngress).v1Ingress
+	ei := cd.actors[api.ExposeIngressAction].(*exposeIngress)
 
 	r := resource.NewManagedKubeResource(ctx, cd.client, cluster, kube.AnnotatingPersister)
 
 	labelSelector := r.Labels.Selector(cluster.Spec().AdditionalLabels)
 
 	if cluster.IsIngressNeeded() {
+		routeMode := routeMode(cluster)
+		// the actor reports the missing Route API
+		if routeMode && !ei.routeAPI {
+			return true, nil
+		}
 
-		var builders []resource.Builder
-		ui := resource.UIIngressBuilder{Cluster: cluster, Labels: labelSelector, V1Ingress: v1Ingress}
-		sql := resource.SQLIngressBuilder{Cluster: cluster, Labels: labelSelector, V1Ingress: v1Ingress}
+		var builders, previous []resource.Builder
+		selected, other := ei.ingressBuilders(cluster, labelSelector, routeMode)
 		uiIngressEnabled := cluster.IsUIIngressEnabled()
 		sqlIngressEnabled := cluster.IsSQLIngressEnabled()
 
@@ -403,11 +631,13 @@ func (cd *clusterDirector) processIngress(ctx context.Context, cluster *resource
 		}
 
 		if uiIngressEnabled {
-			builders = append(builders, ui)
+			builders = append(builders, selected.ui)
+			previous = append(previous, other.ui)
 		}
 
 		if sqlIngressEnabled {
-			builders = append(builders, sql)
+			builders = append(builders, selected.sql)
+			previous = append(previous, other.sql)
 		}
 
 		for _, b := range builders {
@@ -424,7 +654,84 @@ func (cd *clusterDirector) processIngress(ctx context.Context, cluster *resource
 				return true, nil
 			}
 		}
+
+		for _, b := range previous {
+			if exists, err := ei.exists(r, b); err != nil {
+				return false, err
+			} else if exists {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (cd *clusterDirector) processGateway(ctx context.Context, cluster *resource.Cluster) (bool, error) {
+	conditionInitializedTrue := condition.True(api.CrdbInitializedCondition, cluster.Status().Conditions)
+
+	// In order to expose gateway routes,
+	// - the cluster initialized condition must be true
+	// - a route must be created or changed, or a route that is no longer configured must be removed
+
+	if !conditionInitializedTrue {
+		return false, nil
+	}
+
+	available := cd.actors[api.ExposeGatewayAction].(*exposeGateway).available
+
+	r := resource.NewManagedKubeResource(ctx, cd.client, cluster, kube.AnnotatingPersister)
+	labelSelector := r.Labels.Selector(cluster.Spec().AdditionalLabels)
+	enabled, disabled := routeBuilders(cluster, labelSelector)
+
+	for _, b := range disabled {
+		if b.condition != "" {
+			return true, nil
+		}
+		route := b.builder.Placeholder()
+		if !available[route.GetObjectKind().GroupVersionKind()] {
+			continue
+		}
+		if err := r.Fetch(route); err == nil {
+			return true, nil
+		} else if !kube.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	for _, b := range enabled {
+		// the actor reports the missing Gateway API
+		if !available[b.builder.Placeholder().GetObjectKind().GroupVersionKind()] {
+			return true, nil
+		}
+
+		hasChanged, err := resource.Reconciler{
+			ManagedResource: r,
+			Builder:         b.builder,
+			Owner:           cluster.Unwrap(),
+			Scheme:          cd.scheme,
+		}.HasChanged()
+
+		if err != nil {
+			return false, err
+		} else if hasChanged || !condition.True(b.condition, cluster.Status().Conditions) {
+			return true, nil
+		}
 	}
 
 	return false, nil
 }
+
+func (cd *clusterDirector) needsUpgradeFinalization(cluster *resource.Cluster) bool {
+	conditions := cluster.Status().Conditions
+	conditionInitializedTrue := condition.True(api.CrdbInitializedCondition, conditions)
+	conditionUpgradeFinalizedFalse := condition.False(api.UpgradeFinalizedCondition, conditions)
+
+	// In order to finalize an upgrade,
+	// - the cluster must be initialized
+	// - a completed major upgrade must not be finalized yet
+	// - the auto-finalize delay must have passed, or the next check of a manual finalization must be due;
+	//   the other actors run in the meantime and the cluster is reconciled again once it is due
+
+	return conditionInitializedTrue && conditionUpgradeFinalizedFalse && cluster.UpgradeFinalizationWait(time.Now()) == 0
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"testing"
+	"time"
 
 	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/actor"
@@ -27,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach-operator/pkg/labels"
 	"github.com/cockroachdb/cockroach-operator/pkg/resource"
 	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/cockroachdb/cockroach-operator/pkg/utilfeature"
 	"github.com/go-logr/zapr"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
@@ -38,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -153,7 +156,7 @@ func createTestDirectorAndStableCluster(t *testing.T) (*resource.Cluster, actor.
 	client := testutil.NewFakeClient(scheme, objs...)
 	clientset := fake.NewSimpleClientset(objs...)
 	config := &rest.Config{}
-	director := actor.NewDirector(scheme, client, config, clientset)
+	director := actor.NewDirector(scheme, client, config, clientset, record.NewFakeRecorder(10))
 
 	return cluster, director, clientset
 }
@@ -219,6 +222,37 @@ func TestNeedsDecommission(t *testing.T) {
 	require.Equal(t, api.DeployAction, actor.GetActionType())
 }
 
+func TestNeedsDecommissionToPruneRetainedPVCs(t *testing.T) {
+	require.NoError(t, utilfeature.DefaultMutableFeatureGate.Set("AutoPrunePVC=true"))
+	defer func() {
+		require.NoError(t, utilfeature.DefaultMutableFeatureGate.Set("AutoPrunePVC=false"))
+	}()
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+
+	// The last decommission retained PVCs, the other actions run until they are checked again
+	cluster.SetRetainedPVCsStatus(&api.RetainedPVCsStatus{
+		PVCs:          []string{"datadir-crdb-3"},
+		NextCheckTime: metav1.NewTime(time.Now().Add(time.Hour)),
+	})
+	actor, err := director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+
+	cluster.SetRetainedPVCsStatus(&api.RetainedPVCsStatus{
+		PVCs:          []string{"datadir-crdb-3"},
+		NextCheckTime: metav1.NewTime(time.Now().Add(-time.Second)),
+	})
+	actor, err = director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.DecommissionAction, actor.GetActionType())
+
+	// The PVCs are pruned
+	cluster.SetRetainedPVCsStatus(nil)
+	actor, err = director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+}
+
 func TestNeedsVersionCheck(t *testing.T) {
 	cluster, director, _ := createTestDirectorAndStableCluster(t)
 
@@ -248,12 +282,56 @@ func TestNeedsCertificate(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, api.GenerateCertAction, actor.GetActionType())
 
-	// Make a change that disables this actor, and check that it's no longer triggered
+	// Make a change that disables this actor, and check that it's no longer triggered, the
+	// running cluster is migrated to TLS
 	updated.Spec.NodeTLSSecret = "soylent.green.is.people"
 	newCluster = resource.NewCluster(updated)
 	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
 	require.Nil(t, err)
-	require.Equal(t, api.DeployAction, actor.GetActionType())
+	require.Equal(t, api.MigrateTLSAction, actor.GetActionType())
+}
+
+func TestNeedsCertificateRenewal(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+	cluster.SetTrue(api.CertificateGenerated)
+	updated := cluster.Unwrap()
+
+	// Trigger certificate generation by issuing the certificates from a provided CA
+	updated.Spec.Certificates = &api.CertificatesConfig{CASecret: "corporate-ca", DNSNames: []string{"db.example.com"}}
+
+	newCluster := resource.NewCluster(updated)
+	actor, err := director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.GenerateCertAction, actor.GetActionType())
+
+	// The certificates were issued from the CA
+	newCluster.SetCertificatesStatus(&api.CertificatesStatus{CASecret: "corporate-ca", SANs: []string{"db.example.com"}})
+	newCluster.SetAnnotationCertExpiration(time.Now().Add(365 * 24 * time.Hour).Format(time.RFC3339))
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+
+	// Requesting the client certificate of a SQL user registers the finalizer that deletes its Secret
+	updated = newCluster.Unwrap()
+	updated.Spec.Certificates.Clients = []api.ClientCertificate{{User: "app"}}
+	newCluster = resource.NewCluster(updated)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.RetainVolumesAction, actor.GetActionType())
+
+	// Trigger certificate generation by requesting the client certificate of a SQL user
+	updated.Finalizers = append(updated.Finalizers, api.CrdbClusterFinalizer)
+	newCluster = resource.NewCluster(updated)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.GenerateCertAction, actor.GetActionType())
+
+	// Trigger certificate generation when the node certificate expires soon
+	newCluster.SetCertificatesStatus(newCluster.CertificatesStatus())
+	newCluster.SetAnnotationCertExpiration(time.Now().Add(7 * 24 * time.Hour).Format(time.RFC3339))
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.GenerateCertAction, actor.GetActionType())
 }
 
 func TestNeedsUpdate(t *testing.T) {
@@ -276,6 +354,99 @@ func TestNeedsUpdate(t *testing.T) {
 	require.Equal(t, api.DeployAction, actor.GetActionType())
 }
 
+func TestNeedsUpdateWhenPaused(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+
+	// A paused update keeps the partitioned update in charge even though the
+	// statefulset already carries the wanted version
+	cluster.SetTrue(api.UpgradePausedCondition)
+	actor, err := director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.PartitionedUpdateAction, actor.GetActionType())
+
+	cluster.SetFalse(api.UpgradePausedCondition)
+	actor, err = director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+}
+
+func TestNeedsUpdateWhenSoaking(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+
+	// The partitioned update continues once the canaries have soaked
+	start := metav1.Now()
+	cluster.SetUpgradeSoakStartTime(&start)
+	actor, err := director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.PartitionedUpdateAction, actor.GetActionType())
+
+	cluster.SetUpgradeSoakStartTime(nil)
+	actor, err = director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+}
+
+func TestNeedsUpdateWhenDryRun(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+	updated := cluster.Unwrap()
+	updated.Annotations[resource.CrdbVersionAnnotation] = "fake.version.2"
+	updated.Spec.Upgrade = &api.UpgradeSpec{DryRun: true}
+
+	// The partitioned update previews the version change
+	newCluster := resource.NewCluster(updated)
+	actor, err := director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.PartitionedUpdateAction, actor.GetActionType())
+
+	// Once previewed, the other actors run with the version deployed
+	newCluster.SetUpgradePreview(&api.UpgradePreview{FromVersion: "fake.version", ToVersion: "fake.version.2"})
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+	require.Equal(t, "fake.version", newCluster.GetVersionAnnotation())
+}
+
+func TestNeedsUpdateWhenRolledBack(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+
+	// The partitioned update keeps a deploy from moving the pods to the version that
+	// was rolled back
+	cluster.SetRolledBackVersion("fake.version.next")
+	actor, err := director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.PartitionedUpdateAction, actor.GetActionType())
+}
+
+func TestNeedsUpgradeFinalization(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+
+	cluster.SetUpgradeFinalization("24.1")
+	actor, err := director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.FinalizeUpgradeAction, actor.GetActionType())
+
+	// The other actors run until the next check of a manual finalization
+	cluster.SetUpgradeFinalizationCheckTime(time.Now().Add(5 * time.Minute))
+	actor, err = director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+	require.Greater(t, cluster.UpgradeFinalizationWait(time.Now()), 4*time.Minute)
+
+	// and until the auto-finalize delay has passed
+	updated := cluster.Unwrap()
+	updated.Spec.Upgrade = &api.UpgradeSpec{Finalize: api.UpgradeFinalizeAuto, AutoFinalizeDelay: &metav1.Duration{Duration: time.Hour}}
+	autoCluster := resource.NewCluster(updated)
+	actor, err = director.GetActorToExecute(context.Background(), &autoCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+
+	cluster.SetUpgradeFinalization("")
+	actor, err = director.GetActorToExecute(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+	require.Zero(t, cluster.UpgradeFinalizationWait(time.Now()))
+}
+
 func TestNeedsPVCResize(t *testing.T) {
 	cluster, director, _ := createTestDirectorAndStableCluster(t)
 	updated := cluster.Unwrap()
@@ -296,6 +467,152 @@ func TestNeedsPVCResize(t *testing.T) {
 	require.Equal(t, api.DeployAction, actor.GetActionType())
 }
 
+func TestNeedsStorageMigration(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+	updated := cluster.Unwrap()
+
+	// Trigger a storage class migration by changing the storage class
+	storageClass := "ssd"
+	updated.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.StorageClassName = &storageClass
+
+	newCluster := resource.NewCluster(updated)
+	actor, err := director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.MigrateStorageAction, actor.GetActionType())
+
+	// The migration removes the node it added itself, the decommission waits for the end of the migration
+	updated.Spec.Nodes = 3
+	updated.Status.StorageMigration = &api.StorageMigrationStatus{StorageClass: storageClass, Node: 2}
+	newCluster = resource.NewCluster(updated)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.MigrateStorageAction, actor.GetActionType())
+
+	// Make a change that disables this actor, and check that it's no longer triggered
+	newCluster.SetFalse(api.CrdbInitializedCondition)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.DeployAction, actor.GetActionType())
+}
+
+func TestNeedsTLSMigration(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+	updated := cluster.Unwrap()
+
+	// Trigger a TLS migration by enabling TLS with a certificate provided
+	updated.Spec.TLSEnabled = true
+	updated.Spec.NodeTLSSecret = "node-certs"
+	updated.Spec.ClientTLSSecret = "client-certs"
+
+	newCluster := resource.NewCluster(updated)
+	actor, err := director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.MigrateTLSAction, actor.GetActionType())
+
+	// The statefulset is updated first, the migration continues until all the nodes restarted
+	updated.Spec.TLSEnabled = false
+	updated.Status.TLSMigration = &api.TLSMigrationStatus{TLSEnabled: true, Phase: api.TLSMigrationRestarting}
+	newCluster = resource.NewCluster(updated)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.MigrateTLSAction, actor.GetActionType())
+
+	// Make a change that disables this actor, and check that it's no longer triggered
+	newCluster.SetFalse(api.CrdbInitializedCondition)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.InitializeAction, actor.GetActionType())
+}
+
+func TestNeedsPVCAutoExpand(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+	updated := cluster.Unwrap()
+
+	// Trigger a capacity check by enabling auto-expand
+	updated.Spec.DataStore.AutoExpand = &api.AutoExpandConfig{
+		Increment: apiresource.MustParse("1Gi"),
+		MaxSize:   apiresource.MustParse("10Gi"),
+	}
+
+	newCluster := resource.NewCluster(updated)
+	actor, err := director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.ExpandPVCAction, actor.GetActionType())
+
+	// The stores were checked recently, the next check waits for the interval
+	newCluster.SetAutoExpandStatus(&api.AutoExpandStatus{LastCheckTime: metav1.Now()})
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+
+	// An expanded data store is resized
+	size := apiresource.MustParse("2Gi")
+	newCluster.SetAutoExpandStatus(&api.AutoExpandStatus{Size: &size, LastCheckTime: metav1.Now()})
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.ResizePVCAction, actor.GetActionType())
+}
+
+func TestNeedsVolumeRetention(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+	updated := cluster.Unwrap()
+
+	// The finalizer is added for the Delete retention policy
+	updated.Spec.DataStore.RetentionPolicy = api.DeletePolicy
+
+	newCluster := resource.NewCluster(updated)
+	actor, err := director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.RetainVolumesAction, actor.GetActionType())
+
+	updated.Finalizers = []string{api.CrdbClusterFinalizer}
+	newCluster = resource.NewCluster(updated)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+
+	// The volumes are handled once the cluster is deleted
+	now := metav1.Now()
+	updated.DeletionTimestamp = &now
+	newCluster = resource.NewCluster(updated)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.RetainVolumesAction, actor.GetActionType())
+
+	// Nothing else is reconciled for a deleted cluster
+	updated.Finalizers = nil
+	updated.Spec.Nodes = 5
+	newCluster = resource.NewCluster(updated)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+}
+
+func TestNeedsSnapshot(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+	updated := cluster.Unwrap()
+
+	// Trigger a snapshot with the annotation
+	updated.Annotations = map[string]string{resource.CrdbSnapshotAnnotation: "nightly"}
+
+	newCluster := resource.NewCluster(updated)
+	actor, err := director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.SnapshotAction, actor.GetActionType())
+
+	// The snapshot is taken, its VolumeSnapshots are checked until they are ready to use
+	newCluster.SetSnapshotStatus(api.SnapshotStatus{Name: "nightly", CreationTime: metav1.Now()})
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.SnapshotAction, actor.GetActionType())
+
+	// The snapshot is ready to use, there is nothing left to do
+	newCluster.SetSnapshotStatus(api.SnapshotStatus{Name: "nightly", CreationTime: metav1.Now(), ReadyToUse: true})
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Nil(t, actor)
+}
+
 func TestNeedsDeploy(t *testing.T) {
 	cluster, director, _ := createTestDirectorAndStableCluster(t)
 	updated := cluster.Unwrap()
@@ -357,6 +674,38 @@ func TestNeedsIngress(t *testing.T) {
 	require.Equal(t, api.InitializeAction, actor.GetActionType())
 }
 
+func TestNeedsGateway(t *testing.T) {
+	cluster, director, _ := createTestDirectorAndStableCluster(t)
+	updated := cluster.Unwrap()
+
+	// Trigger expose gateway by adding a route
+	updated.Spec.Gateway = &api.GatewayConfig{
+		UI: &api.GatewayRoute{
+			ParentRef: api.GatewayParentReference{Name: "gateway"},
+			Host:      "ui.test.com",
+		}}
+
+	newCluster := resource.NewCluster(updated)
+	actor, err := director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.ExposeGatewayAction, actor.GetActionType())
+
+	// Removing the route of an exposed cluster removes the route
+	updated = newCluster.Unwrap()
+	updated.Spec.Gateway = nil
+	newCluster = resource.NewCluster(updated)
+	newCluster.SetTrue(api.CrdbUIRouteExposedCondition)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.ExposeGatewayAction, actor.GetActionType())
+
+	// Make a change that disables this actor, and check that it's no longer triggered
+	newCluster.SetFalse(api.CrdbInitializedCondition)
+	actor, err = director.GetActorToExecute(context.Background(), &newCluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.Nil(t, err)
+	require.Equal(t, api.InitializeAction, actor.GetActionType())
+}
+
 // Make successive changes to the cluster and check that each change triggers an actor earlier in the order
 func TestOrderOfActors(t *testing.T) {
 	cluster, director, clientset := createTestDirectorAndStableCluster(t)
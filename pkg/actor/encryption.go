@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/condition"
+	"github.com/cockroachdb/cockroach-operator/pkg/features"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/utilfeature"
+	"github.com/cockroachdb/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// encryptionKeyRotation returns true if the statefulset exists and was deployed with another
+// store key than the active key of the cluster.
+func (d deploy) encryptionKeyRotation(ctx context.Context, cluster *resource.Cluster) (bool, error) {
+	key := kubetypes.NamespacedName{Namespace: cluster.Namespace(), Name: cluster.StatefulSetName()}
+	ss := &appsv1.StatefulSet{}
+	if err := d.client.Get(ctx, key, ss); kube.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return ss.Annotations[resource.CrdbEncryptionKeyAnnotation] != cluster.EncryptionKeyReference(), nil
+}
+
+// managedRestartEnabled returns true if the operator restarts the cluster when the restart
+// annotation is set.
+func managedRestartEnabled() bool {
+	return utilfeature.DefaultMutableFeatureGate.Enabled(features.ClusterRestart) &&
+		utilfeature.DefaultMutableFeatureGate.Enabled(features.CrdbVersionValidator)
+}
+
+// requestRollingRestart sets the restart annotation on the cluster for the cluster restart
+// action to restart the pods one at a time.
+func requestRollingRestart(ctx context.Context, cl client.Client, cluster *resource.Cluster) error {
+	fetcher := resource.NewKubeFetcher(ctx, cluster.Namespace(), cl)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cr := resource.ClusterPlaceholder(cluster.Name())
+		if err := fetcher.Fetch(cr); err != nil {
+			return errors.Wrap(err, "failed to retrieve CrdbCluster resource")
+		}
+		refreshedCluster := resource.NewCluster(cr)
+		refreshedCluster.SetRestartTypeAnnotation(api.ClusterRestartType(api.RollingRestart).String())
+		return cl.Update(ctx, refreshedCluster.Unwrap())
+	})
+}
+
+// encryptionStatusStale returns true if the encryption status of an initialized cluster does not
+// report the active key of every node, or if the status of a cluster without encryption has to be cleared.
+func encryptionStatusStale(cluster *resource.Cluster) bool {
+	status := cluster.Status().Encryption
+	if !cluster.IsEncryptionEnabled() {
+		return status != nil
+	}
+	if !condition.True(api.CrdbInitializedCondition, cluster.Status().Conditions) {
+		return false
+	}
+	if status == nil || status.ActiveKey != cluster.EncryptionKeyReference() || len(status.Nodes) != int(cluster.Spec().Nodes) {
+		return true
+	}
+	stores := len(resource.StoreMountPaths(cluster))
+	for _, node := range status.Nodes {
+		if len(node.Stores) != stores {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptionStatus returns the active store key ID reported by each store of each node once all the
+// pods run the current revision of the statefulset, or nil if the store is not encrypted.
+func (d deploy) encryptionStatus(ctx context.Context, cluster *resource.Cluster) (*api.EncryptionStatus, error) {
+	if !cluster.IsEncryptionEnabled() {
+		return nil, nil
+	}
+
+	key := kubetypes.NamespacedName{Namespace: cluster.Namespace(), Name: cluster.StatefulSetName()}
+	ss := &appsv1.StatefulSet{}
+	if err := d.client.Get(ctx, key, ss); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch statefulset")
+	}
+	if statefulSetIsUpdating(ss) || ss.Status.ReadyReplicas < cluster.Spec().Nodes {
+		return nil, NotReadyErr{Err: errors.New("statefulset is updating, waiting to report the encryption status")}
+	}
+
+	status := &api.EncryptionStatus{ActiveKey: cluster.EncryptionKeyReference()}
+	paths := resource.StoreMountPaths(cluster)
+	for i := int32(0); i < cluster.Spec().Nodes; i++ {
+		pod := fmt.Sprintf("%s-%d", cluster.StatefulSetName(), i)
+		outputs := make([]string, 0, len(paths))
+		for _, path := range paths {
+			cmd := []string{"/cockroach/cockroach", "debug", "encryption-active-key", path}
+			stdout, stderr, err := kube.ExecInPod(ctx, d.scheme, d.config, cluster.Namespace(), pod, resource.DbContainerName, cmd)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get the active store key of the store %s of pod %s: %s", path, pod, stderr)
+			}
+			outputs = append(outputs, stdout)
+		}
+		status.Nodes = append(status.Nodes, nodeEncryptionStatus(pod, paths, outputs))
+	}
+	return status, nil
+}
+
+// nodeEncryptionStatus returns the encryption status of a node from the output of
+// `cockroach debug encryption-active-key` for each of its stores, the first store being the data store.
+func nodeEncryptionStatus(pod string, paths, outputs []string) api.NodeEncryptionStatus {
+	status := api.NodeEncryptionStatus{Pod: pod}
+	for i, path := range paths {
+		status.Stores = append(status.Stores, api.StoreEncryptionStatus{Path: path, ActiveKeyID: activeKeyID(outputs[i])})
+	}
+	if len(status.Stores) > 0 {
+		status.ActiveKeyID = status.Stores[0].ActiveKeyID
+	}
+	return status
+}
+
+// activeKeyID parses the output of `cockroach debug encryption-active-key`, formatted as
+// <encryption type>:<key ID>, with an empty key ID if the store is not encrypted.
+func activeKeyID(output string) string {
+	output = strings.TrimSpace(output)
+	id := output[strings.LastIndex(output, ":")+1:]
+	if id == "" {
+		return api.EncryptionPlainKey
+	}
+	return id
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"testing"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveKeyID(t *testing.T) {
+	require.Equal(t, "be235c29239aa84a48e5e1874d76aebf7fb3c1bdc438cec2eb98de82f06a2b", actor.ActiveKeyID(
+		"AES128_CTR:be235c29239aa84a48e5e1874d76aebf7fb3c1bdc438cec2eb98de82f06a2b\n"))
+	require.Equal(t, api.EncryptionPlainKey, actor.ActiveKeyID("Plaintext:\n"))
+}
+
+func TestNodeEncryptionStatus(t *testing.T) {
+	status := actor.NodeEncryptionStatus("cockroachdb-0",
+		[]string{"/cockroach/cockroach-data", "/cockroach/stores/ssd"},
+		[]string{"AES128_CTR:be235c29\n", "Plaintext:\n"})
+
+	require.Equal(t, api.NodeEncryptionStatus{
+		Pod:         "cockroachdb-0",
+		ActiveKeyID: "be235c29",
+		Stores: []api.StoreEncryptionStatus{
+			{Path: "/cockroach/cockroach-data", ActiveKeyID: "be235c29"},
+			{Path: "/cockroach/stores/ssd", ActiveKeyID: api.EncryptionPlainKey},
+		},
+	}, status)
+}
+
+func TestEncryptionStatusStale(t *testing.T) {
+	config := &api.EncryptionConfig{SecretName: "store-keys", ActiveKey: "aes-128.key", OldKey: api.EncryptionPlainKey}
+	rotated := &api.EncryptionConfig{SecretName: "store-keys", ActiveKey: "aes-128-new.key", OldKey: "aes-128.key"}
+	reported := &api.EncryptionStatus{
+		ActiveKey: "store-keys/aes-128.key",
+		Nodes: []api.NodeEncryptionStatus{
+			{Pod: "cockroachdb-0", ActiveKeyID: "be235c29", Stores: []api.StoreEncryptionStatus{{Path: "/cockroach/cockroach-data", ActiveKeyID: "be235c29"}}},
+			{Pod: "cockroachdb-1", ActiveKeyID: "be235c29", Stores: []api.StoreEncryptionStatus{{Path: "/cockroach/cockroach-data", ActiveKeyID: "be235c29"}}},
+		},
+	}
+	perNode := &api.EncryptionStatus{
+		ActiveKey: "store-keys/aes-128.key",
+		Nodes: []api.NodeEncryptionStatus{
+			{Pod: "cockroachdb-0", ActiveKeyID: "be235c29"},
+			{Pod: "cockroachdb-1", ActiveKeyID: "be235c29"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		config      *api.EncryptionConfig
+		initialized bool
+		status      *api.EncryptionStatus
+		expected    bool
+	}{
+		{name: "not initialized", config: config},
+		{name: "not reported", config: config, initialized: true, expected: true},
+		{name: "reported", config: config, initialized: true, status: reported},
+		{name: "not reported per store", config: config, initialized: true, status: perNode, expected: true},
+		{name: "rotated", config: rotated, initialized: true, status: reported, expected: true},
+		{name: "disabled", initialized: true, status: reported, expected: true},
+		{name: "disabled and cleared", initialized: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := testutil.NewBuilder("cockroachdb").Namespaced("default").WithNodeCount(2).
+				WithEncryption(tt.config).WithStatus(api.CrdbClusterStatus{Encryption: tt.status}).Cluster()
+			if tt.initialized {
+				cluster.SetTrue(api.CrdbInitializedCondition)
+			}
+			require.Equal(t, tt.expected, actor.EncryptionStatusStale(cluster))
+		})
+	}
+}
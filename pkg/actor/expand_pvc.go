@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"time"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/healthchecker"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newExpandPVC creates and returns a new expandPVC struct
+func newExpandPVC(cl client.Client, config *rest.Config, clientset kubernetes.Interface, recorder record.EventRecorder) Actor {
+	return &expandPVC{
+		action:   newAction(nil, cl, config, clientset),
+		recorder: recorder,
+	}
+}
+
+// expandPVC checks the capacity of the stores and expands the persistent volumes of the
+// data store when their usage reaches the auto-expand threshold
+type expandPVC struct {
+	action
+
+	recorder record.EventRecorder
+}
+
+// GetActionType returns api.ExpandPVCAction action used to set the cluster status errors
+func (ep *expandPVC) GetActionType() api.ActionType {
+	return api.ExpandPVCAction
+}
+
+// Act reads the usage of the data store of every node. When it reaches the threshold, the size
+// the volumes are expanded to is saved in the status and in the crdb.io/datastoresize
+// annotation, and the volumes are resized by the resize PVC action. The next check is scheduled after the auto-expand interval.
+func (ep *expandPVC) Act(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+	log.V(DEBUGLEVEL).Info("checking the capacity of the stores")
+
+	healthChecker := healthchecker.NewHealthChecker(cluster, ep.clientset, ep.config)
+	usage, err := healthChecker.DataStoreUsage(log, cluster.Spec().Nodes)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the capacity of the stores")
+	}
+
+	if expanded := expandDataStore(cluster, usage, ep.recorder, time.Now(), log); expanded != nil {
+		if err := ep.saveDataStoreSize(ctx, cluster, expanded.String(), log); err != nil {
+			return err
+		}
+	}
+
+	interval := cluster.AutoExpandInterval()
+	return RequeueAfterErr{Err: errors.Newf("next capacity check in %s", interval), After: interval}
+}
+
+// expandDataStore records the usage of the data store in the status and, when it reaches the
+// threshold, the size the volumes are expanded to. Each expansion is recorded in an event, as
+// well as a threshold reached while the volumes are already at their maximum size. It returns
+// the expanded size, nil when the volumes are not expanded.
+func expandDataStore(cluster *resource.Cluster, usage int32, recorder record.EventRecorder, now time.Time, log logr.Logger) *apiresource.Quantity {
+	config := cluster.Spec().DataStore.AutoExpand
+	status := &api.AutoExpandStatus{
+		Usage:         usage,
+		LastCheckTime: metav1.NewTime(now),
+	}
+	if previous := cluster.Status().AutoExpand; previous != nil {
+		status.Size = previous.Size
+	}
+	defer cluster.SetAutoExpandStatus(status)
+
+	threshold := cluster.AutoExpandThreshold()
+	if usage < threshold {
+		return nil
+	}
+
+	size := cluster.DataStoreSize()
+	if size.Cmp(config.MaxSize) >= 0 {
+		log.Info("the data store reached the auto-expand threshold at its maximum size", "usage", usage, "size", size.String())
+		recorder.Eventf(cluster.Unwrap(), corev1.EventTypeWarning, "PVCExpansionLimitReached",
+			"Data store usage %d%% reached the threshold of %d%% but the volumes are at their maximum size %s",
+			usage, threshold, config.MaxSize.String())
+		return nil
+	}
+
+	expanded := size.DeepCopy()
+	expanded.Add(config.Increment)
+	if expanded.Cmp(config.MaxSize) > 0 {
+		expanded = config.MaxSize.DeepCopy()
+	}
+	status.Size = &expanded
+
+	log.Info("expanding the data store", "usage", usage, "from", size.String(), "to", expanded.String())
+	recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "ExpandingPVC",
+		"Data store usage %d%% reached the threshold of %d%%, expanding the volumes from %s to %s",
+		usage, threshold, size.String(), expanded.String())
+	return &expanded
+}
+
+// saveDataStoreSize writes the expanded size to the crdb.io/datastoresize annotation, so the
+// volume claim template keeps the expanded size when the status is lost.
+func (ep *expandPVC) saveDataStoreSize(ctx context.Context, cluster *resource.Cluster, size string, log logr.Logger) error {
+	fetcher := resource.NewKubeFetcher(ctx, cluster.Namespace(), ep.client)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		newcr := resource.ClusterPlaceholder(cluster.Name())
+		if err := fetcher.Fetch(newcr); err != nil {
+			return errors.Wrap(err, "failed to retrieve CrdbCluster resource")
+		}
+		refreshedCluster := resource.NewCluster(newcr)
+		refreshedCluster.SetAnnotationDataStoreSize(size)
+		return ep.client.Update(ctx, refreshedCluster.Unwrap())
+	})
+	if err != nil {
+		log.Error(err, "failed to save the expanded size of the data store")
+		return errors.Wrap(err, "failed to save the expanded size of the data store")
+	}
+
+	cluster.SetAnnotationDataStoreSize(size)
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/go-logr/zapr"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestExpandDataStore(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		size       string
+		annotation string
+		usage      int32
+		expected   string
+		expanded   bool
+		event      string
+	}{
+		{
+			name:  "below the threshold",
+			usage: 79,
+		},
+		{
+			name:     "expanded by the increment",
+			usage:    80,
+			expected: "6Gi",
+			expanded: true,
+			event:    "Normal ExpandingPVC",
+		},
+		{
+			name:       "expanded from the annotation when the status is lost",
+			annotation: "8Gi",
+			usage:      90,
+			expected:   "10Gi",
+			expanded:   true,
+			event:      "Normal ExpandingPVC",
+		},
+		{
+			name:     "expanded from the previous expansion",
+			size:     "8Gi",
+			usage:    90,
+			expected: "10Gi",
+			expanded: true,
+			event:    "Normal ExpandingPVC",
+		},
+		{
+			name:     "expanded up to the maximum size",
+			size:     "9Gi",
+			usage:    90,
+			expected: "10Gi",
+			expanded: true,
+			event:    "Normal ExpandingPVC",
+		},
+		{
+			name:     "at the maximum size",
+			size:     "10Gi",
+			usage:    95,
+			expected: "10Gi",
+			event:    "Warning PVCExpansionLimitReached",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := testutil.NewBuilder("crdb").WithPVDataStore("4Gi").Cr()
+			cr.Spec.DataStore.AutoExpand = &api.AutoExpandConfig{
+				Increment: apiresource.MustParse("2Gi"),
+				MaxSize:   apiresource.MustParse("10Gi"),
+			}
+			if tt.size != "" {
+				size := apiresource.MustParse(tt.size)
+				cr.Status.AutoExpand = &api.AutoExpandStatus{Size: &size}
+			}
+			if tt.annotation != "" {
+				cr.Annotations = map[string]string{resource.CrdbDataStoreSizeAnnotation: tt.annotation}
+			}
+			cluster := resource.NewCluster(cr)
+			recorder := record.NewFakeRecorder(1)
+
+			expanded := actor.ExpandDataStore(&cluster, tt.usage, recorder, now, zapr.NewLogger(zaptest.NewLogger(t)))
+			if tt.expanded {
+				require.NotNil(t, expanded)
+				require.Equal(t, tt.expected, expanded.String())
+			} else {
+				require.Nil(t, expanded)
+			}
+
+			status := cluster.Status().AutoExpand
+			require.NotNil(t, status)
+			require.Equal(t, tt.usage, status.Usage)
+			require.True(t, status.LastCheckTime.Time.Equal(now))
+			if tt.expected == "" {
+				require.Nil(t, status.Size)
+			} else {
+				require.Equal(t, tt.expected, status.Size.String())
+			}
+
+			if tt.event == "" {
+				require.Empty(t, recorder.Events)
+			} else {
+				require.Contains(t, <-recorder.Events, tt.event)
+			}
+		})
+	}
+}
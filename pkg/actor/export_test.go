@@ -17,3 +17,22 @@ limitations under the License.
 package actor
 
 var NewDeploy = newDeploy
+var NewFinalizeUpgrade = newFinalizeUpgrade
+var SQLLoadBalancerAddress = sqlLoadBalancerAddress
+var NewExposeIngress = newExposeIngress
+var RouteMode = routeMode
+var ActiveKeyID = activeKeyID
+var NodeEncryptionStatus = nodeEncryptionStatus
+var EncryptionStatusStale = encryptionStatusStale
+var ClaimTemplatesToResize = claimTemplatesToResize
+var ExpandDataStore = expandDataStore
+var ReplaceVolumes = replaceVolumes
+var RestoredClaims = restoredClaims
+var NewRetainVolumes = newRetainVolumes
+var CABundle = caBundle
+var CertificateSignedBy = certificateSignedBy
+var CertificateExpiresWithin = certificateExpiresWithin
+var ConnectionSecretData = connectionSecretData
+var PruneLater = pruneLater
+var ClientSecretOwned = clientSecretOwned
+var ClientSecretNamespaceAllowed = clientSecretNamespaceAllowed
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/condition"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/util"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newExposeGateway(scheme *runtime.Scheme, cl client.Client, config *rest.Config, clientset kubernetes.Interface) Actor {
+	available := map[schema.GroupVersionKind]bool{}
+	for _, gvk := range []schema.GroupVersionKind{resource.HTTPRouteGVK, resource.TLSRouteGVK, resource.TCPRouteGVK} {
+		available[gvk] = util.CheckIfAPIVersionKindAvailable(config, gvk.GroupVersion().String(), gvk.Kind)
+	}
+
+	return &exposeGateway{
+		action:    newAction(scheme, cl, nil, clientset),
+		available: available,
+	}
+}
+
+// exposeGateway reconciles the Gateway API routes exposing the CockroachDB cluster service
+type exposeGateway struct {
+	action
+	available map[schema.GroupVersionKind]bool
+}
+
+// GetActionType returns the api.ExposeGatewayAction value used to set the cluster status errors
+func (eg exposeGateway) GetActionType() api.ActionType {
+	return api.ExposeGatewayAction
+}
+
+func (eg exposeGateway) Act(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+	log.V(DEBUGLEVEL).Info("reconciling resources on expose gateway action")
+
+	owner := cluster.Unwrap()
+	r := resource.NewManagedKubeResource(ctx, eg.client, cluster, kube.AnnotatingPersister)
+
+	labelSelector := r.Labels.Selector(cluster.Spec().AdditionalLabels)
+	enabled, disabled := routeBuilders(cluster, labelSelector)
+
+	for _, b := range enabled {
+		gvk := b.builder.Placeholder().GetObjectKind().GroupVersionKind()
+		if !eg.available[gvk] {
+			return PermanentErr{Err: errors.Newf("%s is not served by the Kubernetes API, install the Gateway API CRDs", gvk)}
+		}
+
+		_, err := resource.Reconciler{
+			ManagedResource: r,
+			Builder:         b.builder,
+			Owner:           owner,
+			Scheme:          eg.scheme,
+		}.Reconcile()
+		if err != nil {
+			return errors.Wrapf(err, "failed to reconcile %s", b.builder.ResourceName())
+		}
+		cluster.SetTrue(b.condition)
+	}
+
+	for _, b := range disabled {
+		route := b.builder.Placeholder()
+		route.SetNamespace(cluster.Namespace())
+		// a route cannot exist if its kind is not served
+		if eg.available[route.GetObjectKind().GroupVersionKind()] {
+			if err := eg.client.Delete(ctx, route); kube.IgnoreNotFound(err) != nil {
+				msg := fmt.Sprintf("failed to delete [%s] route resource", route.GetName())
+				log.Error(err, msg)
+				return errors.Wrap(err, msg)
+			}
+		}
+		switch b.condition {
+		case "":
+			continue
+		case api.CrdbSQLRouteExposedCondition:
+			cluster.SetSQLHost("")
+		}
+		cluster.SetFalse(b.condition)
+	}
+
+	if err := eg.client.Status().Update(ctx, cluster.Unwrap()); err != nil {
+		msg := "failed to update RouteExposed condition in status"
+		log.Error(err, msg)
+		return errors.Wrap(err, msg)
+	}
+
+	log.Info("reconciled gateway routes")
+	return nil
+}
+
+type routeBuilder struct {
+	builder resource.Builder
+	// condition reports the route, it is empty for a route that may not exist
+	condition api.ClusterConditionType
+}
+
+// routeBuilders returns the builders of the configured routes, and of the routes that
+// were exposed and have to be removed.
+func routeBuilders(cluster *resource.Cluster, labels map[string]string) (enabled, disabled []routeBuilder) {
+	conditions := cluster.Status().Conditions
+
+	ui := routeBuilder{
+		builder:   resource.UIRouteBuilder{Cluster: cluster, Labels: labels},
+		condition: api.CrdbUIRouteExposedCondition,
+	}
+	if cluster.IsUIRouteEnabled() {
+		enabled = append(enabled, ui)
+	} else if condition.True(ui.condition, conditions) {
+		disabled = append(disabled, ui)
+	}
+
+	sql := routeBuilder{
+		builder:   resource.SQLRouteBuilder{Cluster: cluster, Labels: labels},
+		condition: api.CrdbSQLRouteExposedCondition,
+	}
+	if cluster.IsSQLRouteEnabled() {
+		enabled = append(enabled, sql)
+		// a route of the other kind is left behind when the kind changes
+		other := api.TCPRoute
+		if cluster.SQLRouteKind() == api.TCPRoute {
+			other = api.TLSRoute
+		}
+		disabled = append(disabled, routeBuilder{
+			builder: resource.SQLRouteBuilder{Cluster: cluster, Labels: labels, Kind: other},
+		})
+	} else if condition.True(sql.condition, conditions) {
+		disabled = append(disabled, sql)
+	}
+
+	return enabled, disabled
+}
@@ -34,18 +34,22 @@ import (
 )
 
 func newExposeIngress(scheme *runtime.Scheme, cl client.Client, config *rest.Config, clientset kubernetes.Interface) Actor {
+	gvk := resource.OpenShiftRouteGVK
 	return &exposeIngress{
 		action:    newAction(scheme, cl, nil, clientset),
 		config:    config,
 		v1Ingress: util.CheckIfAPIVersionKindAvailable(config, "networking.k8s.io/v1", "Ingress"),
+		routeAPI:  util.CheckIfAPIVersionKindAvailable(config, gvk.GroupVersion().String(), gvk.Kind),
 	}
 }
 
-// exposeIngress initializes and reconciles the ingress resource needed for exposing the CockroachDB cluster service
+// exposeIngress initializes and reconciles the ingress resource needed for exposing the CockroachDB cluster service.
+// When spec.ingress selects the Route mode, the services are exposed with OpenShift Routes instead.
 type exposeIngress struct {
 	action
 	config    *rest.Config
 	v1Ingress bool
+	routeAPI  bool
 }
 
 // GetActionType returns the  api.ExposeIngressAction value used to set the cluster status errors
@@ -61,24 +65,34 @@ func (ei exposeIngress) Act(ctx context.Context, cluster *resource.Cluster, log
 
 	labelSelector := r.Labels.Selector(cluster.Spec().AdditionalLabels)
 
-	ui := resource.UIIngressBuilder{Cluster: cluster, Labels: labelSelector, V1Ingress: ei.v1Ingress}
-	sql := resource.SQLIngressBuilder{Cluster: cluster, Labels: labelSelector, V1Ingress: ei.v1Ingress}
+	routeMode := routeMode(cluster)
+	selected, other := ei.ingressBuilders(cluster, labelSelector, routeMode)
 	uiIngressEnabled := cluster.IsUIIngressEnabled()
 	sqlIngressEnabled := cluster.IsSQLIngressEnabled()
 
 	if cluster.IsIngressNeeded() {
+		if routeMode && !ei.routeAPI {
+			return PermanentErr{Err: errors.New("the Route mode requires the route.openshift.io API")}
+		}
+		if routeMode && sqlIngressEnabled && !cluster.Spec().TLSEnabled {
+			return PermanentErr{Err: errors.New("the SQL route passes TLS through and requires tlsEnabled")}
+		}
+
 		var (
 			builders        []resource.Builder
+			previous        []resource.Builder
 			conditionsToSet []api.ClusterConditionType
 		)
 
 		if uiIngressEnabled {
-			builders = append(builders, ui)
+			builders = append(builders, selected.ui)
+			previous = append(previous, other.ui)
 			conditionsToSet = append(conditionsToSet, api.CrdbUIIngressExposedCondition)
 		}
 
 		if sqlIngressEnabled {
-			builders = append(builders, sql)
+			builders = append(builders, selected.sql)
+			previous = append(previous, other.sql)
 			conditionsToSet = append(conditionsToSet, api.CrdbSQLIngressExposedCondition)
 		}
 
@@ -95,6 +109,13 @@ func (ei exposeIngress) Act(ctx context.Context, cluster *resource.Cluster, log
 			cluster.SetTrue(conditionsToSet[i])
 		}
 
+		// remove the resources of the other mode after switching between ingresses and routes
+		for _, b := range previous {
+			if err := ei.deleteIfExists(ctx, cluster, b, log); err != nil {
+				return err
+			}
+		}
+
 		if err := ei.client.Status().Update(ctx, cluster.Unwrap()); err != nil {
 			msg := "failed to update IngressExposed condition in status"
 			log.Error(err, msg)
@@ -109,28 +130,26 @@ func (ei exposeIngress) Act(ctx context.Context, cluster *resource.Cluster, log
 	// delete the ingress resource
 	if !cluster.IsIngressNeeded() || (!uiIngressEnabled && uiIngressConditionTrue) || (!sqlIngressEnabled && sqlIngressConditionTrue) {
 		var (
-			builders        []resource.Builder
+			builders        [][]resource.Builder
 			conditionsToSet []api.ClusterConditionType
 		)
 
 		if !uiIngressEnabled && uiIngressConditionTrue {
-			builders = append(builders, ui)
+			builders = append(builders, []resource.Builder{selected.ui, other.ui})
 			conditionsToSet = append(conditionsToSet, api.CrdbUIIngressExposedCondition)
 		}
 
 		if !sqlIngressEnabled && sqlIngressConditionTrue {
-			builders = append(builders, sql)
+			builders = append(builders, []resource.Builder{selected.sql, other.sql})
 			conditionsToSet = append(conditionsToSet, api.CrdbSQLIngressExposedCondition)
 			cluster.SetSQLHost("")
 		}
 
-		for i, b := range builders {
-			ing := b.Placeholder()
-			ing.SetNamespace(cluster.Namespace())
-			if err := ei.client.Delete(ctx, ing); err != nil {
-				msg := fmt.Sprintf("failed to delete [%s] ingress resource", ing.GetName())
-				log.Error(err, msg)
-				return errors.Wrap(err, msg)
+		for i, bb := range builders {
+			for _, b := range bb {
+				if err := ei.deleteIfExists(ctx, cluster, b, log); err != nil {
+					return err
+				}
 			}
 			cluster.SetFalse(conditionsToSet[i])
 		}
@@ -145,3 +164,59 @@ func (ei exposeIngress) Act(ctx context.Context, cluster *resource.Cluster, log
 	log.Info("reconciled ingress resource")
 	return nil
 }
+
+type ingressBuilders struct {
+	ui, sql resource.Builder
+}
+
+// ingressBuilders returns the builders of the mode used by the cluster, and the builders of the other mode.
+func (ei exposeIngress) ingressBuilders(cluster *resource.Cluster, labels map[string]string, routeMode bool) (selected, other ingressBuilders) {
+	ingresses := ingressBuilders{
+		ui:  resource.UIIngressBuilder{Cluster: cluster, Labels: labels, V1Ingress: ei.v1Ingress},
+		sql: resource.SQLIngressBuilder{Cluster: cluster, Labels: labels, V1Ingress: ei.v1Ingress},
+	}
+	routes := ingressBuilders{
+		ui:  resource.UIOpenShiftRouteBuilder{Cluster: cluster, Labels: labels},
+		sql: resource.SQLOpenShiftRouteBuilder{Cluster: cluster, Labels: labels},
+	}
+
+	if routeMode {
+		return routes, ingresses
+	}
+	return ingresses, routes
+}
+
+// routeMode returns true if spec.ingress selects the OpenShift Routes to expose the services.
+func routeMode(cluster *resource.Cluster) bool {
+	return cluster.IsIngressNeeded() && cluster.Spec().Ingress.Mode == api.IngressModeRoute
+}
+
+// exists returns true if the resource of the builder exists. Routes cannot exist if their API is not served.
+func (ei exposeIngress) exists(r resource.ManagedResource, b resource.Builder) (bool, error) {
+	obj := b.Placeholder()
+	if obj.GetObjectKind().GroupVersionKind() == resource.OpenShiftRouteGVK && !ei.routeAPI {
+		return false, nil
+	}
+
+	if err := r.Fetch(obj); kube.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (ei exposeIngress) deleteIfExists(ctx context.Context, cluster *resource.Cluster, b resource.Builder, log logr.Logger) error {
+	obj := b.Placeholder()
+	if obj.GetObjectKind().GroupVersionKind() == resource.OpenShiftRouteGVK && !ei.routeAPI {
+		return nil
+	}
+
+	obj.SetNamespace(cluster.Namespace())
+	if err := ei.client.Delete(ctx, obj); kube.IgnoreNotFound(err) != nil {
+		msg := fmt.Sprintf("failed to delete [%s] ingress resource", obj.GetName())
+		log.Error(err, msg)
+		return errors.Wrap(err, msg)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap/zaptest"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestExposeIngressRouteModeRequiresRouteAPI(t *testing.T) {
+	scheme := testutil.InitScheme(t)
+	cluster := testutil.NewBuilder("cockroachdb").
+		Namespaced("default").
+		WithTLS().
+		WithIngress(&api.IngressConfig{
+			Mode: api.IngressModeRoute,
+			UI:   &api.Ingress{Host: "ui.test.com"},
+		}).Cluster()
+
+	exposeIngress := actor.NewExposeIngress(scheme, testutil.NewFakeClient(scheme), &rest.Config{},
+		fake.NewSimpleClientset())
+
+	err := exposeIngress.Act(context.Background(), cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+	require.ErrorAs(t, err, &actor.PermanentErr{})
+	require.EqualError(t, err, "the Route mode requires the route.openshift.io API")
+}
+
+func TestRouteModeIsOptIn(t *testing.T) {
+	builder := testutil.NewBuilder("cockroachdb").Namespaced("default").WithTLS()
+
+	// existing specs keep their Ingresses, on OpenShift too
+	cluster := builder.WithIngress(&api.IngressConfig{UI: &api.Ingress{Host: "ui.test.com"}}).Cluster()
+	require.False(t, actor.RouteMode(cluster))
+
+	cluster = builder.WithIngress(&api.IngressConfig{
+		Mode: api.IngressModeRoute,
+		UI:   &api.Ingress{Host: "ui.test.com"},
+	}).Cluster()
+	require.True(t, actor.RouteMode(cluster))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/update"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// manualFinalizationCheckInterval is how often the operator checks whether the user
+// finalized a major upgrade.
+const manualFinalizationCheckInterval = 5 * time.Minute
+
+func newFinalizeUpgrade(cl client.Client, config *rest.Config, clientset kubernetes.Interface) Actor {
+	return &finalizeUpgrade{
+		action: newAction(nil, cl, config, clientset),
+	}
+}
+
+// finalizeUpgrade finalizes a completed major upgrade, or tracks the finalization
+// done by the user
+type finalizeUpgrade struct {
+	action
+}
+
+// GetActionType returns api.FinalizeUpgradeAction action used to set the cluster status errors
+func (fu *finalizeUpgrade) GetActionType() api.ActionType {
+	return api.FinalizeUpgradeAction
+}
+
+// Act resets the preserve downgrade option once the auto-finalize delay has passed.
+// With manual finalization it only records when the user finalized the upgrade, and
+// when to check again otherwise.
+func (fu *finalizeUpgrade) Act(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+	// No need to connect to the database before the delay passed
+	if err := waitForAutoFinalizeDelay(cluster, log); err != nil {
+		return err
+	}
+
+	db, err := fu.openDatabase(ctx, cluster, log)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// The other actors run until the next check
+	err = finalizeCompletedUpgrade(ctx, cluster, db, log)
+	var requeueAfterErr RequeueAfterErr
+	if errors.As(err, &requeueAfterErr) {
+		cluster.SetUpgradeFinalizationCheckTime(time.Now().Add(requeueAfterErr.After))
+		return nil
+	}
+	return err
+}
+
+// waitForAutoFinalizeDelay returns a RequeueAfterErr until the auto-finalize delay of
+// the last completed upgrade has passed.
+func waitForAutoFinalizeDelay(cluster *resource.Cluster, log logr.Logger) error {
+	if cluster.UpgradeFinalizeMode() != api.UpgradeFinalizeAuto {
+		return nil
+	}
+
+	finalizeAt := cluster.UpgradeCompletionTime().Add(cluster.UpgradeAutoFinalizeDelay())
+	if wait := time.Until(finalizeAt); wait > 0 {
+		log.Info("waiting for the auto-finalize delay before finalizing the upgrade", "finalizeAt", finalizeAt)
+		return RequeueAfterErr{Err: errors.New("waiting for the auto-finalize delay"), After: wait}
+	}
+	return nil
+}
+
+// finalizeCompletedUpgrade finalizes the last completed major upgrade when it is due. It
+// returns a RequeueAfterErr while the upgrade waits for the delay or for the user.
+func finalizeCompletedUpgrade(ctx context.Context, cluster *resource.Cluster, db *sql.DB, log logr.Logger) error {
+	if err := waitForAutoFinalizeDelay(cluster, log); err != nil {
+		return err
+	}
+	auto := cluster.UpgradeFinalizeMode() == api.UpgradeFinalizeAuto
+
+	preserved, err := update.PreserveDowngradeVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if preserved != "" {
+		if !auto {
+			log.V(DEBUGLEVEL).Info("waiting for the upgrade to be finalized manually", "preserveDowngradeVersion", preserved)
+			return RequeueAfterErr{
+				Err:   errors.Newf("waiting for the upgrade to be finalized manually, %s is preserved", preserved),
+				After: manualFinalizationCheckInterval,
+			}
+		}
+		if err := update.FinalizeUpgrade(ctx, db, log); err != nil {
+			return err
+		}
+	}
+
+	cluster.SetUpgradeFinalization("")
+	log.Info("upgrade finalized")
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/zapr"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFinalizeUpgradeWaitsForDelay(t *testing.T) {
+	cluster := resource.NewCluster(&api.CrdbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cockroachdb", Namespace: "default"},
+		Spec: api.CrdbClusterSpec{
+			Upgrade: &api.UpgradeSpec{
+				Finalize:          api.UpgradeFinalizeAuto,
+				AutoFinalizeDelay: &metav1.Duration{Duration: time.Hour},
+			},
+		},
+	})
+	cluster.SetUpgradeFinalization("24.1")
+
+	finalize := actor.NewFinalizeUpgrade(nil, nil, nil)
+	err := finalize.Act(context.Background(), &cluster, zapr.NewLogger(zaptest.NewLogger(t)))
+
+	var requeueErr actor.RequeueAfterErr
+	require.True(t, errors.As(err, &requeueErr))
+	require.Greater(t, requeueErr.After, 59*time.Minute)
+	require.LessOrEqual(t, requeueErr.After, time.Hour)
+	require.False(t, cluster.True(api.UpgradeFinalizedCondition))
+	require.Equal(t, "24.1", cluster.Status().PreserveDowngradeVersion)
+}
@@ -17,12 +17,14 @@ limitations under the License.
 package actor
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"time"
 
 	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
@@ -56,6 +58,15 @@ type generateCert struct {
 
 	CertsDir string
 	CAKey    string
+	// CACert is the certificate of the CA provided in spec.certificates.caSecret, nil for the CA
+	// generated by the operator
+	CACert []byte
+	// Renewed is true if the certificates were reissued or their CA changed, the nodes are restarted
+	// to load them
+	Renewed bool
+	// CARotation is true if the CA of the secrets was replaced by a bundle of the new and the previous
+	// CA. The certificates are issued from the new CA once the nodes trust it.
+	CARotation bool
 }
 
 // GetActionType returns api.RequestCertAction action used to set the cluster status errors
@@ -76,6 +87,9 @@ func (rc *generateCert) Act(ctx context.Context, cluster *resource.Cluster, log
 	certsDir, cleanup := util.CreateTempDir("certsDir")
 	defer cleanup()
 	rc.CertsDir = certsDir
+	rc.CACert = nil
+	rc.Renewed = false
+	rc.CARotation = false
 
 	caDir, cleanupCADir := util.CreateTempDir("caDir")
 	defer cleanupCADir()
@@ -107,9 +121,27 @@ func (rc *generateCert) Act(ctx context.Context, cluster *resource.Cluster, log
 		return errors.Wrap(err, msg)
 	}
 
+	// the certificates of the applications are issued once the nodes trust the CA
+	if !rc.CARotation {
+		if err := rc.issueClientCertificates(ctx, log, cluster); err != nil {
+			msg := "error issuing the client certificates of SQL users"
+			log.Error(err, msg)
+			return errors.Wrap(err, msg)
+		}
+	}
+
 	var restartRequired bool
 	sqlIngressConditionTrue := condition.True(api.CrdbSQLIngressExposedCondition, cluster.Status().Conditions)
-	if cluster.IsSQLIngressEnabled() && sqlIngressConditionTrue {
+	sqlRouteConditionTrue := condition.True(api.CrdbSQLRouteExposedCondition, cluster.Status().Conditions)
+	if cluster.SQLExternalHost() != "" && (sqlIngressConditionTrue || sqlRouteConditionTrue) {
+		restartRequired = true
+	}
+	// the node certificate was regenerated for a new load balancer address
+	if loadBalancerHost(cluster) != "" && condition.True(api.CrdbInitializedCondition, cluster.Status().Conditions) {
+		restartRequired = true
+	}
+	// the certificates were renewed, or the nodes have to trust a new CA
+	if rc.Renewed && condition.True(api.CrdbInitializedCondition, cluster.Status().Conditions) {
 		restartRequired = true
 	}
 
@@ -156,9 +188,15 @@ func (rc *generateCert) Act(ctx context.Context, cluster *resource.Cluster, log
 		}
 		refreshedCluster := resource.NewCluster(newcr)
 		refreshedCluster.Fetcher = fetcher
-		refreshedCluster.SetTrue(api.CertificateGenerated)
-		if refreshedCluster.IsSQLIngressEnabled() {
-			refreshedCluster.SetSQLHost(cluster.Spec().Ingress.SQL.Host)
+		// the certificates are issued from the new CA after the restart
+		if rc.CARotation {
+			refreshedCluster.SetFalse(api.CertificateGenerated)
+		} else {
+			refreshedCluster.SetTrue(api.CertificateGenerated)
+			refreshedCluster.SetCertificatesStatus(cluster.CertificatesStatus())
+		}
+		if host := cluster.SQLExternalHost(); host != "" {
+			refreshedCluster.SetSQLHost(host)
 		}
 
 		crdbobj := refreshedCluster.Unwrap()
@@ -180,6 +218,10 @@ func (rc *generateCert) Act(ctx context.Context, cluster *resource.Cluster, log
 }
 
 func (rc *generateCert) generateCA(ctx context.Context, log logr.Logger, cluster *resource.Cluster) error {
+	if cluster.IsCAProvided() {
+		return rc.loadProvidedCA(ctx, log, cluster)
+	}
+
 	log.V(DEBUGLEVEL).Info("generating CA")
 	// load the secret.  If it exists don't update the cert
 	secret, err := resource.LoadTLSSecret(cluster.CASecretName(),
@@ -227,6 +269,29 @@ func (rc *generateCert) generateCA(ctx context.Context, log logr.Logger, cluster
 	return nil
 }
 
+// loadProvidedCA writes the certificate and the key of the CA provided in spec.certificates.caSecret,
+// the certificates are issued from it.
+func (rc *generateCert) loadProvidedCA(ctx context.Context, log logr.Logger, cluster *resource.Cluster) error {
+	log.V(DEBUGLEVEL).Info("loading the provided CA", "secret", cluster.CASecretName())
+	secret, err := resource.LoadTLSSecret(cluster.CASecretName(),
+		resource.NewKubeResource(ctx, rc.client, cluster.Namespace(), kube.DefaultPersister))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get CA secret %s", cluster.CASecretName())
+	}
+	if !secret.ReadyCA() || len(secret.CA()) == 0 {
+		return errors.Newf("CA secret %s must have a ca.crt and a ca.key", cluster.CASecretName())
+	}
+
+	if err := os.WriteFile(rc.CAKey, secret.CAKey(), 0600); err != nil {
+		return errors.Wrap(err, "failed to write CA key")
+	}
+	if err := os.WriteFile(filepath.Join(rc.CertsDir, "ca.crt"), secret.CA(), 0644); err != nil {
+		return errors.Wrap(err, "failed to write CA cert")
+	}
+	rc.CACert = secret.CA()
+	return nil
+}
+
 // TODO we have an edge case that exists that the actor is not handling properly
 // If any errors occurs and we have save secrets we may need to delete the secrets
 // We can get into a race condition where the Node certifcate was created, but the Client certificate was not.
@@ -249,11 +314,35 @@ func (rc *generateCert) generateNodeCert(ctx context.Context, log logr.Logger, c
 		SQLHost        string
 	)
 
-	if cluster.IsSQLIngressEnabled() && cluster.Status().SQLHost != cluster.Spec().Ingress.SQL.Host {
-		SQLHost = cluster.Spec().Ingress.SQL.Host
+	if host := cluster.SQLExternalHost(); host != "" && cluster.Status().SQLHost != host {
+		SQLHost = host
 		regenerateCert = true
 	}
 
+	if host := loadBalancerHost(cluster); host != "" {
+		SQLHost = host
+		if secret.Ready() && !certificateCoversHost(secret.Key(), host) {
+			regenerateCert = true
+		}
+	}
+
+	if secret.Ready() {
+		renew, err := rc.renewCert(ctx, log, cluster, secret)
+		if err != nil {
+			return "", err
+		}
+		if rc.CARotation {
+			return rc.getCertificateExpirationDate(ctx, log, secret.Key())
+		}
+		if renew {
+			regenerateCert = true
+		} else if nodeCertificateChanged(cluster) {
+			log.V(DEBUGLEVEL).Info("reissuing node certificate because of change in spec.certificates")
+			regenerateCert = true
+			rc.Renewed = true
+		}
+	}
+
 	// if the secret is ready then don't update the secret
 	// the Actor should have already generated the secret
 	if secret.Ready() {
@@ -280,6 +369,7 @@ func (rc *generateCert) generateNodeCert(ctx context.Context, log logr.Logger, c
 		fmt.Sprintf("*.%s.%s", cluster.DiscoveryServiceName(), cluster.Namespace()),
 		fmt.Sprintf("*.%s.%s.%s", cluster.DiscoveryServiceName(), cluster.Namespace(), cluster.Domain()),
 	}
+	hosts = append(hosts, cluster.CertificateSANs()...)
 
 	if SQLHost != "" {
 		hosts = append(hosts, SQLHost)
@@ -344,8 +434,14 @@ func (rc *generateCert) generateClientCert(ctx context.Context, log logr.Logger,
 	// the Actor should have already generated the secret
 	//but we should read the expiration date
 	if secret.Ready() {
-		log.V(DEBUGLEVEL).Info("not updating client certificate")
-		return nil
+		renew, err := rc.renewCert(ctx, log, cluster, secret)
+		if err != nil || !renew {
+			log.V(DEBUGLEVEL).Info("not updating client certificate")
+			return err
+		}
+		if err = os.WriteFile(filepath.Join(rc.CertsDir, "ca.crt"), secret.CA(), 0644); err != nil {
+			return errors.Wrap(err, "failed to write CA cert")
+		}
 	}
 
 	// Create the user for the certificate
@@ -395,6 +491,111 @@ func (rc *generateCert) generateClientCert(ctx context.Context, log logr.Logger,
 	return nil
 }
 
+// nodeCertificateChanged returns true if the CA or the additional names of the node certificate
+// changed since it was issued.
+func nodeCertificateChanged(cluster *resource.Cluster) bool {
+	issued, wanted := cluster.Status().Certificates, cluster.CertificatesStatus()
+	if issued == nil {
+		issued = &api.CertificatesStatus{}
+	}
+	if wanted == nil {
+		wanted = &api.CertificatesStatus{}
+	}
+	return issued.CASecret != wanted.CASecret || !reflect.DeepEqual(issued.SANs, wanted.SANs)
+}
+
+// renewCert returns true if the certificate of a secret has to be reissued, because it expires
+// within the renewal delay or was not issued from the provided CA. If the provided CA changed, the
+// new CA is first added to the CA of the secret, for the nodes to trust both CAs until all the
+// certificates are issued from the new one.
+func (rc *generateCert) renewCert(ctx context.Context, log logr.Logger, cluster *resource.Cluster,
+	secret *resource.TLSSecret) (bool, error) {
+	if rc.CACert != nil && !bytes.HasPrefix(secret.CA(), rc.CACert) {
+		log.Info("adding the new CA to the certificate secret")
+		if err := secret.UpdateCertAndCA(secret.Key(), caBundle(rc.CACert, secret.CA()), log); err != nil {
+			return false, errors.Wrap(err, "failed to update the CA of the certificate secret")
+		}
+		rc.CARotation = true
+		rc.Renewed = true
+		return false, nil
+	}
+
+	renew := certificateExpiresWithin(secret.Key(), cluster.CertificateRenewBefore())
+	if rc.CACert != nil && !certificateSignedBy(secret.Key(), rc.CACert) {
+		renew = true
+	}
+	if renew {
+		log.Info("reissuing the certificate")
+		rc.Renewed = true
+		if err := os.WriteFile(filepath.Join(rc.CertsDir, "ca.crt"), secret.CA(), 0644); err != nil {
+			return false, errors.Wrap(err, "failed to write CA cert")
+		}
+	}
+	return renew, nil
+}
+
+// caBundle returns the PEM encoded CA followed by the first certificate of the previous CA bundle,
+// the CA the current certificates are issued from.
+func caBundle(ca, previous []byte) []byte {
+	block, _ := pem.Decode(previous)
+	if block == nil {
+		return ca
+	}
+	bundle := append([]byte{}, ca...)
+	if len(bundle) > 0 && bundle[len(bundle)-1] != '\n' {
+		bundle = append(bundle, '\n')
+	}
+	return append(bundle, pem.EncodeToMemory(block)...)
+}
+
+// parseCertificate returns the first certificate of PEM encoded data, nil if there is none.
+func parseCertificate(pemCert []byte) *x509.Certificate {
+	block, _ := pem.Decode(pemCert)
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return cert
+}
+
+// certificateSignedBy returns true if the PEM encoded certificate is signed by the first
+// certificate of the PEM encoded CA.
+func certificateSignedBy(pemCert, pemCA []byte) bool {
+	cert, ca := parseCertificate(pemCert), parseCertificate(pemCA)
+	return cert != nil && ca != nil && cert.CheckSignatureFrom(ca) == nil
+}
+
+// certificateExpiresWithin returns true if the PEM encoded certificate expires within the duration.
+func certificateExpiresWithin(pemCert []byte, d time.Duration) bool {
+	cert := parseCertificate(pemCert)
+	return cert != nil && time.Until(cert.NotAfter) < d
+}
+
+// loadBalancerHost returns the address of the load balancer exposing SQL, which is
+// recorded in the status when neither a SQL ingress nor a TLSRoute is used.
+func loadBalancerHost(cluster *resource.Cluster) string {
+	if cluster.SQLExternalHost() != "" {
+		return ""
+	}
+	return cluster.Status().SQLHost
+}
+
+// certificateCoversHost returns true if the PEM encoded certificate is valid for the host.
+func certificateCoversHost(pemCert []byte, host string) bool {
+	block, _ := pem.Decode(pemCert)
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	return cert.VerifyHostname(host) == nil
+}
+
 func (rc *generateCert) getCertificateExpirationDate(ctx context.Context, log logr.Logger, pemCert []byte) (string, error) {
 	log.V(DEBUGLEVEL).Info("getExpirationDate from cert")
 	block, _ := pem.Decode(pemCert)
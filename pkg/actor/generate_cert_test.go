@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/security"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCABundle(t *testing.T) {
+	previous, err := security.NewCACertificate()
+	require.NoError(t, err)
+	ca, err := security.NewCACertificate()
+	require.NoError(t, err)
+
+	require.Equal(t, ca.Certificate(), actor.CABundle(ca.Certificate(), nil))
+
+	bundle := actor.CABundle(ca.Certificate(), previous.Certificate())
+	require.True(t, bytes.HasPrefix(bundle, ca.Certificate()))
+	require.True(t, bytes.HasSuffix(bundle, previous.Certificate()))
+
+	// only the CA the current certificates are issued from is kept
+	rotated := actor.CABundle(previous.Certificate(), bundle)
+	require.Equal(t, append(append([]byte{}, previous.Certificate()...), ca.Certificate()...), rotated)
+}
+
+func TestCertificateRenewal(t *testing.T) {
+	ca, err := security.NewCACertificate(security.ExpOption(90 * 24 * time.Hour))
+	require.NoError(t, err)
+	other, err := security.NewCACertificate()
+	require.NoError(t, err)
+	node, err := security.NewCertificate(ca, security.DNSNamesOption("cockroachdb-public"))
+	require.NoError(t, err)
+
+	require.True(t, actor.CertificateSignedBy(node.Certificate(), ca.Certificate()))
+	require.False(t, actor.CertificateSignedBy(node.Certificate(), other.Certificate()))
+	require.False(t, actor.CertificateSignedBy(nil, ca.Certificate()))
+
+	require.False(t, actor.CertificateExpiresWithin(node.Certificate(), 30*24*time.Hour))
+	require.True(t, actor.CertificateExpiresWithin(node.Certificate(), 120*24*time.Hour))
+}
+
+func TestConnectionSecretData(t *testing.T) {
+	cr := testutil.NewBuilder("cockroachdb").Namespaced("db").WithTLS().Cr()
+	cr.Spec.Certificates = &api.CertificatesConfig{Clients: []api.ClientCertificate{{User: "app", Namespace: "app"}}}
+	cluster := resource.NewCluster(cr)
+	clients := cluster.ClientCertificates()
+	require.Equal(t, []api.ClientCertificate{{
+		User:        "app",
+		Namespace:   "app",
+		SecretName:  "cockroachdb-client-app",
+		Database:    "defaultdb",
+		BindingRoot: "/bindings",
+	}}, clients)
+
+	data := actor.ConnectionSecretData(&cluster, clients[0])
+	require.Equal(t, map[string]string{
+		"type":     "postgresql",
+		"provider": "cockroachdb",
+		"host":     "cockroachdb-public.db.svc.cluster.local",
+		"port":     "26257",
+		"username": "app",
+		"database": "defaultdb",
+		"sslmode":  "verify-full",
+		"uri": "postgresql://app@cockroachdb-public.db.svc.cluster.local:26257/defaultdb?" +
+			"sslcert=%2Fbindings%2Fcockroachdb-client-app%2Ftls.crt&sslkey=%2Fbindings%2Fcockroachdb-client-app%2Ftls.key&" +
+			"sslmode=verify-full&sslrootcert=%2Fbindings%2Fcockroachdb-client-app%2Fca.crt",
+	}, stringData(data))
+
+	// the SQL host of the cluster is used when it is exposed
+	cluster.SetSQLHost("sql.example.com")
+	data = actor.ConnectionSecretData(&cluster, clients[0])
+	require.Equal(t, "sql.example.com", string(data["host"]))
+}
+
+func TestClientCertificateSecrets(t *testing.T) {
+	cluster := resource.NewCluster(testutil.NewBuilder("cockroachdb").Namespaced("db").WithTLS().Cr())
+
+	// only the Secrets labelled with the cluster are owned
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app"}}
+	require.False(t, actor.ClientSecretOwned(&cluster, secret))
+	secret.Labels = map[string]string{"crdb.io/cluster": "cockroachdb", "crdb.io/cluster-namespace": "other"}
+	require.False(t, actor.ClientSecretOwned(&cluster, secret))
+	secret.Labels["crdb.io/cluster-namespace"] = "db"
+	require.True(t, actor.ClientSecretOwned(&cluster, secret))
+
+	// the other namespaces opt in to the Secrets of the clusters of a namespace
+	cl := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app", Labels: map[string]string{"crdb.io/client-certificates-from": "db"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	).Build()
+	ctx := context.Background()
+	require.NoError(t, actor.ClientSecretNamespaceAllowed(ctx, cl, &cluster, "db"))
+	require.NoError(t, actor.ClientSecretNamespaceAllowed(ctx, cl, &cluster, "app"))
+	require.EqualError(t, actor.ClientSecretNamespaceAllowed(ctx, cl, &cluster, "other"),
+		"namespace other does not accept the client certificates of the clusters of namespace db, "+
+			"label it with crdb.io/client-certificates-from=db")
+	require.Error(t, actor.ClientSecretNamespaceAllowed(ctx, cl, &cluster, "missing"))
+}
+
+func stringData(data map[string][]byte) map[string]string {
+	m := map[string]string{}
+	for k, v := range data {
+		m[k] = string(v)
+	}
+	return m
+}
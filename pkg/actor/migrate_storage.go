@@ -0,0 +1,309 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/clustersql"
+	"github.com/cockroachdb/cockroach-operator/pkg/healthchecker"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/scale"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// storageMigrationStepDelay is the delay before the next step of a storage class migration, once
+// the progress of the migration is saved in the status.
+const storageMigrationStepDelay = 5 * time.Second
+
+// newMigrateStorage creates and returns a new migrateStorage struct
+func newMigrateStorage(scheme *runtime.Scheme, cl client.Client, config *rest.Config, clientset kubernetes.Interface,
+	recorder record.EventRecorder) Actor {
+	return &migrateStorage{
+		action:   newAction(scheme, cl, config, clientset),
+		recorder: recorder,
+	}
+}
+
+// migrateStorage migrates the persistent volumes of the data store to a new storage class by
+// replacing the nodes one at a time
+type migrateStorage struct {
+	action
+
+	recorder record.EventRecorder
+}
+
+// GetActionType returns api.MigrateStorageAction action used to set the cluster status errors
+func (ms *migrateStorage) GetActionType() api.ActionType {
+	return api.MigrateStorageAction
+}
+
+// Act runs the next step of the migration of the data store to the storage class of the cluster
+// and saves the progress in the status. The volume claim templates are immutable, so the
+// statefulset is first recreated without deleting its pods, and an additional node is added. Then
+// each node is decommissioned and its volumes and pod are deleted, the statefulset recreates them
+// on the new storage class. The additional node is decommissioned last.
+func (ms *migrateStorage) Act(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+	key := kubetypes.NamespacedName{
+		Namespace: cluster.Namespace(),
+		Name:      cluster.StatefulSetName(),
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := ms.client.Get(ctx, key, sts); err != nil {
+		return errors.Wrap(err, "failed to fetch statefulset")
+	}
+	if statefulSetIsUpdating(sts) {
+		return NotReadyErr{Err: errors.New("storage migration statefulset is updating, waiting for the update to finish")}
+	}
+
+	// The default storage class is assigned to the PVCs when they are created, so their storage
+	// class never matches an empty target
+	target := cluster.StorageClassName()
+	if target == "" {
+		return PermanentErr{Err: errors.New("the storage class migration requires dataStore.pvc.spec.storageClassName")}
+	}
+	status := cluster.Status().StorageMigration
+	if status == nil {
+		status = &api.StorageMigrationStatus{
+			StorageClass: target,
+			Node:         cluster.Spec().Nodes - 1,
+			StartTime:    metav1.Now(),
+		}
+		log.Info("starting the storage class migration", "storageClass", target)
+		ms.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "StorageMigrationStarted",
+			"Migrating the data store to the storage class %q", target)
+	}
+	defer func() {
+		cluster.SetStorageMigrationStatus(status)
+	}()
+
+	if template := resource.DataStoreClaimTemplate(sts); template != nil && claimStorageClass(template) != target {
+		log.Info("recreating the statefulset with the new storage class", "storageClass", target)
+		if err := ms.updateSts(ctx, sts, cluster, log); err != nil {
+			return errors.Wrapf(err, "updating statefulset %s.%s", cluster.Namespace(), cluster.StatefulSetName())
+		}
+		return RequeueAfterErr{Err: errors.New("recreated the statefulset"), After: storageMigrationStepDelay}
+	}
+
+	// Skip the nodes whose volumes are already on the new storage class
+	pvcs := ms.clientset.CoreV1().PersistentVolumeClaims(cluster.Namespace())
+	for status.Phase == "" && status.Node >= 0 {
+		pvc, err := pvcs.Get(ctx, claimName(resource.DataDirName, sts.Name, status.Node), metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch the data store PVC of node %d", status.Node)
+		}
+		if claimStorageClass(pvc) != target {
+			break
+		}
+		status.Node--
+	}
+
+	if status.Node < 0 {
+		if err := ms.removeAdditionalNode(ctx, cluster, sts, log); err != nil {
+			return err
+		}
+		log.Info("storage class migration completed", "storageClass", target)
+		ms.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "StorageMigrationCompleted",
+			"Migrated the data store to the storage class %q", target)
+		status = nil
+		return nil
+	}
+
+	// The additional node keeps the ranges fully replicated while a node is replaced
+	nodes := cluster.Spec().Nodes
+	if *sts.Spec.Replicas <= nodes {
+		log.Info("adding a node for the storage class migration")
+		if err := ms.statefulSet(cluster, sts).SetReplicas(ctx, uint(nodes+1)); err != nil {
+			return err
+		}
+		return RequeueAfterErr{Err: errors.New("added a node for the migration"), After: storageMigrationStepDelay}
+	}
+
+	if status.Phase != api.StorageMigrationReplacing {
+		if err := scale.IsStatefulSetReadyToServe(ctx, ms.clientset, cluster.Namespace(), sts.Name, nodes+1); err != nil {
+			return NotReadyErr{Err: err}
+		}
+
+		status.Phase = api.StorageMigrationDecommissioning
+		drainer, err := ms.drainer(ctx, cluster, sts, log)
+		if err != nil {
+			return err
+		}
+		log.Info("decommissioning the node to replace", "node", status.Node)
+		if err := drainer.Decommission(ctx, uint(status.Node), *cluster.Spec().GRPCPort); err != nil {
+			return errors.Wrapf(err, "failed to decommission node %d", status.Node)
+		}
+		status.Phase = api.StorageMigrationReplacing
+		return RequeueAfterErr{Err: errors.Newf("decommissioned node %d", status.Node), After: storageMigrationStepDelay}
+	}
+
+	if err := replaceVolumes(ctx, ms.clientset, sts, status.Node, target, log); err != nil {
+		return err
+	}
+	if err := scale.IsStatefulSetReadyToServe(ctx, ms.clientset, cluster.Namespace(), sts.Name, nodes+1); err != nil {
+		return NotReadyErr{Err: err}
+	}
+
+	healthChecker := healthchecker.NewHealthChecker(cluster, ms.clientset, ms.config)
+	healthChecker.SetClient(ms.client)
+	if healthChecker.NeedsDatabase() {
+		db, err := ms.openDatabase(ctx, cluster, log)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		healthChecker.SetDatabase(db)
+	}
+	if err := healthChecker.Probe(ctx, log, "storage migration", int(status.Node)); err != nil {
+		return err
+	}
+
+	podName := fmt.Sprintf("%s-%d", sts.Name, status.Node)
+	log.Info("replaced the node on the new storage class", "pod", podName)
+	ms.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "NodeReplaced",
+		"Replaced the node of pod %s on the storage class %q", podName, target)
+	status.Node--
+	status.Phase = ""
+	return RequeueAfterErr{Err: errors.Newf("replaced the node of pod %s", podName), After: storageMigrationStepDelay}
+}
+
+// replaceVolumes deletes the volumes and the pod of a decommissioned node, for the statefulset to
+// recreate them on the new storage class. It returns a NotReadyErr until the data store volume of
+// the node is on the new storage class.
+func replaceVolumes(ctx context.Context, clientset kubernetes.Interface, sts *appsv1.StatefulSet, ordinal int32,
+	storageClass string, log logr.Logger) error {
+	pvcs := clientset.CoreV1().PersistentVolumeClaims(sts.Namespace)
+	podName := fmt.Sprintf("%s-%d", sts.Name, ordinal)
+
+	pvc, err := pvcs.Get(ctx, claimName(resource.DataDirName, sts.Name, ordinal), metav1.GetOptions{})
+	if kube.IsNotFound(err) {
+		// The pod was recreated before its volumes were deleted and waits for volumes that no
+		// longer exist. Deleting it again lets the statefulset create the volumes.
+		if err := deletePod(ctx, clientset, sts.Namespace, podName); err != nil {
+			return err
+		}
+		return NotReadyErr{Err: errors.Newf("waiting for pod %s to be recreated", podName)}
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to fetch the data store PVC of pod %s", podName)
+	}
+	if claimStorageClass(pvc) == storageClass {
+		return nil
+	}
+
+	if pvc.DeletionTimestamp == nil {
+		log.Info("deleting the volumes of the decommissioned node", "pod", podName)
+		for _, template := range sts.Spec.VolumeClaimTemplates {
+			name := claimName(template.Name, sts.Name, ordinal)
+			if err := pvcs.Delete(ctx, name, metav1.DeleteOptions{}); kube.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, "failed to delete PVC %s", name)
+			}
+		}
+	}
+	// The volumes are only deleted once the pod using them is gone
+	if err := deletePod(ctx, clientset, sts.Namespace, podName); err != nil {
+		return err
+	}
+	return NotReadyErr{Err: errors.Newf("waiting for the volumes of pod %s to be deleted", podName)}
+}
+
+// removeAdditionalNode decommissions the node added for the migration and prunes its volumes, a
+// decommissioned node cannot rejoin the cluster.
+func (ms *migrateStorage) removeAdditionalNode(ctx context.Context, cluster *resource.Cluster, sts *appsv1.StatefulSet,
+	log logr.Logger) error {
+	nodes := cluster.Spec().Nodes
+	if *sts.Spec.Replicas <= nodes {
+		return nil
+	}
+
+	drainer, err := ms.drainer(ctx, cluster, sts, log)
+	if err != nil {
+		return err
+	}
+	scaler := scale.Scaler{
+		Logger:    log,
+		CRDB:      ms.statefulSet(cluster, sts),
+		Drainer:   drainer,
+		PVCPruner: newPVCPruner(ms.config, ms.clientset, ms.client, ms.recorder, cluster, sts.Name, log),
+	}
+	err = scaler.EnsureScale(ctx, uint(nodes), *cluster.Spec().GRPCPort, true)
+	// the node is removed, the decommission action prunes its volumes once they can be deleted
+	var retained scale.PVCsRetainedErr
+	if errors.As(err, &retained) {
+		retainPVCs(cluster, retained, ms.recorder, log)
+		return nil
+	}
+	return errors.Wrap(err, "failed to remove the node added for the migration")
+}
+
+// drainer returns the drainer used to decommission the nodes. Like the decommission action, it
+// fails if no range moves for three times the range move duration.
+func (ms *migrateStorage) drainer(ctx context.Context, cluster *resource.Cluster, sts *appsv1.StatefulSet,
+	log logr.Logger) (scale.Drainer, error) {
+	db, err := ms.openDatabase(ctx, cluster, log)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	timeout, err := clustersql.RangeMoveDuration(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get range move duration")
+	}
+	return scale.NewCockroachNodeDrainer(log, cluster.Namespace(), sts.Name, ms.config, ms.clientset,
+		cluster.Spec().TLSEnabled, 3*timeout), nil
+}
+
+func (ms *migrateStorage) statefulSet(cluster *resource.Cluster, sts *appsv1.StatefulSet) *scale.CockroachStatefulSet {
+	return &scale.CockroachStatefulSet{
+		ClientSet: ms.clientset,
+		Namespace: cluster.Namespace(),
+		Name:      sts.Name,
+	}
+}
+
+func deletePod(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	err := clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	return errors.Wrapf(kube.IgnoreNotFound(err), "failed to delete pod %s", name)
+}
+
+// claimName returns the name of the PVC created by the statefulset from a volume claim template
+// for the pod with the given ordinal.
+func claimName(template, sts string, ordinal int32) string {
+	return fmt.Sprintf("%s-%s-%d", template, sts, ordinal)
+}
+
+// claimStorageClass returns the storage class of a PVC, empty for the default storage class.
+func claimStorageClass(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName == nil {
+		return ""
+	}
+	return *pvc.Spec.StorageClassName
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/go-logr/zapr"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReplaceVolumes(t *testing.T) {
+	ctx := context.Background()
+	log := zapr.NewLogger(zaptest.NewLogger(t))
+	standard, ssd := "standard", "ssd"
+	claim := func(name string, storageClass *string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: storageClass},
+		}
+	}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "crdb-1", Namespace: "default"}}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "crdb", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+			{ObjectMeta: metav1.ObjectMeta{Name: "datadir"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "store-1"}},
+		}},
+	}
+
+	// The volumes and the pod of the decommissioned node are deleted
+	clientset := fake.NewSimpleClientset([]runtime.Object{
+		claim("datadir-crdb-0", &standard),
+		claim("datadir-crdb-1", &standard),
+		claim("store-1-crdb-1", &standard),
+		pod,
+	}...)
+	var notReadyErr actor.NotReadyErr
+	err := actor.ReplaceVolumes(ctx, clientset, sts, 1, ssd, log)
+	require.ErrorAs(t, err, &notReadyErr)
+
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, pvcs.Items, 1)
+	require.Equal(t, "datadir-crdb-0", pvcs.Items[0].Name)
+	pods, err := clientset.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, pods.Items)
+
+	// A pod recreated before its volumes were deleted is deleted again
+	_, err = clientset.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+	err = actor.ReplaceVolumes(ctx, clientset, sts, 1, ssd, log)
+	require.ErrorAs(t, err, &notReadyErr)
+	pods, err = clientset.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, pods.Items)
+
+	// The node is replaced once its data store is on the new storage class
+	_, err = clientset.CoreV1().PersistentVolumeClaims("default").Create(ctx, claim("datadir-crdb-1", &ssd), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, actor.ReplaceVolumes(ctx, clientset, sts, 1, ssd, log))
+}
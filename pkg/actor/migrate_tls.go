@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"time"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/healthchecker"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/scale"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tlsMigrationStepDelay is the delay before the pods restarted by a TLS migration are checked.
+const tlsMigrationStepDelay = 10 * time.Second
+
+// newMigrateTLS creates and returns a new migrateTLS struct
+func newMigrateTLS(scheme *runtime.Scheme, cl client.Client, config *rest.Config, kd kube.KubernetesDistribution,
+	clientset kubernetes.Interface, recorder record.EventRecorder) Actor {
+	return &migrateTLS{
+		action:   newAction(scheme, cl, config, clientset),
+		kd:       kd,
+		recorder: recorder,
+	}
+}
+
+// migrateTLS migrates a running cluster to or from TLS. Insecure and secure nodes cannot be part
+// of the same cluster, so all the nodes are restarted at once with the new security mode.
+type migrateTLS struct {
+	action
+
+	kd       kube.KubernetesDistribution
+	recorder record.EventRecorder
+}
+
+// GetActionType returns api.MigrateTLSAction action used to set the cluster status errors
+func (mt *migrateTLS) GetActionType() api.ActionType {
+	return api.MigrateTLSAction
+}
+
+// Act runs the next step of the migration of the cluster to the security mode of the spec and
+// saves the progress in the status. The statefulset is updated with the new security mode, then
+// every pod not running the new template is deleted and the statefulset recreates all of them at
+// once. The migration completes when all the nodes are ready and healthy.
+func (mt *migrateTLS) Act(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+	key := kubetypes.NamespacedName{
+		Namespace: cluster.Namespace(),
+		Name:      cluster.StatefulSetName(),
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := mt.client.Get(ctx, key, sts); err != nil {
+		return errors.Wrap(err, "failed to fetch statefulset")
+	}
+
+	target := cluster.Spec().TLSEnabled
+	status := cluster.Status().TLSMigration
+	if status == nil {
+		status = &api.TLSMigrationStatus{
+			TLSEnabled: target,
+			Phase:      api.TLSMigrationRestarting,
+			StartTime:  metav1.Now(),
+		}
+		log.Info("starting the TLS migration, the cluster is unavailable until all the nodes restart", "tlsEnabled", target)
+		mt.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "TLSMigrationStarted",
+			"Restarting all the nodes with tlsEnabled %t, the cluster is unavailable until they are ready", target)
+	}
+	defer func() {
+		cluster.SetTLSMigrationStatus(status)
+	}()
+
+	if deployed, _ := resource.StatefulSetTLSEnabled(sts); deployed != target {
+		log.Info("updating the statefulset with the new security mode", "tlsEnabled", target)
+		kubernetesDistro, err := mt.kd.Get(ctx, mt.clientset, log)
+		if err != nil {
+			return errors.Wrap(err, "failed to get Kubernetes distribution")
+		}
+		r := resource.NewManagedKubeResource(ctx, mt.client, cluster, kube.AnnotatingPersister)
+		_, err = resource.Reconciler{
+			ManagedResource: r,
+			Builder: resource.StatefulSetBuilder{
+				Cluster:   cluster,
+				Selector:  r.Labels.Selector(cluster.Spec().AdditionalLabels),
+				Telemetry: kube.TelemetryChannel(kubernetesDistro),
+			},
+			Owner:  cluster.Unwrap(),
+			Scheme: mt.scheme,
+		}.Reconcile()
+		if err != nil {
+			return errors.Wrapf(err, "updating statefulset %s.%s", cluster.Namespace(), cluster.StatefulSetName())
+		}
+		return RequeueAfterErr{Err: errors.New("updated the statefulset"), After: tlsMigrationStepDelay}
+	}
+
+	// A rolling update would never complete, the first restarted node cannot join the others
+	restarted, err := restartOutdatedPods(ctx, mt.clientset, sts, log)
+	if err != nil {
+		return err
+	}
+	if restarted {
+		return RequeueAfterErr{Err: errors.New("restarted all the nodes"), After: tlsMigrationStepDelay}
+	}
+	if err := scale.IsStatefulSetReadyToServe(ctx, mt.clientset, cluster.Namespace(), sts.Name, *sts.Spec.Replicas); err != nil {
+		return NotReadyErr{Err: err}
+	}
+
+	healthChecker := healthchecker.NewHealthChecker(cluster, mt.clientset, mt.config)
+	healthChecker.SetClient(mt.client)
+	if healthChecker.NeedsDatabase() {
+		db, err := mt.openDatabase(ctx, cluster, log)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		healthChecker.SetDatabase(db)
+	}
+	if err := healthChecker.Probe(ctx, log, "TLS migration", 0); err != nil {
+		return err
+	}
+
+	log.Info("TLS migration completed", "tlsEnabled", target)
+	mt.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "TLSMigrationCompleted",
+		"Restarted all the nodes with tlsEnabled %t", target)
+	status = nil
+
+	// the migration is done, the annotation is removed for the next one to be requested again
+	fetcher := resource.NewKubeFetcher(ctx, cluster.Namespace(), mt.client)
+	cr := resource.ClusterPlaceholder(cluster.Name())
+	if err := fetcher.Fetch(cr); err != nil {
+		return errors.Wrap(err, "failed to retrieve CrdbCluster resource on TLS migration action")
+	}
+	refreshedCluster := resource.NewCluster(cr)
+	refreshedCluster.DeleteTLSMigrationAnnotation()
+	if err := mt.client.Update(ctx, refreshedCluster.Unwrap()); err != nil {
+		log.Error(err, "failed removing the TLS migration annotation")
+	}
+	return nil
+}
+
+// restartOutdatedPods deletes at once all the pods of the statefulset that do not run its current
+// template, for the statefulset to recreate them with it. It returns whether pods were deleted.
+func restartOutdatedPods(ctx context.Context, clientset kubernetes.Interface, sts *appsv1.StatefulSet,
+	log logr.Logger) (bool, error) {
+	if sts.Status.ObservedGeneration < sts.Generation || sts.Status.UpdateRevision == "" {
+		return false, NotReadyErr{Err: errors.New("statefulset update is not observed yet")}
+	}
+
+	pods, err := clientset.CoreV1().Pods(sts.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set(sts.Spec.Selector.MatchLabels).AsSelector().String(),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list the pods of statefulset %s", sts.Name)
+	}
+
+	restarted := false
+	for _, pod := range pods.Items {
+		if pod.Labels[appsv1.ControllerRevisionHashLabelKey] == sts.Status.UpdateRevision || pod.DeletionTimestamp != nil {
+			continue
+		}
+		log.Info("restarting the pod with the new template", "pod", pod.Name)
+		if err := deletePod(ctx, clientset, sts.Namespace, pod.Name); err != nil {
+			return false, err
+		}
+		restarted = true
+	}
+	return restarted, nil
+}
@@ -18,13 +18,12 @@ package actor
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
-	"github.com/cockroachdb/cockroach-operator/pkg/database"
+	"github.com/cockroachdb/cockroach-operator/pkg/condition"
 	"github.com/cockroachdb/cockroach-operator/pkg/healthchecker"
 	"github.com/cockroachdb/cockroach-operator/pkg/resource"
 	"github.com/cockroachdb/cockroach-operator/pkg/update"
@@ -32,12 +31,16 @@ import (
 	"github.com/go-logr/logr"
 	"go.uber.org/zap/zapcore"
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// pausedUpdateCheckInterval is how often the canaries of a paused update are checked again.
+const pausedUpdateCheckInterval = time.Minute
+
 func newPartitionedUpdate(cl client.Client, config *rest.Config, clientset kubernetes.Interface) Actor {
 	return &partitionedUpdate{
 		action: newAction(nil, cl, config, clientset),
@@ -75,7 +78,9 @@ func (up *partitionedUpdate) Act(ctx context.Context, cluster *resource.Cluster,
 		return errors.Wrap(err, "failed to fetch statefulset")
 	}
 
-	if statefulSetIsUpdating(statefulSet) {
+	// A partitioned update that holds on its canaries leaves the statefulset updating
+	// until it is continued here.
+	if statefulSetIsUpdating(statefulSet) && !partitionedUpdateInFlight(statefulSet) {
 		return NotReadyErr{Err: errors.New("statefulset is updating, waiting for the update to finish")}
 	}
 
@@ -104,8 +109,62 @@ func (up *partitionedUpdate) Act(ctx context.Context, cluster *resource.Cluster,
 		return nil
 	}
 
+	// A dry run only previews the upgrade, the statefulset is left untouched.
+	if cluster.UpgradeDryRun() {
+		if currentVersionCalFmtStr == versionWantedCalFmtStr {
+			cluster.SetUpgradePreview(nil)
+			return nil
+		}
+		return up.previewUpgrade(ctx, cluster, statefulSet, currentVersionCalFmtStr, versionWantedCalFmtStr, containerWanted, log)
+	}
+	cluster.SetUpgradePreview(nil)
+
+	// An upgrade that was rolled back is not retried until another version is requested.
+	requestedVersion := versionWantedCalFmtStr
+	if rolledBack := cluster.Status().RolledBackVersion; rolledBack != "" {
+		if rolledBack == requestedVersion {
+			return PermanentErr{Err: errors.Newf("upgrade to %s was rolled back, request another version to retry", rolledBack)}
+		}
+		cluster.SetRolledBackVersion("")
+	}
+
+	// The version and image the pods ran when the upgrade started are used to roll back a
+	// failed update. They are recorded once, so that a hop in flight, a paused update or
+	// soaking canaries, which already carry the new version on the statefulset, go back to
+	// where the cluster started from.
+	deployedImage := statefulSet.Annotations[resource.CrdbContainerImageAnnotation]
+	previousVersionCalFmtStr, previousImage := upgradeOrigin(cluster, statefulSet, requestedVersion)
+
+	// A multi-hop upgrade rolls out one release series at a time, the version and image
+	// of the current hop take the place of the requested ones.
+	hopInFlight := false
+	if cluster.UpgradeMultiHop() && currentVersionCalFmtStr != requestedVersion {
+		hopInFlight = partitionedUpdateInFlight(statefulSet)
+		if hopInFlight {
+			versionWantedCalFmtStr, containerWanted = currentVersionCalFmtStr, deployedImage
+			log.Info("continuing the upgrade hop that did not reach every pod", "hop", versionWantedCalFmtStr)
+		} else {
+			hopVersion, hopImage, err := planUpgradeHop(cluster, currentVersionCalFmtStr, requestedVersion, containerWanted)
+			if err != nil {
+				return err
+			}
+			versionWantedCalFmtStr, containerWanted = hopVersion, hopImage
+			log.Info("upgrading through an intermediate version", "hop", hopVersion, "plan", cluster.Status().UpgradePlan)
+		}
+	}
+
+	// A paused update is resumed when the target version did not change, the statefulset
+	// already carries the wanted version for the canaries in that case. The same goes for
+	// an update whose canaries are soaking.
+	paused := cluster.True(api.UpgradePausedCondition)
+	resuming := paused && currentVersionCalFmtStr == versionWantedCalFmtStr
+	soaking := cluster.Status().UpgradeSoakStartTime != nil && currentVersionCalFmtStr == versionWantedCalFmtStr
+	if cluster.Status().UpgradeSoakStartTime != nil && !soaking {
+		cluster.SetUpgradeSoakStartTime(nil)
+	}
+
 	// check annotation
-	if currentVersionCalFmtStr == versionWantedCalFmtStr {
+	if currentVersionCalFmtStr == versionWantedCalFmtStr && !resuming && !soaking && !hopInFlight {
 		log.Info("no version changes needed")
 		return nil
 	}
@@ -124,39 +183,6 @@ func (up *partitionedUpdate) Act(ctx context.Context, cluster *resource.Cluster,
 	podUpdateTimeout := 10 * time.Minute
 	podMaxPollingInterval := 30 * time.Minute
 
-	// test to see if we are running inside of Kubernetes
-	// If we are running inside of k8s we will not find this file.
-	runningInsideK8s := inK8s("/var/run/secrets/kubernetes.io/serviceaccount/token")
-
-	serviceName := cluster.PublicServiceAddress()
-	if runningInsideK8s {
-		log.V(DEBUGLEVEL).Info("operator is running inside of kubernetes, connecting to service for db connection")
-	} else {
-		serviceName = fmt.Sprintf("%s-0.%s.%s", cluster.Name(), cluster.Name(), cluster.Namespace())
-		log.V(DEBUGLEVEL).Info("operator is NOT inside of kubernetes, connnecting to pod ordinal zero for db connection")
-	}
-
-	// The connection needs to use the discovery service name because of the
-	// hostnames in the SSL certificates
-	conn := &database.DBConnection{
-		Ctx:              ctx,
-		Client:           up.client,
-		RestConfig:       up.config,
-		ServiceName:      serviceName,
-		Namespace:        cluster.Namespace(),
-		DatabaseName:     "system", // TODO we need to use variable instead of string
-		Port:             cluster.Spec().SQLPort,
-		RunningInsideK8s: runningInsideK8s,
-	}
-
-	// see https://github.com/cockroachdb/cockroach-operator/issues/204 for above TODO
-
-	if cluster.Spec().TLSEnabled {
-		conn.UseSSL = true
-		conn.ClientCertificateSecretName = cluster.ClientTLSSecretName()
-		conn.RootCertificateSecretName = cluster.NodeTLSSecretName()
-	}
-
 	// TODO we may have an error case where the operator will not finish an update, but will
 	// still try to make a database connection.
 	// see https://github.com/cockroachdb/cockroach-operator/issues/205
@@ -164,17 +190,17 @@ func (up *partitionedUpdate) Act(ctx context.Context, cluster *resource.Cluster,
 	// Create a new database connection for the update.
 	// TODO we may want to create this db connection later
 	// see https://github.com/cockroachdb/cockroach-operator/issues/207
-
-	db, err := database.NewDbConnection(conn)
+	db, err := up.openDatabase(ctx, cluster, log)
 	if err != nil {
-		return errors.Wrapf(err, "failed to create database connection")
+		return err
 	}
-	log.V(int(zapcore.DebugLevel)).Info("opened db connection")
 	defer db.Close()
 
 	// TODO test downgrades
 	// see https://github.com/cockroachdb/cockroach-operator/issues/208
 	healthChecker := healthchecker.NewHealthChecker(cluster, up.clientset, up.config)
+	healthChecker.SetClient(up.client)
+	healthChecker.SetDatabase(db)
 	log.V(int(zapcore.InfoLevel)).Info("update starting with partitioned update", "old version", currentVersionCalFmtStr, "new version", versionWantedCalFmtStr, "image", containerWanted)
 
 	updateRoach := &update.UpdateRoach{
@@ -191,6 +217,33 @@ func (up *partitionedUpdate) Act(ctx context.Context, cluster *resource.Cluster,
 		PodUpdateTimeout:      podUpdateTimeout,
 		PodMaxPollingInterval: podMaxPollingInterval,
 		HealthChecker:         healthChecker,
+		Canaries:              cluster.UpgradeCanaries(),
+		SoakDuration:          cluster.UpgradeSoakDuration(),
+	}
+	if soaking {
+		k8sCluster.SoakStartTime = cluster.Status().UpgradeSoakStartTime.Time
+	}
+
+	// Each hop starts from a finalized version, the previous major upgrade has to be
+	// finalized before the next one can preserve the running version.
+	if versionWantedCalFmtStr != requestedVersion && !hopInFlight && !resuming && !soaking &&
+		condition.False(api.UpgradeFinalizedCondition, cluster.Status().Conditions) {
+		if err := finalizeCompletedUpgrade(ctx, cluster, db, log); err != nil {
+			return err
+		}
+	}
+
+	// The canaries of a paused update have to be healthy again before the remaining
+	// pods are updated. Changing the version, for instance back to the previous one,
+	// moves the cluster forward without this check.
+	if resuming {
+		if err := update.CheckClusterHealth(ctx, updateRoach, k8sCluster, log); err != nil {
+			return RequeueAfterErr{Err: errors.Wrap(err, "update is paused, the canaries are still unhealthy"), After: pausedUpdateCheckInterval}
+		}
+		log.Info("canaries are healthy again, resuming the paused update")
+	}
+	if paused {
+		cluster.SetFalse(api.UpgradePausedCondition)
 	}
 
 	err = update.UpdateClusterCockroachVersion(
@@ -200,6 +253,48 @@ func (up *partitionedUpdate) Act(ctx context.Context, cluster *resource.Cluster,
 		log,
 	)
 
+	// The canaries hold the update until they have soaked, the update is continued by a
+	// later reconcile instead of holding this one.
+	var soakingErr update.CanariesSoakingErr
+	if errors.As(err, &soakingErr) {
+		start := metav1.NewTime(soakingErr.StartTime)
+		cluster.SetUpgradeSoakStartTime(&start)
+		log.Info("canaries are soaking", "since", start.Time, "next check", soakingErr.CheckAfter)
+		return RequeueAfterErr{Err: err, After: soakingErr.CheckAfter}
+	}
+	cluster.SetUpgradeSoakStartTime(nil)
+
+	var canaryErr update.CanaryFailedErr
+	var healthErr update.HealthCheckFailedErr
+	healthCheckFailed := errors.As(err, &canaryErr) || errors.As(err, &healthErr)
+
+	// A failed update goes back to the previous image through the same partitioned
+	// update, as long as the cluster can still be downgraded.
+	if healthCheckFailed && cluster.UpgradeAutoRollback() && !resuming && previousImage != "" {
+		previousVersion, parseErr := semver.NewVersion(previousVersionCalFmtStr)
+		if parseErr != nil {
+			return errors.Wrapf(parseErr, "failed to parse the version the upgrade started from: %s", previousVersionCalFmtStr)
+		}
+		rolledBack, rollbackErr := rollbackUpdate(ctx, updateRoach, k8sCluster, previousVersion, previousImage, log)
+		if rolledBack {
+			cluster.SetRolledBackVersion(requestedVersion)
+			cluster.SetUpgradeOrigin("", "")
+			cluster.SetUpgradePlan(nil)
+		}
+		if rollbackErr != nil {
+			return PermanentErr{Err: errors.Wrapf(rollbackErr, "failed to roll back sts %s after: %v", stsName, err)}
+		}
+		if rolledBack {
+			return PermanentErr{Err: errors.Wrapf(err, "rolled back sts %s to %s", stsName, previousVersionCalFmtStr)}
+		}
+	}
+
+	if errors.As(err, &canaryErr) {
+		cluster.SetTrue(api.UpgradePausedCondition)
+		log.Info("pausing update, the canaries failed their health checks", "canaries", canaryErr.Canaries)
+		return RequeueAfterErr{Err: errors.Wrapf(err, "paused update of sts %s", stsName), After: pausedUpdateCheckInterval}
+	}
+
 	// TODO set status so that we will not try to update the cluster again
 	// TODO set status to rollback cluster?
 	// This work is pending the status field updates
@@ -209,11 +304,111 @@ func (up *partitionedUpdate) Act(ctx context.Context, cluster *resource.Cluster,
 		return errors.Wrapf(err, "failed to update sts with partitioned update: %s", stsName)
 	}
 
+	// A major upgrade keeps the previous version around until it is finalized.
+	preserved, err := update.PreserveDowngradeVersion(ctx, db)
+	if err != nil {
+		return errors.Wrap(err, "failed to read preserve downgrade option")
+	}
+	if preserved != "" {
+		cluster.SetUpgradeFinalization(preserved)
+	}
+	if versionWantedCalFmtStr == requestedVersion {
+		cluster.SetUpgradePlan(nil)
+		cluster.SetUpgradeOrigin("", "")
+	}
+
 	// TODO set status that we are completed.
 	log.V(DEBUGLEVEL).Info("update completed with partitioned update", "new version", versionWantedCalFmtStr)
 	return nil
 }
 
+// partitionedUpdateInFlight returns true if a partitioned update stopped before it
+// reached the pod with ordinal zero.
+func partitionedUpdateInFlight(sts *appsv1.StatefulSet) bool {
+	rollingUpdate := sts.Spec.UpdateStrategy.RollingUpdate
+	return rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0
+}
+
+// upgradeOrigin returns the version and image the cluster ran when the upgrade to the requested
+// version started. They are recorded in the status the first time the statefulset differs from
+// the requested version, and cleared once every pod runs it.
+func upgradeOrigin(cluster *resource.Cluster, sts *appsv1.StatefulSet, requested string) (string, string) {
+	deployedVersion := sts.Annotations[resource.CrdbVersionAnnotation]
+	deployedImage := sts.Annotations[resource.CrdbContainerImageAnnotation]
+
+	if deployedVersion == requested && !partitionedUpdateInFlight(sts) {
+		cluster.SetUpgradeOrigin("", "")
+		return deployedVersion, deployedImage
+	}
+	if cluster.Status().UpgradeFromVersion == "" {
+		cluster.SetUpgradeOrigin(deployedVersion, deployedImage)
+	}
+	return cluster.Status().UpgradeFromVersion, cluster.Status().UpgradeFromImage
+}
+
+// planUpgradeHop plans a multi-hop upgrade from the current to the requested version
+// and returns the version and image of the first hop.
+func planUpgradeHop(cluster *resource.Cluster, current, requested, requestedImage string) (string, string, error) {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to parse container image version: %s", current)
+	}
+	requestedVersion, err := semver.NewVersion(requested)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to parse spec image version: %s", requested)
+	}
+
+	plan, err := update.PlanUpgrade(currentVersion, requestedVersion, resource.SupportedCrdbVersions())
+	if err != nil {
+		return "", "", ValidationError{Err: errors.Wrap(err, "failed to plan multi-hop upgrade")}
+	}
+	cluster.SetUpgradePlan(plan)
+
+	if len(plan) == 1 {
+		return requested, requestedImage, nil
+	}
+	image := resource.SupportedCrdbImage(plan[0])
+	if image == "" {
+		return "", "", ValidationError{Err: errors.Newf("no image found for the upgrade hop to %s", plan[0])}
+	}
+	return plan[0], image, nil
+}
+
+// rollbackUpdate moves the pods of a failed update back to the version and image the upgrade
+// started from. It returns false without touching the pods when the cluster can no longer be
+// downgraded to that version.
+func rollbackUpdate(
+	ctx context.Context,
+	failed *update.UpdateRoach,
+	cluster *update.UpdateCluster,
+	previousVersion *semver.Version,
+	previousImage string,
+	log logr.Logger,
+) (bool, error) {
+	allowed, err := update.RollbackAllowed(ctx, previousVersion, failed.WantVersion, failed.Db)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check if the update can be rolled back")
+	}
+	if !allowed {
+		log.Info("not rolling back the failed update, the cluster can no longer be downgraded")
+		return false, nil
+	}
+
+	log.Info("rolling back the failed update", "to", previousImage)
+	rollback := &update.UpdateRoach{
+		CurrentVersion: failed.WantVersion,
+		WantVersion:    previousVersion,
+		WantImageName:  previousImage,
+		StsName:        failed.StsName,
+		StsNamespace:   failed.StsNamespace,
+		Db:             failed.Db,
+	}
+	// Every pod goes straight back to the previous image, there is nothing to soak.
+	rollbackCluster := *cluster
+	rollbackCluster.Canaries = 0
+	return true, update.UpdateClusterCockroachVersion(ctx, rollback, &rollbackCluster, log)
+}
+
 // inK8s checks to see if the a file exists
 func inK8s(file string) bool {
 	_, err := os.Stat(file)
@@ -19,6 +19,12 @@ package actor
 import (
 	"os"
 	"testing"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 )
 
 func TestDeployedInCluster(t *testing.T) {
@@ -39,3 +45,76 @@ func TestDeployedInCluster(t *testing.T) {
 		t.Fatal("we should find the file")
 	}
 }
+
+func TestPartitionedUpdateInFlight(t *testing.T) {
+	sts := &appsv1.StatefulSet{}
+	require.False(t, partitionedUpdateInFlight(sts))
+
+	partition := int32(2)
+	sts.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition}
+	require.True(t, partitionedUpdateInFlight(sts))
+
+	partition = 0
+	require.False(t, partitionedUpdateInFlight(sts))
+}
+
+func TestPlanUpgradeHop(t *testing.T) {
+	t.Setenv("RELATED_IMAGE_COCKROACH_v23_1_28", "cockroachdb/cockroach:v23.1.28")
+	t.Setenv("RELATED_IMAGE_COCKROACH_v23_2_20", "cockroachdb/cockroach:v23.2.20")
+	t.Setenv("RELATED_IMAGE_COCKROACH_v24_1_5", "cockroachdb/cockroach:v24.1.5")
+	cluster := resource.NewCluster(&api.CrdbCluster{})
+
+	version, image, err := planUpgradeHop(&cluster, "v23.1.28", "v24.1.5", "cockroachdb/cockroach:v24.1.5")
+	require.NoError(t, err)
+	require.Equal(t, "v23.2.20", version)
+	require.Equal(t, "cockroachdb/cockroach:v23.2.20", image)
+	require.Equal(t, []string{"v23.2.20", "v24.1.5"}, cluster.Status().UpgradePlan)
+	require.Equal(t, "v23.2.20", cluster.Status().UpgradeHop)
+
+	// the last hop uses the image validated by the version checker
+	version, image, err = planUpgradeHop(&cluster, "v23.2.20", "v24.1.5", "registry.example.com/cockroach:v24.1.5")
+	require.NoError(t, err)
+	require.Equal(t, "v24.1.5", version)
+	require.Equal(t, "registry.example.com/cockroach:v24.1.5", image)
+	require.Equal(t, "v24.1.5", cluster.Status().UpgradeHop)
+
+	_, _, err = planUpgradeHop(&cluster, "v22.1.0", "v24.1.5", "cockroachdb/cockroach:v24.1.5")
+	var validationErr ValidationError
+	require.True(t, errors.As(err, &validationErr))
+}
+
+func TestUpgradeOrigin(t *testing.T) {
+	cluster := resource.NewCluster(&api.CrdbCluster{})
+	sts := &appsv1.StatefulSet{}
+	deploy := func(version string, partition int32) {
+		sts.Annotations = map[string]string{
+			resource.CrdbVersionAnnotation:        version,
+			resource.CrdbContainerImageAnnotation: "cockroachdb/cockroach:" + version,
+		}
+		sts.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition}
+	}
+
+	// the upgrade starts from the deployed version
+	deploy("v23.1.28", 0)
+	version, image := upgradeOrigin(&cluster, sts, "v24.1.5")
+	require.Equal(t, "v23.1.28", version)
+	require.Equal(t, "cockroachdb/cockroach:v23.1.28", image)
+
+	// a hop in flight still goes back to where the upgrade started
+	deploy("v23.2.20", 2)
+	version, image = upgradeOrigin(&cluster, sts, "v24.1.5")
+	require.Equal(t, "v23.1.28", version)
+	require.Equal(t, "cockroachdb/cockroach:v23.1.28", image)
+
+	// so do canaries that already carry the requested version
+	deploy("v24.1.5", 3)
+	version, _ = upgradeOrigin(&cluster, sts, "v24.1.5")
+	require.Equal(t, "v23.1.28", version)
+
+	// the origin is cleared once every pod runs the requested version
+	deploy("v24.1.5", 0)
+	version, _ = upgradeOrigin(&cluster, sts, "v24.1.5")
+	require.Equal(t, "v24.1.5", version)
+	require.Empty(t, cluster.Status().UpgradeFromVersion)
+	require.Empty(t, cluster.Status().UpgradeFromImage)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kubetypes "k8s.io/apimachinery/pkg/types"
@@ -67,12 +68,6 @@ func (rp *resizePVC) GetActionType() api.ActionType {
 
 // Act in this implementation resizes PVC volumes of a CR sts.
 func (rp *resizePVC) Act(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
-	// If we do not have a volume claim we do not have PVCs
-	if cluster.Spec().DataStore.VolumeClaim == nil {
-		log.Info("Skipping PVC resize as VolumeClaim does not exist")
-		return nil
-	}
-
 	// Get the sts and compare the sts size to the size in the CR
 	key := kubetypes.NamespacedName{
 		Namespace: cluster.Namespace(),
@@ -101,11 +96,9 @@ func (rp *resizePVC) Act(ctx context.Context, cluster *resource.Cluster, log log
 		return nil
 	}
 
-	stsStorageSizeDeployed := statefulSet.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage()
-	stsStorageSizeSet := cluster.Spec().DataStore.VolumeClaim.PersistentVolumeClaimSpec.Resources.Requests.Storage()
-
 	// If the sizes match do not resize
-	if stsStorageSizeDeployed.Equal(stsStorageSizeSet.DeepCopy()) {
+	sizes := claimTemplatesToResize(cluster, statefulSet)
+	if len(sizes) == 0 {
 		log.Info("Skipping PVC resize as sizes match")
 		return nil
 	}
@@ -113,7 +106,7 @@ func (rp *resizePVC) Act(ctx context.Context, cluster *resource.Cluster, log log
 	log.Info("Starting PVC resize")
 
 	// Find all of the PVCs and resize them
-	if err := rp.findAndResizePVC(ctx, statefulSet, cluster, rp.clientset, log); err != nil {
+	if err := rp.findAndResizePVC(ctx, statefulSet, cluster, sizes, rp.clientset, log); err != nil {
 		return errors.Wrapf(err, "updating PVCs for statefulset %s.%s", cluster.Namespace(), cluster.StatefulSetName())
 	}
 
@@ -144,29 +137,29 @@ func (rp *resizePVC) Act(ctx context.Context, cluster *resource.Cluster, log log
 	return nil
 }
 
-// updateSts updates the size of an STS' VolumeClaimTemplate to match the new size in the CR.
+// updateSts updates the VolumeClaimTemplates of an STS to match the CR, e.g. the new size or storage class.
 // In order to update the volume claim template we have to delete the STS without cascading and then
 // create the sts.
-func (rp *resizePVC) updateSts(ctx context.Context, sts *appsv1.StatefulSet, cluster *resource.Cluster, log logr.Logger) error {
+func (a action) updateSts(ctx context.Context, sts *appsv1.StatefulSet, cluster *resource.Cluster, log logr.Logger) error {
 
 	// delete the original sts, but do not delete the Pods
 	orphan := metav1.DeletePropagationOrphan
-	if err := rp.client.Delete(ctx, sts, &client.DeleteOptions{PropagationPolicy: &orphan}); err != nil {
+	if err := a.client.Delete(ctx, sts, &client.DeleteOptions{PropagationPolicy: &orphan}); err != nil {
 		return err
 	}
 
 	f := func() error {
-		return rp.recreateSTS(ctx, cluster, log)
+		return a.recreateSTS(ctx, cluster, log)
 	}
 
 	b := backoffFactory(5 * time.Minute)
 	return backoff.Retry(f, backoff.WithContext(b, ctx))
 }
 
-func (rp *resizePVC) recreateSTS(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+func (a action) recreateSTS(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
 	// Use same StatefulSetBuilder that we run in Deploy to
 	// rebuild and save the StatefulSet with the new PVC size
-	r := resource.NewManagedKubeResource(ctx, rp.client, cluster, kube.AnnotatingPersister)
+	r := resource.NewManagedKubeResource(ctx, a.client, cluster, kube.AnnotatingPersister)
 	_, err := (resource.Reconciler{
 		ManagedResource: r,
 		Builder: resource.StatefulSetBuilder{
@@ -174,7 +167,7 @@ func (rp *resizePVC) recreateSTS(ctx context.Context, cluster *resource.Cluster,
 			Selector: r.Labels.Selector(cluster.Spec().AdditionalLabels),
 		},
 		Owner:  cluster.Unwrap(),
-		Scheme: rp.scheme,
+		Scheme: a.scheme,
 	}).Reconcile()
 
 	if err != nil {
@@ -183,26 +176,37 @@ func (rp *resizePVC) recreateSTS(ctx context.Context, cluster *resource.Cluster,
 	return err
 }
 
-// findAndResizePVC finds all active PVCs and resizes them to the new size contained in the cluster
-// definition.
+// claimTemplatesToResize returns the new size of each volume claim template of the statefulset
+// whose requested storage does not match the size specified in the cluster.
+func claimTemplatesToResize(cluster *resource.Cluster, sts *appsv1.StatefulSet) map[string]apiresource.Quantity {
+	sizes := resource.VolumeClaimTemplateSizes(cluster)
+	resize := make(map[string]apiresource.Quantity)
+	for _, pvct := range sts.Spec.VolumeClaimTemplates {
+		size, ok := sizes[pvct.Name]
+		if ok && !pvct.Spec.Resources.Requests.Storage().Equal(size) {
+			resize[pvct.Name] = size
+		}
+	}
+	return resize
+}
+
+// findAndResizePVC finds all active PVCs of the given volume claim templates and resizes them to
+// the new size of their template.
 func (rp *resizePVC) findAndResizePVC(ctx context.Context, sts *appsv1.StatefulSet, cluster *resource.Cluster,
-	clientset kubernetes.Interface, log logr.Logger) error {
+	sizes map[string]apiresource.Quantity, clientset kubernetes.Interface, log logr.Logger) error {
 	// K8s doesn't provide a way to tell if a PVC or PV is currently in use by
 	// a pod. However, it is safe to assume that any PVCs with an ordinal great
 	// than or equal to the sts' Replicas is not in use. As only pods with with
 	// an ordinal < Replicas will exist. Any PVCs with an ordinal less than
-	// Replicas is in use. To detect this, we build a map of PVCs that we
-	// consider to be in use and skip and PVCs that it contains
+	// Replicas is in use. To detect this, we build a map of the new size of the
+	// PVCs that we consider to be in use and skip the PVCs that it does not contain
 	// the name of.
 	log.Info("starting finding and resizing all PVCs")
-	prefixes := make([]string, len(sts.Spec.VolumeClaimTemplates))
-	pvcsToKeep := make(map[string]bool, int(*sts.Spec.Replicas)*len(sts.Spec.VolumeClaimTemplates))
-	for j, pvct := range sts.Spec.VolumeClaimTemplates {
-		prefixes[j] = fmt.Sprintf("%s-%s-", pvct.Name, sts.Name)
-
+	pvcSizes := make(map[string]apiresource.Quantity, int(*sts.Spec.Replicas)*len(sizes))
+	for template, size := range sizes {
 		for i := int32(0); i < *sts.Spec.Replicas; i++ {
-			name := fmt.Sprintf("%s-%s-%d", pvct.Name, sts.Name, i)
-			pvcsToKeep[name] = true
+			name := fmt.Sprintf("%s-%s-%d", template, sts.Name, i)
+			pvcSizes[name] = size
 		}
 	}
 
@@ -222,9 +226,8 @@ func (rp *resizePVC) findAndResizePVC(ctx context.Context, sts *appsv1.StatefulS
 	log.Info("resizing PVCs")
 	for _, pvc := range pvcs.Items {
 		// Resize PVCs that are still in use
-		if pvcsToKeep[pvc.Name] {
-			size := cluster.Spec().DataStore.VolumeClaim.PersistentVolumeClaimSpec.Resources.Requests.Storage()
-			pvc.Spec.Resources.Requests[v1.ResourceStorage] = *size
+		if size, ok := pvcSizes[pvc.Name]; ok {
+			pvc.Spec.Resources.Requests[v1.ResourceStorage] = size
 
 			if _, err := clientset.CoreV1().PersistentVolumeClaims(cluster.Namespace()).Update(ctx, &pvc, metav1.UpdateOptions{}); err != nil {
 				return errors.Wrap(err, "error resizing PVCs")
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"testing"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClaimTemplatesToResize(t *testing.T) {
+	claimSpec := func(size string) v1.PersistentVolumeClaimSpec {
+		return v1.PersistentVolumeClaimSpec{
+			Resources: v1.VolumeResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: apiresource.MustParse(size)},
+			},
+		}
+	}
+	template := func(name, size string) v1.PersistentVolumeClaim {
+		return v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: claimSpec(size)}
+	}
+
+	cr := testutil.NewBuilder("cockroachdb").Namespaced("default").WithPVDataStore("1Gi").Cr()
+	cr.Spec.DataStore.Stores = []api.Store{{Name: "store-1", Spec: claimSpec("4Gi")}}
+	cr.Spec.DataStore.WALFailover = &api.WALFailoverVolume{Spec: claimSpec("1Gi")}
+	cluster := resource.NewCluster(cr)
+
+	sts := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{
+		VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+			template("datadir", "1Gi"),
+			template("store-1", "2Gi"),
+			template("wal-failover", "500Mi"),
+		},
+	}}
+
+	require.Equal(t, map[string]apiresource.Quantity{
+		"store-1":      apiresource.MustParse("4Gi"),
+		"wal-failover": apiresource.MustParse("1Gi"),
+	}, actor.ClaimTemplatesToResize(&cluster, sts))
+
+	sts.Spec.VolumeClaimTemplates = []v1.PersistentVolumeClaim{
+		template("datadir", "1Gi"),
+		template("store-1", "4Gi"),
+		template("wal-failover", "1Gi"),
+	}
+	require.Empty(t, actor.ClaimTemplatesToResize(&cluster, sts))
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/labels"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// newRetainVolumes creates and returns a new retainVolumes struct
+func newRetainVolumes(cl client.Client, clientset kubernetes.Interface, recorder record.EventRecorder) Actor {
+	return &retainVolumes{
+		action:   newAction(nil, cl, nil, clientset),
+		recorder: recorder,
+	}
+}
+
+// retainVolumes applies the retention policy of the persistent volumes when the cluster is deleted,
+// and deletes the Secrets of its client certificates. The operator finalizer holds the deletion of
+// the clusters whose volumes or client certificates are deleted with them.
+type retainVolumes struct {
+	action
+
+	recorder record.EventRecorder
+}
+
+// GetActionType returns api.RetainVolumesAction action used to set the cluster status errors
+func (rv *retainVolumes) GetActionType() api.ActionType {
+	return api.RetainVolumesAction
+}
+
+// Act registers the operator finalizer on the clusters with the Delete retention policy or with
+// client certificates. Once such a cluster is deleted, the final snapshot is taken if requested, the
+// PVCs of the nodes are deleted with the Delete retention policy, the Secrets of the client
+// certificates owned by the cluster are deleted and the finalizer is removed.
+func (rv *retainVolumes) Act(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+	if !cluster.IsBeingDeleted() {
+		return rv.updateFinalizer(ctx, cluster, cluster.NeedsFinalizer(), log)
+	}
+
+	if cluster.RetentionPolicy() == api.DeletePolicy {
+		pvcs, err := clusterVolumes(ctx, rv.clientset, cluster)
+		if err != nil {
+			return err
+		}
+
+		deleteVolumes := true
+		if cluster.Spec().DataStore.FinalSnapshot {
+			if deleteVolumes, err = rv.finalSnapshot(ctx, cluster, pvcs, log); err != nil {
+				return err
+			}
+		}
+
+		if deleteVolumes {
+			log.Info("deleting the volumes of the nodes", "pvcs", len(pvcs))
+			for _, pvc := range pvcs {
+				err := rv.clientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+				if err != nil && !kube.IsNotFound(err) {
+					return errors.Wrapf(err, "failed to delete PVC %s", pvc.Name)
+				}
+			}
+			rv.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "VolumesDeleted",
+				"Deleted %d PVCs with the cluster", len(pvcs))
+		}
+	}
+
+	if err := deleteClientSecrets(ctx, rv.client, cluster, log); err != nil {
+		return err
+	}
+
+	return rv.updateFinalizer(ctx, cluster, false, log)
+}
+
+// finalSnapshot takes the final snapshot of the volumes and waits until it is ready to use. It
+// returns false if the snapshot failed, the volumes are then retained.
+func (rv *retainVolumes) finalSnapshot(ctx context.Context, cluster *resource.Cluster,
+	pvcs []corev1.PersistentVolumeClaim, log logr.Logger) (bool, error) {
+	waiting := RequeueAfterErr{Err: errors.New("waiting for the final snapshot to be ready to use"), After: snapshotCheckInterval}
+
+	name := fmt.Sprintf("final-%d", cluster.Unwrap().DeletionTimestamp.Unix())
+	snapshot := cluster.Snapshot(name)
+	if snapshot == nil {
+		log.Info("taking the final snapshot of the volumes", "snapshot", name, "pvcs", len(pvcs))
+		status, err := snapshotVolumes(ctx, rv.client, cluster, pvcs, name)
+		if err != nil {
+			return false, err
+		}
+		status.Nodes = cluster.Spec().Nodes
+		cluster.SetSnapshotStatus(status)
+		rv.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "SnapshotCreated",
+			"Created %d VolumeSnapshots for the final snapshot %s", len(status.VolumeSnapshots), name)
+		return false, waiting
+	}
+	if snapshot.Error != "" {
+		return false, nil
+	}
+	if snapshot.ReadyToUse {
+		return true, nil
+	}
+
+	ready, message, err := volumeSnapshotsState(ctx, rv.client, cluster, *snapshot)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case message != "":
+		snapshot.Error = message
+		cluster.SetSnapshotStatus(*snapshot)
+		rv.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeWarning, "SnapshotFailed",
+			"The final snapshot failed, the volumes are retained: %s", message)
+		return false, nil
+	case ready:
+		snapshot.ReadyToUse = true
+		cluster.SetSnapshotStatus(*snapshot)
+		rv.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "SnapshotReady",
+			"The final snapshot %s is ready to use", name)
+		return true, nil
+	default:
+		return false, waiting
+	}
+}
+
+// updateFinalizer adds or removes the operator finalizer of the cluster.
+func (rv *retainVolumes) updateFinalizer(ctx context.Context, cluster *resource.Cluster, finalizer bool, log logr.Logger) error {
+	fetcher := resource.NewKubeFetcher(ctx, cluster.Namespace(), rv.client)
+	cr := resource.ClusterPlaceholder(cluster.Name())
+	if err := fetcher.Fetch(cr); err != nil {
+		return errors.Wrap(err, "failed to retrieve CrdbCluster resource on retain volumes action")
+	}
+
+	if finalizer {
+		log.Info("adding the finalizer", "finalizer", api.CrdbClusterFinalizer)
+		ctrlutil.AddFinalizer(cr, api.CrdbClusterFinalizer)
+	} else {
+		log.Info("removing the finalizer", "finalizer", api.CrdbClusterFinalizer)
+		ctrlutil.RemoveFinalizer(cr, api.CrdbClusterFinalizer)
+	}
+	return errors.Wrap(rv.client.Update(ctx, cr), "failed to update the finalizers of the cluster")
+}
+
+// clusterVolumes returns the PVCs of the nodes of the cluster, whatever the current size of the
+// statefulset.
+func clusterVolumes(ctx context.Context, clientset kubernetes.Interface, cluster *resource.Cluster) (
+	[]corev1.PersistentVolumeClaim, error) {
+	selector := labels.Common(cluster.Unwrap()).Selector(nil)
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(cluster.Namespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: k8slabels.SelectorFromSet(selector).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the PVCs of the cluster")
+	}
+	return pvcs.Items, nil
+}
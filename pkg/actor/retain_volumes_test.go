@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/labels"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/go-logr/zapr"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRetainVolumes(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    api.RetentionPolicy
+		remaining []string
+	}{
+		{
+			name:      "volumes are retained by default",
+			remaining: []string{"datadir-crdb-0", "datadir-crdb-1", "datadir-other-0"},
+		},
+		{
+			name:      "volumes are deleted with the cluster",
+			policy:    api.DeletePolicy,
+			remaining: []string{"datadir-other-0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cr := testutil.NewBuilder("crdb").Namespaced("default").WithPVDataStore("1Gi").Cr()
+			cr.Spec.DataStore.RetentionPolicy = tt.policy
+			cr.Finalizers = []string{api.CrdbClusterFinalizer}
+			now := metav1.Now()
+			cr.DeletionTimestamp = &now
+
+			claim := func(name string, claimLabels map[string]string) *v1.PersistentVolumeClaim {
+				return &v1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: claimLabels},
+				}
+			}
+			selector := labels.Common(cr).Selector(nil)
+			clientset := fake.NewSimpleClientset(
+				claim("datadir-crdb-0", selector),
+				claim("datadir-crdb-1", selector),
+				claim("datadir-other-0", map[string]string{"app.kubernetes.io/instance": "other"}),
+			)
+			cl := ctrlfake.NewClientBuilder().WithScheme(testutil.InitScheme(t)).WithObjects(cr).Build()
+
+			cluster := resource.NewCluster(cr)
+			retainVolumes := actor.NewRetainVolumes(cl, clientset, record.NewFakeRecorder(10))
+			require.NoError(t, retainVolumes.Act(ctx, &cluster, zapr.NewLogger(zaptest.NewLogger(t))))
+
+			pvcs, err := clientset.CoreV1().PersistentVolumeClaims("default").List(ctx, metav1.ListOptions{})
+			require.NoError(t, err)
+			var remaining []string
+			for _, pvc := range pvcs.Items {
+				remaining = append(remaining, pvc.Name)
+			}
+			require.Equal(t, tt.remaining, remaining)
+
+			// the cluster is gone once the finalizer is removed
+			err = cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: "crdb"}, &api.CrdbCluster{})
+			require.True(t, kube.IsNotFound(err))
+		})
+	}
+}
+
+func TestRetainVolumesDeletesClientSecrets(t *testing.T) {
+	ctx := context.Background()
+	cr := testutil.NewBuilder("crdb").Namespaced("default").WithPVDataStore("1Gi").Cr()
+	cr.Spec.Certificates = &api.CertificatesConfig{Clients: []api.ClientCertificate{
+		{User: "app", Namespace: "apps"},
+		{User: "batch"},
+	}}
+	cr.Finalizers = []string{api.CrdbClusterFinalizer}
+	now := metav1.Now()
+	cr.DeletionTimestamp = &now
+
+	owned := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      "crdb-client-app",
+		Namespace: "apps",
+		Labels:    map[string]string{"crdb.io/cluster": "crdb", "crdb.io/cluster-namespace": "default"},
+	}}
+	// a Secret of the same name created by someone else is kept
+	unowned := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "crdb-client-batch", Namespace: "default"}}
+	cl := ctrlfake.NewClientBuilder().WithScheme(testutil.InitScheme(t)).WithObjects(cr, owned, unowned).Build()
+
+	cluster := resource.NewCluster(cr)
+	retainVolumes := actor.NewRetainVolumes(cl, fake.NewSimpleClientset(), record.NewFakeRecorder(10))
+	require.NoError(t, retainVolumes.Act(ctx, &cluster, zapr.NewLogger(zaptest.NewLogger(t))))
+
+	err := cl.Get(ctx, types.NamespacedName{Namespace: "apps", Name: "crdb-client-app"}, &v1.Secret{})
+	require.True(t, kube.IsNotFound(err))
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: "crdb-client-batch"}, &v1.Secret{}))
+
+	err = cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: "crdb"}, &api.CrdbCluster{})
+	require.True(t, kube.IsNotFound(err))
+}
@@ -30,6 +30,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestSetupRBACActionAct(t *testing.T) {
@@ -52,7 +53,7 @@ func TestSetupRBACActionAct(t *testing.T) {
 	t.Run("creates service account, role, and role-binding", func(t *testing.T) {
 		client := testutil.NewFakeClient(scheme)
 		config := &rest.Config{}
-		actor := NewDirector(scheme, client, config, nil).GetActor(api.SetupRBACAction)
+		actor := NewDirector(scheme, client, config, nil, record.NewFakeRecorder(10)).GetActor(api.SetupRBACAction)
 		require.NoError(t, actor.Act(ctx, cluster, log))
 
 		sa := new(corev1.ServiceAccount)
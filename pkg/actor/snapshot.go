@@ -0,0 +1,299 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"github.com/cockroachdb/cockroach-operator/pkg/labels"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/scale"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// snapshotCheckInterval is the interval between two checks of the VolumeSnapshots that are not
+// ready to use yet.
+const snapshotCheckInterval = 10 * time.Second
+
+// newSnapshot creates and returns a new snapshot struct
+func newSnapshot(cl client.Client, clientset kubernetes.Interface, recorder record.EventRecorder) Actor {
+	return &snapshot{
+		action:   newAction(nil, cl, nil, clientset),
+		recorder: recorder,
+	}
+}
+
+// snapshot takes CSI snapshots of the volumes of the nodes
+type snapshot struct {
+	action
+
+	recorder record.EventRecorder
+}
+
+// GetActionType returns api.SnapshotAction action used to set the cluster status errors
+func (s *snapshot) GetActionType() api.ActionType {
+	return api.SnapshotAction
+}
+
+// Act takes the snapshot named by the crdb.io/snapshot annotation: a VolumeSnapshot is created
+// for every PVC used by the nodes and the snapshot is recorded in the status. The snapshots are
+// then checked until all their VolumeSnapshots are ready to use.
+func (s *snapshot) Act(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+	if name := cluster.GetAnnotationSnapshot(); name != "" && cluster.Snapshot(name) == nil {
+		if err := s.takeSnapshot(ctx, cluster, name, log); err != nil {
+			return err
+		}
+	}
+	return s.refreshSnapshots(ctx, cluster, log)
+}
+
+// takeSnapshot creates the VolumeSnapshots of the PVCs used by the nodes, once every node is
+// ready, and removes the snapshot annotation.
+func (s *snapshot) takeSnapshot(ctx context.Context, cluster *resource.Cluster, name string, log logr.Logger) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return ValidationError{Err: errors.Newf("invalid snapshot name %q: %s", name, strings.Join(errs, ", "))}
+	}
+
+	key := kubetypes.NamespacedName{
+		Namespace: cluster.Namespace(),
+		Name:      cluster.StatefulSetName(),
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := s.client.Get(ctx, key, sts); err != nil {
+		return errors.Wrap(err, "failed to fetch statefulset")
+	}
+	if statefulSetIsUpdating(sts) || sts.Status.ReadyReplicas != *sts.Spec.Replicas {
+		return NotReadyErr{Err: errors.New("snapshot statefulset does not have all replicas ready")}
+	}
+
+	pvcs, err := scale.PVCsInUse(ctx, s.clientset, sts)
+	if err != nil {
+		return err
+	}
+
+	log.Info("taking a snapshot of the volumes", "snapshot", name, "pvcs", len(pvcs))
+	status, err := snapshotVolumes(ctx, s.client, cluster, pvcs, name)
+	if err != nil {
+		return err
+	}
+	status.Nodes = *sts.Spec.Replicas
+	cluster.SetSnapshotStatus(status)
+	s.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "SnapshotCreated",
+		"Created %d VolumeSnapshots for the snapshot %s, they are only crash-consistent per volume",
+		len(status.VolumeSnapshots), name)
+
+	// the snapshot is taken, the annotation is removed
+	fetcher := resource.NewKubeFetcher(ctx, cluster.Namespace(), s.client)
+	cr := resource.ClusterPlaceholder(cluster.Name())
+	if err := fetcher.Fetch(cr); err != nil {
+		return errors.Wrap(err, "failed to retrieve CrdbCluster resource on snapshot action")
+	}
+	refreshedCluster := resource.NewCluster(cr)
+	refreshedCluster.DeleteSnapshotAnnotation()
+	if err := s.client.Update(ctx, refreshedCluster.Unwrap()); err != nil {
+		log.Error(err, "failed removing the snapshot annotation")
+	}
+	return nil
+}
+
+// refreshSnapshots records the snapshots whose VolumeSnapshots are all ready to use, or one of
+// which failed. The request is requeued while a snapshot is neither ready nor failed.
+func (s *snapshot) refreshSnapshots(ctx context.Context, cluster *resource.Cluster, log logr.Logger) error {
+	pending := false
+	for _, snapshot := range cluster.Status().Snapshots {
+		if snapshot.ReadyToUse || snapshot.Error != "" {
+			continue
+		}
+
+		ready, message, err := volumeSnapshotsState(ctx, s.client, cluster, snapshot)
+		if err != nil {
+			return err
+		}
+		snapshot.Error = message
+
+		switch {
+		case snapshot.Error != "":
+			log.Info("snapshot failed", "snapshot", snapshot.Name, "error", snapshot.Error)
+			s.recorder.Event(cluster.Unwrap(), corev1.EventTypeWarning, "SnapshotFailed", snapshot.Error)
+		case ready:
+			snapshot.ReadyToUse = true
+			log.Info("snapshot ready to use", "snapshot", snapshot.Name)
+			s.recorder.Eventf(cluster.Unwrap(), corev1.EventTypeNormal, "SnapshotReady",
+				"The snapshot %s is ready to use", snapshot.Name)
+		default:
+			pending = true
+		}
+		cluster.SetSnapshotStatus(snapshot)
+	}
+
+	if pending {
+		return RequeueAfterErr{Err: errors.New("waiting for the VolumeSnapshots to be ready to use"), After: snapshotCheckInterval}
+	}
+	return nil
+}
+
+// snapshotVolumes creates a VolumeSnapshot of each PVC for the snapshot with the given name and
+// returns the status of the snapshot.
+func snapshotVolumes(ctx context.Context, cl client.Client, cluster *resource.Cluster,
+	pvcs []corev1.PersistentVolumeClaim, name string) (api.SnapshotStatus, error) {
+	snapshotLabels := labels.Common(cluster.Unwrap()).Selector(cluster.Spec().AdditionalLabels)
+	snapshotLabels[resource.CrdbSnapshotAnnotation] = name
+	status := api.SnapshotStatus{
+		Name:         name,
+		CreationTime: metav1.Now(),
+		Consistency:  api.SnapshotConsistencyVolume,
+	}
+	for _, pvc := range pvcs {
+		vs := resource.NewVolumeSnapshot(cluster, pvc.Name, name, snapshotLabels)
+		if err := cl.Create(ctx, vs); err != nil && !kube.IsAlreadyExists(err) {
+			return status, errors.Wrapf(err, "failed to create VolumeSnapshot %s", vs.GetName())
+		}
+		status.VolumeSnapshots = append(status.VolumeSnapshots, vs.GetName())
+	}
+	return status, nil
+}
+
+// volumeSnapshotsState returns whether all the VolumeSnapshots of a snapshot are ready to use, and
+// the error message of the first one that failed.
+func volumeSnapshotsState(ctx context.Context, cl client.Client, cluster *resource.Cluster,
+	snapshot api.SnapshotStatus) (bool, string, error) {
+	ready := true
+	for _, name := range snapshot.VolumeSnapshots {
+		vs := resource.VolumeSnapshotPlaceholder(name)
+		if err := cl.Get(ctx, kubetypes.NamespacedName{Namespace: cluster.Namespace(), Name: name}, vs); err != nil {
+			return false, "", errors.Wrapf(err, "failed to fetch VolumeSnapshot %s", name)
+		}
+		vsReady, message := resource.VolumeSnapshotState(vs)
+		if message != "" {
+			return false, fmt.Sprintf("VolumeSnapshot %s failed: %s", name, message), nil
+		}
+		ready = ready && vsReady
+	}
+	return ready, "", nil
+}
+
+// pruneSnapshotter takes a snapshot of the PVCs of the decommissioned nodes before they are pruned.
+type pruneSnapshotter struct {
+	client  client.Client
+	cluster *resource.Cluster
+}
+
+// SnapshotVolume creates the VolumeSnapshot of a PVC and returns true once it is ready to use.
+func (ps pruneSnapshotter) SnapshotVolume(ctx context.Context, pvc corev1.PersistentVolumeClaim) (bool, error) {
+	name := prunedVolumeSnapshotName(pvc)
+	snapshotLabels := labels.Common(ps.cluster.Unwrap()).Selector(ps.cluster.Spec().AdditionalLabels)
+	snapshotLabels[resource.CrdbSnapshotAnnotation] = "pruned"
+	vs := resource.NewVolumeSnapshot(ps.cluster, pvc.Name, prunedSnapshot(pvc), snapshotLabels)
+	if err := ps.client.Create(ctx, vs); err != nil && !kube.IsAlreadyExists(err) {
+		return false, errors.Wrapf(err, "failed to create VolumeSnapshot %s", name)
+	}
+
+	vs = resource.VolumeSnapshotPlaceholder(name)
+	if err := ps.client.Get(ctx, kubetypes.NamespacedName{Namespace: ps.cluster.Namespace(), Name: name}, vs); err != nil {
+		return false, errors.Wrapf(err, "failed to fetch VolumeSnapshot %s", name)
+	}
+	ready, message := resource.VolumeSnapshotState(vs)
+	if message != "" {
+		return false, fmt.Errorf("VolumeSnapshot %s failed: %s", name, message)
+	}
+	return ready, nil
+}
+
+// prunedSnapshot returns the name of the snapshot of a pruned PVC. It includes the UID of the PVC,
+// a PVC of the same name created later for the same node gets its own snapshot.
+func prunedSnapshot(pvc corev1.PersistentVolumeClaim) string {
+	return "pruned-" + string(pvc.UID)
+}
+
+// prunedVolumeSnapshotName returns the name of the VolumeSnapshot of a pruned PVC.
+func prunedVolumeSnapshotName(pvc corev1.PersistentVolumeClaim) string {
+	return resource.VolumeSnapshotName(pvc.Name, prunedSnapshot(pvc))
+}
+
+// restoreVolumes creates the PVCs of a clone from the VolumeSnapshots of the snapshot named as the
+// data source of its data store, before its statefulset is created. The statefulset then uses
+// these PVCs instead of creating empty ones from its volume claim templates.
+func (d deploy) restoreVolumes(ctx context.Context, cluster *resource.Cluster, builder resource.StatefulSetBuilder,
+	log logr.Logger) error {
+	source := cluster.Spec().DataStore.DataSource
+	if source == nil {
+		return nil
+	}
+
+	key := kubetypes.NamespacedName{
+		Namespace: cluster.Namespace(),
+		Name:      cluster.StatefulSetName(),
+	}
+	if err := d.client.Get(ctx, key, &appsv1.StatefulSet{}); err == nil || !kube.IsNotFound(err) {
+		return err
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := builder.Build(sts); err != nil {
+		return err
+	}
+	sts.Namespace = cluster.Namespace()
+
+	log.Info("restoring the volumes from a snapshot", "cluster", source.Cluster, "snapshot", source.Snapshot)
+	pvcs := d.clientset.CoreV1().PersistentVolumeClaims(cluster.Namespace())
+	for _, pvc := range restoredClaims(sts, source) {
+		if _, err := pvcs.Create(ctx, &pvc, metav1.CreateOptions{}); err != nil && !kube.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to restore PVC %s", pvc.Name)
+		}
+	}
+	return nil
+}
+
+// restoredClaims returns the PVCs the statefulset would create from its volume claim templates,
+// with the VolumeSnapshot of the same volume of the same node of the source cluster as data source.
+func restoredClaims(sts *appsv1.StatefulSet, source *api.SnapshotSource) []corev1.PersistentVolumeClaim {
+	apiGroup := resource.VolumeSnapshotGVK.Group
+	var pvcs []corev1.PersistentVolumeClaim
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		for i := int32(0); i < *sts.Spec.Replicas; i++ {
+			pvc := corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      claimName(template.Name, sts.Name, i),
+					Namespace: sts.Namespace,
+					Labels:    sts.Spec.Selector.MatchLabels,
+				},
+				Spec: *template.Spec.DeepCopy(),
+			}
+			pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     resource.VolumeSnapshotGVK.Kind,
+				Name:     resource.VolumeSnapshotName(claimName(template.Name, source.Cluster, i), source.Snapshot),
+			}
+			pvcs = append(pvcs, pvc)
+		}
+	}
+	return pvcs
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor_test
+
+import (
+	"testing"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/ptr"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRestoredClaims(t *testing.T) {
+	ssd := "ssd"
+	selector := map[string]string{"app.kubernetes.io/instance": "clone"}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "clone", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.Int32(2),
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "datadir"},
+					Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &ssd},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "wal-failover"},
+				},
+			},
+		},
+	}
+
+	pvcs := actor.RestoredClaims(sts, &api.SnapshotSource{Cluster: "crdb", Snapshot: "nightly"})
+
+	var names, sources []string
+	for _, pvc := range pvcs {
+		require.Equal(t, "default", pvc.Namespace)
+		require.Equal(t, selector, pvc.Labels)
+		require.Equal(t, "snapshot.storage.k8s.io", *pvc.Spec.DataSource.APIGroup)
+		require.Equal(t, "VolumeSnapshot", pvc.Spec.DataSource.Kind)
+		names = append(names, pvc.Name)
+		sources = append(sources, pvc.Spec.DataSource.Name)
+	}
+	require.Equal(t, []string{
+		"datadir-clone-0", "datadir-clone-1", "wal-failover-clone-0", "wal-failover-clone-1",
+	}, names)
+	require.Equal(t, []string{
+		"datadir-crdb-0-nightly", "datadir-crdb-1-nightly", "wal-failover-crdb-0-nightly", "wal-failover-crdb-1-nightly",
+	}, sources)
+	require.Equal(t, "ssd", *pvcs[0].Spec.StorageClassName)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/update"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// previewUpgrade writes the steps of the pending upgrade to the status without touching
+// the statefulset. Anything that would stop the upgrade is reported as a blocker.
+func (up *partitionedUpdate) previewUpgrade(
+	ctx context.Context,
+	cluster *resource.Cluster,
+	statefulSet *appsv1.StatefulSet,
+	current, requested, requestedImage string,
+	log logr.Logger,
+) error {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse container image version: %s", current)
+	}
+	requestedVersion, err := semver.NewVersion(requested)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse spec image version: %s", requested)
+	}
+
+	preview := &api.UpgradePreview{FromVersion: current, ToVersion: requested}
+
+	hops := []string{requested}
+	if cluster.UpgradeMultiHop() {
+		plan, err := update.PlanUpgrade(currentVersion, requestedVersion, resource.SupportedCrdbVersions())
+		if err != nil {
+			preview.Blockers = append(preview.Blockers, err.Error())
+		} else {
+			hops = plan
+		}
+	}
+
+	replicas := *statefulSet.Spec.Replicas
+	if ready := statefulSet.Status.ReadyReplicas; ready != replicas {
+		preview.Blockers = append(preview.Blockers, fmt.Sprintf("%d of %d pods are ready", ready, replicas))
+	}
+	if cluster.Status().RolledBackVersion == requested {
+		preview.Blockers = append(preview.Blockers, fmt.Sprintf("the upgrade to %s was rolled back", requested))
+	}
+
+	// Only the first hop starts from the state of the running cluster
+	db, err := up.openDatabase(ctx, cluster, log)
+	if err != nil {
+		preview.Blockers = append(preview.Blockers, err.Error())
+	} else {
+		defer db.Close()
+	}
+
+	var duration time.Duration
+	from := currentVersion
+	for i, hop := range hops {
+		hopVersion, err := semver.NewVersion(hop)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse upgrade hop version: %s", hop)
+		}
+		hopImage := requestedImage
+		if i < len(hops)-1 {
+			hopImage = resource.SupportedCrdbImage(hop)
+		}
+
+		updateRoach := &update.UpdateRoach{
+			CurrentVersion: from,
+			WantVersion:    hopVersion,
+			WantImageName:  hopImage,
+			StsName:        statefulSet.Name,
+			StsNamespace:   statefulSet.Namespace,
+		}
+		if i == 0 {
+			updateRoach.Db = db
+		}
+		k8sCluster := &update.UpdateCluster{
+			Canaries:     cluster.UpgradeCanaries(),
+			SoakDuration: cluster.UpgradeSoakDuration(),
+		}
+
+		hopPreview := update.PreviewUpgrade(ctx, updateRoach, k8sCluster, replicas, log)
+		if i == 0 {
+			preview.Kind = hopPreview.Kind
+		}
+		preview.Steps = append(preview.Steps, hopPreview.Steps...)
+		preview.Blockers = append(preview.Blockers, hopPreview.Blockers...)
+		duration += hopPreview.Duration
+
+		if hopPreview.Kind == "MAJOR_UPGRADE" {
+			preview.Steps = append(preview.Steps, finalizationStep(cluster, hop))
+		}
+		from = hopVersion
+	}
+	preview.EstimatedDuration = &metav1.Duration{Duration: duration}
+
+	cluster.SetUpgradePreview(preview)
+	log.Info("previewed upgrade, the statefulset is left untouched", "from", current, "to", requested, "blockers", len(preview.Blockers))
+	return nil
+}
+
+// finalizationStep describes how a major upgrade to version gets finalized.
+func finalizationStep(cluster *resource.Cluster, version string) string {
+	if cluster.UpgradeFinalizeMode() == api.UpgradeFinalizeAuto {
+		return fmt.Sprintf("finalize the upgrade to %s after %s", version, cluster.UpgradeAutoFinalizeDelay())
+	}
+	return fmt.Sprintf("wait for the upgrade to %s to be finalized manually", version)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustersql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cockroachdb/errors"
+)
+
+const nonLiveNodesQuery = `
+SELECT n.node_id
+FROM crdb_internal.gossip_nodes n
+JOIN crdb_internal.gossip_liveness l ON n.node_id = l.node_id
+WHERE l.membership = 'active' AND NOT n.is_live
+ORDER BY n.node_id`
+
+// NonLiveNodes returns the IDs of the active (not decommissioning or decommissioned)
+// nodes that node liveness currently reports as not live.
+func NonLiveNodes(ctx context.Context, db *sql.DB) ([]int, error) {
+	rows, err := db.QueryContext(ctx, nonLiveNodesQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query node liveness")
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan node liveness")
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, errors.Wrap(rows.Err(), "failed to read node liveness")
+}
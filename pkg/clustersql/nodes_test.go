@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustersql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/cockroachdb/cockroach-operator/pkg/clustersql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonLiveNodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	t.Run("returns the ids of nodes that are not live", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"node_id"}).AddRow(2).AddRow(4)
+		mock.ExpectQuery("SELECT n.node_id FROM crdb_internal.gossip_nodes").WillReturnRows(rows)
+
+		ids, err := NonLiveNodes(context.Background(), db)
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 4}, ids)
+	})
+
+	t.Run("returns nothing when all nodes are live", func(t *testing.T) {
+		mock.ExpectQuery("SELECT n.node_id FROM crdb_internal.gossip_nodes").
+			WillReturnRows(sqlmock.NewRows([]string{"node_id"}))
+
+		ids, err := NonLiveNodes(context.Background(), db)
+		require.NoError(t, err)
+		require.Empty(t, ids)
+	})
+
+	t.Run("returns error when the query fails", func(t *testing.T) {
+		mock.ExpectQuery("SELECT n.node_id FROM crdb_internal.gossip_nodes").
+			WillReturnError(fmt.Errorf("boom"))
+
+		_, err := NonLiveNodes(context.Background(), db)
+		require.Error(t, err)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
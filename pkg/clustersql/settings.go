@@ -68,6 +68,18 @@ func SetClusterSetting(ctx context.Context, db *sql.DB, name, value string) erro
 	return nil
 }
 
+// ResetClusterSetting func
+func ResetClusterSetting(ctx context.Context, db *sql.DB, name string) error {
+	if err := validateSettingName(name); err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("RESET CLUSTER SETTING %s", name)); err != nil {
+		return errors.Wrapf(err, "failed to reset %s", name)
+	}
+	return nil
+}
+
 // RangeMoveDuration calculates the slowest time.Duration that a range would
 // reasonably take to move from one node to another.
 // This duration does not account for IOPs or cluster load. If used as a timeout
@@ -18,6 +18,8 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -35,11 +37,15 @@ import (
 	v1 "k8s.io/api/networking/v1"
 	"k8s.io/api/networking/v1beta1"
 	policy "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -56,6 +62,7 @@ type ClusterReconciler struct {
 // +kubebuilder:rbac:groups=crdb.cockroachlabs.com,resources=crdbclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=crdb.cockroachlabs.com,resources=crdbclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=crdb.cockroachlabs.com,resources=crdbclusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups=crdb.cockroachlabs.com,resources=crdbclustertemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch;create;patch;delete
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/approval,verbs=update
@@ -64,12 +71,14 @@ type ClusterReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps/status,verbs=get
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;delete;deletecollection
 // +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups=core,resources=pods/log,verbs=get
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list
-// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=list;update;delete
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;create;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;create;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;create;watch
@@ -77,6 +86,11 @@ type ClusterReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/scale,verbs=get;watch;update
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/finalizers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes;tlsroutes;tcproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets/status,verbs=get
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets/finalizers,verbs=get;list;watch
@@ -116,6 +130,36 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req reconcile.Request
 		return requeueIfError(client.IgnoreNotFound(err))
 	}
 
+	// the merged spec is only used by this reconciliation, it is never saved
+	var templateStatus *api.TemplateStatus
+	if cr.Spec.TemplateRef != nil && !cr.DeletionTimestamp.IsZero() {
+		// a deleted cluster is cleaned up with the spec last merged, its template may be gone or invalid
+		merged, err := cr.LastMerged()
+		if err != nil {
+			log.Error(err, "failed to read the spec last merged with the cluster template")
+			return requeueIfError(err)
+		}
+		cr.Spec = merged.Spec
+	} else if cr.Spec.TemplateRef != nil {
+		var err error
+		if templateStatus, err = r.applyTemplate(ctx, cr); err != nil {
+			log.Error(err, "failed to apply the cluster template")
+			failed := resource.NewCluster(cr)
+			cleanObj := failed.Unwrap()
+			failed.SetActionFailed(api.ApplyTemplateAction, err.Error())
+			if err := r.updateClusterStatus(ctx, log, &failed, cleanObj); err != nil {
+				log.Error(err, "failed to update cluster status")
+			}
+
+			// the cluster is reconciled again when it or its template changes
+			var validationErr actor.ValidationError
+			if errors.As(err, &validationErr) {
+				return noRequeue()
+			}
+			return requeueIfError(err)
+		}
+	}
+
 	cluster := resource.NewCluster(cr)
 	cluster.Fetcher = fetcher
 	cleanClusterObj := cluster.Unwrap()
@@ -148,16 +192,60 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req reconcile.Request
 		return noRequeue()
 	}
 
+	// record the revision of the template merged into the spec, it is applied once the actors roll it out,
+	// the template of a deleted cluster is not merged anymore
+	if !cluster.IsBeingDeleted() && (templateChanged(cluster.Status().Template, templateStatus) || cluster.Failed(api.ApplyTemplateAction)) {
+		cluster.SetTemplateStatus(templateStatus)
+		if cluster.Failed(api.ApplyTemplateAction) {
+			cluster.SetActionFinished(api.ApplyTemplateAction)
+		}
+		if err := r.updateClusterStatus(ctx, log, &cluster, cleanClusterObj); err != nil {
+			log.Error(err, "failed to update cluster status")
+			return requeueIfError(err)
+		}
+		return requeueImmediately()
+	}
+
+	// a change of the template waits for the maintenance window of the cluster
+	if wait := cluster.TemplateRolloutWait(time.Now()); wait > 0 {
+		log.Info("waiting for the maintenance window to roll out the template", "after", wait)
+		return requeueAfter(wait, nil)
+	}
+
 	actorToExecute, err := r.Director.GetActorToExecute(ctx, &cluster, log)
 	if err != nil {
 		return requeueAfter(30*time.Second, nil)
 	} else if actorToExecute == nil {
+		// the cluster has nothing left to roll out
+		if template := cluster.Status().Template; template != nil && template.AppliedRevision != template.ObservedRevision {
+			cluster.SetTemplateApplied()
+			if err := r.updateClusterStatus(ctx, log, &cluster, cleanClusterObj); err != nil {
+				log.Error(err, "failed to update cluster status")
+				return requeueIfError(err)
+			}
+		}
+		// the finalization of an upgrade waits without holding the other actors
+		if wait := cluster.UpgradeFinalizationWait(time.Now()); wait > 0 {
+			log.Info("No actor to run; requeueing for the upgrade finalization", "after", wait)
+			return requeueAfter(wait, nil)
+		}
 		log.Info("No actor to run; not requeueing")
 		return noRequeue()
 	}
 
 	log.Info(fmt.Sprintf("Running action with name: %s", actorToExecute.GetActionType()))
 	if err := actorToExecute.Act(ctx, &cluster, log); err != nil {
+		// Waiting for a point in time, this is not a failure of the action
+		var requeueAfterErr actor.RequeueAfterErr
+		if errors.As(err, &requeueAfterErr) {
+			log.V(int(zapcore.DebugLevel)).Info("requeueing", "reason", requeueAfterErr.Error(), "after", requeueAfterErr.After, "Action", actorToExecute.GetActionType())
+			if err := r.updateClusterStatus(ctx, log, &cluster, cleanClusterObj); err != nil {
+				log.Error(err, "failed to update cluster status")
+				return requeueIfError(err)
+			}
+			return requeueAfter(requeueAfterErr.After, nil)
+		}
+
 		// Save the error on the Status for each action
 		log.Info("Error on action", "Action", actorToExecute.GetActionType(), "err", err.Error())
 		cluster.SetActionFailed(actorToExecute.GetActionType(), err.Error())
@@ -199,6 +287,11 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req reconcile.Request
 		cluster.SetActionFinished(actorToExecute.GetActionType())
 	}
 
+	// the template is rolled out once the StatefulSet is deployed or updated with the merged spec
+	if actorToExecute.GetActionType() == api.DeployAction || actorToExecute.GetActionType() == api.PartitionedUpdateAction {
+		cluster.SetTemplateApplied()
+	}
+
 	if err := r.updateClusterStatus(ctx, log, &cluster, cleanClusterObj); err != nil {
 		log.Error(err, "failed to update cluster status")
 		return requeueIfError(err)
@@ -213,9 +306,126 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req reconcile.Request
 func (r *ClusterReconciler) updateClusterStatus(ctx context.Context, log logr.Logger, cluster *resource.Cluster,
 	cleanObj *api.CrdbCluster) error {
 	cluster.SetClusterStatus()
-	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		return r.Client.Status().Patch(ctx, cluster.Unwrap(), client.MergeFrom(cleanObj))
 	})
+	// a deleted cluster is gone once its finalizer is removed
+	if cluster.IsBeingDeleted() {
+		return client.IgnoreNotFound(err)
+	}
+	return err
+}
+
+// applyTemplate merges the template referenced by the cluster under the spec of the cluster, then
+// defaults and validates the merged spec like the webhooks do for a cluster without template: the
+// merged spec is validated as a new cluster, and as an update of the spec last merged with the
+// template. The errors that only the user can fix are returned as an actor.ValidationError.
+func (r *ClusterReconciler) applyTemplate(ctx context.Context, cr *api.CrdbCluster) (*api.TemplateStatus, error) {
+	template := &api.CrdbClusterTemplate{}
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Spec.TemplateRef.Name}
+	if err := r.Client.Get(ctx, key, template); err != nil {
+		err = errors.Wrapf(err, "failed to get the cluster template %s", key.Name)
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil, actor.ValidationError{Err: err}
+		}
+		return nil, err
+	}
+
+	patch, err := r.storedSpec(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := cr.DeepCopy()
+	if err := merged.MergeTemplate(template, patch); err != nil {
+		return nil, actor.ValidationError{Err: err}
+	}
+
+	ref := merged.Spec.TemplateRef
+	merged.Spec.TemplateRef = nil
+	if err := merged.Default(ctx, merged); err != nil {
+		return nil, actor.ValidationError{Err: errors.Wrapf(err, "failed to default the spec merged with the template %s", template.Name)}
+	}
+	if _, err := merged.ValidateCreate(ctx, merged); err != nil {
+		return nil, actor.ValidationError{Err: errors.Wrapf(err, "the spec merged with the template %s is not valid", template.Name)}
+	}
+
+	spec, err := json.Marshal(merged.Spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the spec merged with the template %s", template.Name)
+	}
+	status := &api.TemplateStatus{
+		Name:             template.Name,
+		ObservedRevision: template.Generation,
+		SpecHash:         fmt.Sprintf("%x", sha256.Sum256(spec)),
+		Spec:             &runtime.RawExtension{Raw: spec},
+	}
+	if last := cr.Status.Template; last != nil && last.Name == template.Name {
+		status.AppliedRevision = last.AppliedRevision
+	}
+
+	// a change of the template can not do what the webhook rejects in a change of the spec
+	if last := cr.Status.Template; last != nil && last.Spec != nil && last.SpecHash != status.SpecHash {
+		old, err := cr.LastMerged()
+		if err != nil {
+			return nil, err
+		}
+		old.Spec.TemplateRef = nil
+		if _, err := merged.ValidateUpdate(ctx, old, merged); err != nil {
+			return nil, actor.ValidationError{Err: errors.Wrapf(err,
+				"the spec merged with the template %s is not a valid update of the spec merged with revision %d of the template %s",
+				template.Name, last.ObservedRevision, last.Name)}
+		}
+	}
+
+	cr.Spec = merged.Spec
+	cr.Spec.TemplateRef = ref
+	return status, nil
+}
+
+// storedSpec returns the JSON of the spec of the cluster as stored in the API server, with only the
+// fields set by the user.
+func (r *ClusterReconciler) storedSpec(ctx context.Context, cr *api.CrdbCluster) ([]byte, error) {
+	stored := &unstructured.Unstructured{}
+	stored.SetGroupVersionKind(api.SchemeGroupVersion.WithKind("CrdbCluster"))
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, stored); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the stored spec of the cluster %s", cr.Name)
+	}
+
+	spec, _, err := unstructured.NestedMap(stored.Object, "spec")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the stored spec of the cluster %s", cr.Name)
+	}
+	return json.Marshal(spec)
+}
+
+// templateChanged returns true if the template merged into the spec of a cluster is not the one in
+// its status. The merged specs are compared by hash, the API server does not keep the formatting of
+// their JSON.
+func templateChanged(old, new *api.TemplateStatus) bool {
+	if old == nil || new == nil {
+		return old != new
+	}
+	return old.Name != new.Name || old.ObservedRevision != new.ObservedRevision || old.SpecHash != new.SpecHash
+}
+
+// clustersOfTemplate returns a request for each cluster that references the template.
+func (r *ClusterReconciler) clustersOfTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &api.CrdbClusterList{}
+	if err := r.Client.List(ctx, clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list the clusters of the template", "template", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, cluster := range clusters.Items {
+		if cluster.Spec.TemplateRef != nil && cluster.Spec.TemplateRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name},
+			})
+		}
+	}
+	return requests
 }
 
 // SetupWithManager registers the controller with the controller.Manager from controller-runtime
@@ -233,6 +443,8 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&policy.PodDisruptionBudget{}).
 		Owns(&kbatch.Job{}).
 		Owns(ingress).
+		Owns(&v1.NetworkPolicy{}).
+		Watches(&api.CrdbClusterTemplate{}, handler.EnqueueRequestsFromMapFunc(r.clustersOfTemplate)).
 		Complete(r)
 }
 
@@ -249,10 +461,11 @@ func InitClusterReconcilerWithLogger(l logr.Logger) func(ctrl.Manager) error {
 			return err
 		}
 		return (&ClusterReconciler{
-			Client:   mgr.GetClient(),
-			Log:      l,
-			Scheme:   mgr.GetScheme(),
-			Director: actor.NewDirector(mgr.GetScheme(), mgr.GetClient(), mgr.GetConfig(), clientset),
+			Client: mgr.GetClient(),
+			Log:    l,
+			Scheme: mgr.GetScheme(),
+			Director: actor.NewDirector(mgr.GetScheme(), mgr.GetClient(), mgr.GetConfig(), clientset,
+				mgr.GetEventRecorderFor("cockroach-operator")),
 		}).SetupWithManager(mgr)
 	}
 }
@@ -23,6 +23,7 @@ import (
 
 	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/actor"
+	"github.com/cockroachdb/cockroach-operator/pkg/condition"
 	"github.com/cockroachdb/cockroach-operator/pkg/controller"
 	"github.com/cockroachdb/cockroach-operator/pkg/resource"
 	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
@@ -127,6 +128,14 @@ func TestReconcile(t *testing.T) {
 			want:    ctrl.Result{RequeueAfter: 5 * time.Second},
 			wantErr: "",
 		},
+		{
+			name: "reconcile action waits for a point in time",
+			action: fakeActor{
+				err: actor.RequeueAfterErr{Err: errors.New("waiting"), After: time.Hour},
+			},
+			want:    ctrl.Result{RequeueAfter: time.Hour},
+			wantErr: "",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -188,3 +197,216 @@ func TestReconcileMigrationLabel(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, ctrl.Result{}, actual)
 }
+
+type specActor struct {
+	fakeActor
+	action api.ActionType
+	spec   *api.CrdbClusterSpec
+}
+
+func (a *specActor) GetActionType() api.ActionType {
+	return a.action
+}
+
+func (a *specActor) Act(_ context.Context, cluster *resource.Cluster, _ logr.Logger) error {
+	a.spec = cluster.Spec()
+	return nil
+}
+
+func TestReconcileTemplate(t *testing.T) {
+	scheme := testutil.InitScheme(t)
+	fs := corev1.PersistentVolumeFilesystem
+
+	cluster := testutil.NewBuilder("cluster").Namespaced("test-namespace").WithNodeCount(5).WithPVDataStore("100Gi").Cr()
+	cluster.Spec.Image = nil
+	cluster.Spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.VolumeMode = nil
+	cluster.Spec.TemplateRef = &api.TemplateReference{Name: "shared"}
+	cluster.Status.ClusterStatus = "Running"
+
+	template := &api.CrdbClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "test-namespace", Generation: 2},
+		Spec: api.CrdbClusterTemplateSpec{Spec: runtime.RawExtension{Raw: []byte(`{
+			"nodes": 3,
+			"cockroachDBVersion": "v24.1.0",
+			"tlsEnabled": true,
+			"dataStore": {"pvc": {"spec": {"volumeMode": "Filesystem"}}}
+		}`)}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, template).WithStatusSubresource(cluster).Build()
+	log := zapr.NewLogger(zaptest.NewLogger(t)).WithName("cluster-controller-test")
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}}
+
+	a := &specActor{action: api.DeployAction}
+	r := &controller.ClusterReconciler{Client: cl, Log: log, Scheme: scheme, Director: &fakeDirector{actorToExecute: a}}
+
+	// the revision of the template is recorded first
+	actual, err := r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{Requeue: true}, actual)
+	require.Nil(t, a.spec)
+
+	saved := &api.CrdbCluster{}
+	require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, saved))
+	require.Equal(t, "shared", saved.Status.Template.Name)
+	require.Equal(t, int64(2), saved.Status.Template.ObservedRevision)
+	require.Zero(t, saved.Status.Template.AppliedRevision)
+	require.NotEmpty(t, saved.Status.Template.SpecHash)
+	require.NotNil(t, saved.Status.Template.Spec)
+
+	// the actors get the spec merged with the template, the stored spec is unchanged
+	_, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NotNil(t, a.spec)
+	require.Equal(t, int32(5), a.spec.Nodes)
+	require.Equal(t, "v24.1.0", a.spec.CockroachDBVersion)
+	require.True(t, a.spec.TLSEnabled)
+	require.Equal(t, &fs, a.spec.DataStore.VolumeClaim.PersistentVolumeClaimSpec.VolumeMode)
+	require.Equal(t, api.DefaultSQLPort, *a.spec.SQLPort)
+	require.Equal(t, &api.TemplateReference{Name: "shared"}, a.spec.TemplateRef)
+
+	require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, saved))
+	require.Empty(t, saved.Spec.CockroachDBVersion)
+	require.Nil(t, saved.Spec.SQLPort)
+
+	// the revision is applied once deployed
+	require.Equal(t, int64(2), saved.Status.Template.AppliedRevision)
+
+	// a change of the template that the webhook rejects in a change of the spec is blocked
+	template.Spec.Spec.Raw = []byte(`{
+		"nodes": 3,
+		"cockroachDBVersion": "v24.1.0",
+		"tlsEnabled": true,
+		"additionalLabels": {"env": "prod"},
+		"dataStore": {"pvc": {"spec": {"volumeMode": "Filesystem"}}}
+	}`)
+	template.Generation = 3
+	require.NoError(t, cl.Update(context.TODO(), template))
+	a.spec = nil
+
+	actual, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, actual)
+	require.Nil(t, a.spec)
+
+	require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, saved))
+	require.Equal(t, api.ApplyTemplateAction, saved.Status.OperatorActions[0].Type)
+	require.Contains(t, saved.Status.OperatorActions[0].Message, "not a valid update of the spec merged with revision 2 of the template shared")
+	require.Contains(t, saved.Status.OperatorActions[0].Message, "mutating additionalLabels field is not supported")
+	require.Equal(t, int64(2), saved.Status.Template.ObservedRevision)
+
+	// an invalid template is reported in the status until it is fixed
+	template.Spec.Spec.Raw = []byte(`{"cockroachDBVersion": "v24.1.0", "nodeCount": 3}`)
+	require.NoError(t, cl.Update(context.TODO(), template))
+	a.spec = nil
+
+	actual, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, actual)
+	require.Nil(t, a.spec)
+
+	require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, saved))
+	require.Equal(t, api.ApplyTemplateAction, saved.Status.OperatorActions[0].Type)
+	require.Contains(t, saved.Status.OperatorActions[0].Message, `unknown field "nodeCount"`)
+}
+
+func TestReconcileTemplateMaintenanceWindow(t *testing.T) {
+	scheme := testutil.InitScheme(t)
+	now := time.Now().UTC()
+
+	cluster := testutil.NewBuilder("cluster").Namespaced("test-namespace").WithNodeCount(3).WithPVDataStore("100Gi").Cr()
+	cluster.Spec.TemplateRef = &api.TemplateReference{Name: "shared", MaintenanceWindow: &api.MaintenanceWindow{
+		Start:    now.Add(2 * time.Hour).Format("15:04"),
+		Duration: metav1.Duration{Duration: time.Hour},
+	}}
+	cluster.Status.ClusterStatus = "Running"
+	condition.SetTrue(api.CrdbInitializedCondition, &cluster.Status, metav1.Now())
+
+	template := &api.CrdbClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "test-namespace", Generation: 1},
+		Spec:       api.CrdbClusterTemplateSpec{Spec: runtime.RawExtension{Raw: []byte(`{"cockroachDBVersion": "v24.1.0"}`)}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, template).WithStatusSubresource(cluster).Build()
+	log := zapr.NewLogger(zaptest.NewLogger(t)).WithName("cluster-controller-test")
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}}
+
+	a := &specActor{action: api.PartitionedUpdateAction}
+	r := &controller.ClusterReconciler{Client: cl, Log: log, Scheme: scheme, Director: &fakeDirector{actorToExecute: a}}
+
+	// the revision is observed, then the rollout waits for the window
+	_, err := r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+
+	actual, err := r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.Nil(t, a.spec)
+	require.Greater(t, actual.RequeueAfter, time.Hour)
+	require.LessOrEqual(t, actual.RequeueAfter, 2*time.Hour)
+
+	saved := &api.CrdbCluster{}
+	require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, saved))
+	require.Equal(t, int64(1), saved.Status.Template.ObservedRevision)
+	require.Zero(t, saved.Status.Template.AppliedRevision)
+
+	// the rollout runs in the window and the revision is applied once the update is done
+	saved.Spec.TemplateRef.MaintenanceWindow = &api.MaintenanceWindow{
+		Start:    now.Add(-time.Hour).Format("15:04"),
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+	}
+	require.NoError(t, cl.Update(context.TODO(), saved))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NotNil(t, a.spec)
+	require.Equal(t, "v24.1.0", a.spec.CockroachDBVersion)
+
+	require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, saved))
+	require.Equal(t, int64(1), saved.Status.Template.AppliedRevision)
+}
+
+func TestReconcileTemplateDeletedCluster(t *testing.T) {
+	scheme := testutil.InitScheme(t)
+
+	cluster := testutil.NewBuilder("cluster").Namespaced("test-namespace").WithNodeCount(3).WithPVDataStore("100Gi").Cr()
+	cluster.Spec.TemplateRef = &api.TemplateReference{Name: "shared"}
+	cluster.Finalizers = []string{api.CrdbClusterFinalizer}
+	cluster.Status.ClusterStatus = "Running"
+
+	template := &api.CrdbClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "test-namespace", Generation: 1},
+		Spec: api.CrdbClusterTemplateSpec{Spec: runtime.RawExtension{Raw: []byte(`{
+			"cockroachDBVersion": "v24.1.0",
+			"dataStore": {"retentionPolicy": "Delete"}
+		}`)}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, template).WithStatusSubresource(cluster).Build()
+	log := zapr.NewLogger(zaptest.NewLogger(t)).WithName("cluster-controller-test")
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}}
+
+	a := &specActor{action: api.RetainVolumesAction}
+	r := &controller.ClusterReconciler{Client: cl, Log: log, Scheme: scheme, Director: &fakeDirector{actorToExecute: a}}
+
+	// the template is merged and recorded
+	_, err := r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+
+	// the template is deleted first, the cluster is not reconciled anymore
+	require.NoError(t, cl.Delete(context.TODO(), template))
+	actual, err := r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, actual)
+	require.Nil(t, a.spec)
+
+	// the deleted cluster is cleaned up with the spec last merged with its template
+	saved := &api.CrdbCluster{}
+	require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, saved))
+	require.NoError(t, cl.Delete(context.TODO(), saved))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	require.NotNil(t, a.spec)
+	require.Equal(t, "v24.1.0", a.spec.CockroachDBVersion)
+	require.Equal(t, api.DeletePolicy, a.spec.DataStore.RetentionPolicy)
+}
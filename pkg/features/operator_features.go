@@ -78,7 +78,8 @@ const (
 
 	// owner: @alina
 	// alpha: v1.7.13
-	// AutoPrunePVC uses crdb binary to prune PVC on decommission
+	// AutoPrunePVC uses crdb binary to prune PVC on decommission, once the node
+	// that ran on the PVC is reported decommissioned
 	AutoPrunePVC featuregate.Feature = "AutoPrunePVC"
 
 	// owner: @chrislovecnm
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthchecker
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+)
+
+// DataStoreUsage returns the highest percentage of the capacity of the data store used by a
+// cockroachdb pod, read from the _status/vars endpoint of every pod.
+func (hc *HealthCheckerImpl) DataStoreUsage(l logr.Logger, replicas int32) (int32, error) {
+	var highest float64
+	err := hc.forAllPods(replicas, func(podname string) error {
+		l.V(int(zapcore.DebugLevel)).Info("dataStoreUsage", "podname", podname)
+		resp, err := hc.get(l, podname, "/_status/vars")
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		usage, err := dataStoreUsage(resp.Body)
+		if err != nil {
+			return errors.Wrapf(err, "pod %s", podname)
+		}
+		highest = math.Max(highest, usage)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int32(math.Ceil(highest * 100)), nil
+}
+
+// dataStoreUsage returns the fraction of the capacity used by the data store of a node from the
+// capacity and capacity_available samples read from r, in the Prometheus text format. The data
+// store is the first store of the node, with the lowest store ID.
+func dataStoreUsage(r io.Reader) (float64, error) {
+	capacity := map[int]float64{}
+	available := map[int]float64{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "capacity") {
+			continue
+		}
+
+		name, labels, value, err := parseSample(line)
+		if err != nil {
+			return 0, err
+		}
+		if name != "capacity" && name != "capacity_available" {
+			continue
+		}
+		store, err := strconv.Atoi(labels["store"])
+		if err != nil {
+			return 0, errors.Errorf("invalid store label in the sample %q", line)
+		}
+		if name == "capacity" {
+			capacity[store] = value
+		} else {
+			available[store] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	store := -1
+	for id := range capacity {
+		if store == -1 || id < store {
+			store = id
+		}
+	}
+	if store == -1 || capacity[store] == 0 {
+		return 0, errors.New("no capacity samples found")
+	}
+	if _, ok := available[store]; !ok {
+		return 0, errors.Errorf("no capacity_available sample found for store %d", store)
+	}
+	return 1 - available[store]/capacity[store], nil
+}
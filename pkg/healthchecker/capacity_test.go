@@ -0,0 +1,45 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthchecker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataStoreUsage(t *testing.T) {
+	usage, err := dataStoreUsage(strings.NewReader(`# HELP capacity Total storage capacity
+# TYPE capacity gauge
+capacity{store="2"} 2000
+capacity{store="1"} 1000
+# HELP capacity_available Available storage capacity
+# TYPE capacity_available gauge
+capacity_available{store="2"} 100
+capacity_available{store="1"} 250
+capacity_used{store="1"} 600
+`))
+	require.NoError(t, err)
+	require.Equal(t, 0.75, usage)
+
+	_, err = dataStoreUsage(strings.NewReader(vars))
+	require.Error(t, err)
+
+	_, err = dataStoreUsage(strings.NewReader(`capacity{store="1"} 1000`))
+	require.Error(t, err)
+}
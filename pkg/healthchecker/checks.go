@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthchecker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/clustersql"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	underReplicatedRangesExpression = "ranges_underreplicated == 0"
+	unavailableRangesExpression     = "ranges_unavailable == 0"
+)
+
+// check is a single health check of the cluster
+type check struct {
+	name         string
+	usesDatabase bool
+	run          func(ctx context.Context, l logr.Logger, replicas int32) error
+}
+
+// newCheck builds the check for its configuration. A check that cannot be built
+// always fails, so that the reason shows up in the status.
+func (hc *HealthCheckerImpl) newCheck(c api.HealthCheck) check {
+	name := c.CheckName()
+	switch c.Type {
+	case api.UnderReplicatedRangesCheck:
+		return hc.newMetricCheck(name, underReplicatedRangesExpression)
+	case api.UnavailableRangesCheck:
+		return hc.newMetricCheck(name, unavailableRangesExpression)
+	case api.PrometheusExpressionCheck:
+		return hc.newMetricCheck(name, c.Expression)
+	case api.ReadyCheck:
+		return check{name: name, run: hc.checkReadyAllPods}
+	case api.NodeLivenessCheck:
+		return check{name: name, usesDatabase: true, run: hc.checkNodeLiveness}
+	case api.SQLCheck:
+		return check{name: name, usesDatabase: true, run: hc.checkSQL}
+	}
+	return failingCheck(name, errors.Errorf("unknown health check type %q", c.Type))
+}
+
+func failingCheck(name string, err error) check {
+	return check{name: name, run: func(context.Context, logr.Logger, int32) error {
+		return err
+	}}
+}
+
+// newMetricCheck returns a check of _status/vars on all cockroachdb pods. Every sample
+// of the metric has to satisfy the expression on every pod.
+func (hc *HealthCheckerImpl) newMetricCheck(name, text string) check {
+	e, err := parseExpression(text)
+	if err != nil {
+		return failingCheck(name, err)
+	}
+	return check{name: name, run: func(ctx context.Context, l logr.Logger, replicas int32) error {
+		return hc.forAllPods(replicas, func(podname string) error {
+			return hc.checkMetric(l, podname, e)
+		})
+	}}
+}
+
+// checkMetric evaluates the expression on the _status/vars endpoint of a specific pod
+func (hc *HealthCheckerImpl) checkMetric(l logr.Logger, podname string, e expression) error {
+	l.V(int(zapcore.DebugLevel)).Info("checkMetric", "podname", podname, "expression", e.String())
+	resp, err := hc.get(l, podname, "/_status/vars")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := e.evaluate(resp.Body); err != nil {
+		return errors.Wrapf(err, "pod %s", podname)
+	}
+	return nil
+}
+
+// checkReadyAllPods checks that /health?ready=1 answers OK on all cockroachdb pods
+func (hc *HealthCheckerImpl) checkReadyAllPods(_ context.Context, l logr.Logger, replicas int32) error {
+	return hc.forAllPods(replicas, func(podname string) error {
+		resp, err := hc.get(l, podname, "/health?ready=1")
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("pod %s is not ready: %s", podname, resp.Status)
+		}
+		return nil
+	})
+}
+
+// checkNodeLiveness checks that node liveness reports every active node as live
+func (hc *HealthCheckerImpl) checkNodeLiveness(ctx context.Context, _ logr.Logger, _ int32) error {
+	if hc.db == nil {
+		return errors.New("no database connection")
+	}
+	nodes, err := clustersql.NonLiveNodes(ctx, hc.db)
+	if err != nil {
+		return err
+	}
+	if len(nodes) > 0 {
+		return errors.Errorf("nodes %v are not live", nodes)
+	}
+	return nil
+}
+
+// checkSQL checks that the cluster answers a SQL query
+func (hc *HealthCheckerImpl) checkSQL(ctx context.Context, _ logr.Logger, _ int32) error {
+	if hc.db == nil {
+		return errors.New("no database connection")
+	}
+	var one int
+	if err := hc.db.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return errors.Wrap(err, "failed to query the cluster")
+	}
+	return nil
+}
+
+// forAllPods calls f for all cockroachdb pods, starting with the highest ordinal
+func (hc *HealthCheckerImpl) forAllPods(replicas int32, f func(podname string) error) error {
+	for partition := replicas - 1; partition >= 0; partition-- {
+		if err := f(fmt.Sprintf("%s-%v", hc.cluster.StatefulSetName(), partition)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
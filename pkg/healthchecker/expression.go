@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthchecker
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+var expressionRegexp = regexp.MustCompile(`^\s*([a-zA-Z_:][a-zA-Z0-9_:]*)(\{[^}]*\})?\s*(==|!=|<=|>=|<|>)\s*(\S+)\s*$`)
+
+// expression compares every sample of a metric with a number, for instance
+// ranges_underreplicated{store="1"} == 0
+type expression struct {
+	text   string
+	metric string
+	labels map[string]string
+	op     string
+	value  float64
+}
+
+// parseExpression parses an expression of the form metric{label="value",...} op number.
+// The label matchers are optional.
+func parseExpression(text string) (expression, error) {
+	m := expressionRegexp.FindStringSubmatch(text)
+	if m == nil {
+		return expression{}, errors.Errorf("invalid expression %q, expected metric{labels} op number", text)
+	}
+
+	e := expression{text: strings.TrimSpace(text), metric: m[1], op: m[3]}
+	if m[2] != "" {
+		labels, rest, err := scanLabels(m[2][1:])
+		if err != nil || rest != "" {
+			return expression{}, errors.Errorf("invalid label matchers in expression %q", text)
+		}
+		e.labels = labels
+	}
+
+	value, err := strconv.ParseFloat(m[4], 64)
+	if err != nil {
+		return expression{}, errors.Wrapf(err, "invalid number in expression %q", text)
+	}
+	e.value = value
+	return e, nil
+}
+
+func (e expression) String() string {
+	return e.text
+}
+
+// evaluate checks the samples read from r, in the Prometheus text format. It fails if a
+// matching sample does not satisfy the expression or if no sample matches.
+func (e expression) evaluate(r io.Reader) error {
+	found := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, e.metric) {
+			continue
+		}
+
+		name, labels, value, err := parseSample(line)
+		if err != nil {
+			return err
+		}
+		if name != e.metric || !e.matches(labels) {
+			continue
+		}
+
+		found = true
+		if !e.holds(value) {
+			return errors.Errorf("%s is %v, expected %s", strings.Fields(line)[0], value, e)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if !found {
+		return errors.Errorf("no samples found for %s", e)
+	}
+	return nil
+}
+
+func (e expression) matches(labels map[string]string) bool {
+	for name, value := range e.labels {
+		if labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (e expression) holds(value float64) bool {
+	switch e.op {
+	case "==":
+		return value == e.value
+	case "!=":
+		return value != e.value
+	case "<":
+		return value < e.value
+	case "<=":
+		return value <= e.value
+	case ">":
+		return value > e.value
+	case ">=":
+		return value >= e.value
+	}
+	return false
+}
+
+// parseSample parses a line like ranges_underreplicated{store="1"} 0 into its metric name,
+// labels and value. A trailing timestamp is ignored.
+func parseSample(line string) (string, map[string]string, float64, error) {
+	end := strings.IndexAny(line, "{ \t")
+	if end < 0 {
+		return "", nil, 0, errors.Errorf("incorrect format of the sample %q", line)
+	}
+	name, rest := line[:end], line[end:]
+
+	var labels map[string]string
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		if labels, rest, err = scanLabels(rest[1:]); err != nil {
+			return "", nil, 0, errors.Wrapf(err, "incorrect format of the sample %q", line)
+		}
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, errors.Errorf("incorrect format of the sample %q", line)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, errors.Wrapf(err, "incorrect format of the sample %q", line)
+	}
+	return name, labels, value, nil
+}
+
+// scanLabels parses label pairs like a="b",c="d"} and returns them together with the
+// text following the closing brace.
+func scanLabels(s string) (map[string]string, string, error) {
+	labels := map[string]string{}
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+
+		eq := strings.Index(s, "=")
+		if eq <= 0 {
+			return nil, "", errors.New("expected a label name")
+		}
+		name := strings.TrimSpace(s[:eq])
+		s = strings.TrimLeft(s[eq+1:], " \t")
+		if !strings.HasPrefix(s, `"`) {
+			return nil, "", errors.Errorf("expected a quoted value for label %s", name)
+		}
+
+		var value strings.Builder
+		i := 1
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+			}
+			value.WriteByte(s[i])
+		}
+		if i == len(s) {
+			return nil, "", errors.Errorf("unterminated value for label %s", name)
+		}
+		labels[name] = value.String()
+		s = s[i+1:]
+	}
+}
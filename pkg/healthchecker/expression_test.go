@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthchecker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const vars = `# HELP ranges_underreplicated Number of ranges with fewer live replicas than the replication target
+# TYPE ranges_underreplicated gauge
+ranges_underreplicated{store="1"} 0
+ranges_underreplicated{store="2"} 3
+# HELP ranges_unavailable Number of ranges with fewer live replicas than needed for quorum
+# TYPE ranges_unavailable gauge
+ranges_unavailable{store="1"} 0
+ranges_unavailable{store="2"} 0
+liveness_livenodes 3 1700000000000
+sql_conns{node_id="1",app="my \"app\""} 12
+`
+
+func TestParseExpression(t *testing.T) {
+	e, err := parseExpression(` ranges_unavailable{store="1", node_id="2"} <= 1.5 `)
+	require.NoError(t, err)
+	require.Equal(t, "ranges_unavailable", e.metric)
+	require.Equal(t, map[string]string{"store": "1", "node_id": "2"}, e.labels)
+	require.Equal(t, "<=", e.op)
+	require.Equal(t, 1.5, e.value)
+
+	for _, invalid := range []string{
+		"ranges_unavailable",
+		"ranges_unavailable = 0",
+		"ranges_unavailable == zero",
+		`ranges_unavailable{store=1} == 0`,
+		"rate(ranges_unavailable[5m]) == 0",
+	} {
+		_, err := parseExpression(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestEvaluateExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		errMsg     string
+	}{
+		{expression: "ranges_unavailable == 0"},
+		{expression: `ranges_underreplicated{store="1"} == 0`},
+		{
+			expression: "ranges_underreplicated == 0",
+			errMsg:     `ranges_underreplicated{store="2"} is 3, expected ranges_underreplicated == 0`,
+		},
+		{expression: "liveness_livenodes >= 3"},
+		{
+			expression: "liveness_livenodes > 3",
+			errMsg:     "liveness_livenodes is 3, expected liveness_livenodes > 3",
+		},
+		{expression: `sql_conns{app="my \"app\""} < 100`},
+		{
+			expression: `ranges_underreplicated{store="3"} == 0`,
+			errMsg:     `no samples found for ranges_underreplicated{store="3"} == 0`,
+		},
+		{
+			expression: "ranges == 0",
+			errMsg:     "no samples found for ranges == 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			e, err := parseExpression(tt.expression)
+			require.NoError(t, err)
+
+			err = e.evaluate(strings.NewReader(vars))
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
@@ -17,32 +17,31 @@ limitations under the License.
 package healthchecker
 
 import (
-	"bufio"
 	"context"
 	"crypto/tls"
+	"database/sql"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/kube"
 	"github.com/cockroachdb/cockroach-operator/pkg/resource"
 	"github.com/cockroachdb/cockroach-operator/pkg/scale"
 	"github.com/cockroachdb/errors"
 	"github.com/go-logr/logr"
 	"go.uber.org/zap/zapcore"
+	kubetypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const underreplicatedmetric = "ranges_underreplicated{store="
-
 // HealthChecker interface
 type HealthChecker interface { // for testing
 	Probe(ctx context.Context, l logr.Logger, logSuffix string, partition int) error
@@ -53,19 +52,47 @@ type HealthCheckerImpl struct {
 	clientset kubernetes.Interface
 	cluster   *resource.Cluster
 	config    *rest.Config
+	db        *sql.DB
+	client    client.Client
+	checks    []check
 }
 
 // NewHealthChecker ctor
 func NewHealthChecker(cluster *resource.Cluster, clientset kubernetes.Interface, config *rest.Config) *HealthCheckerImpl {
-	return &HealthCheckerImpl{
+	hc := &HealthCheckerImpl{
 		clientset: clientset,
 		cluster:   cluster,
 		config:    config,
 	}
+	for _, c := range cluster.HealthChecks() {
+		hc.checks = append(hc.checks, hc.newCheck(c))
+	}
+	return hc
+}
+
+// NeedsDatabase returns true if one of the configured checks queries the cluster over SQL.
+func (hc *HealthCheckerImpl) NeedsDatabase() bool {
+	for _, c := range hc.checks {
+		if c.usesDatabase {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDatabase sets the connection used by the NodeLiveness and SQL checks.
+func (hc *HealthCheckerImpl) SetDatabase(db *sql.DB) {
+	hc.db = db
+}
+
+// SetClient sets the client used to save the results of the checks in the cluster status while
+// the rolling operation is in progress. Without client the results are only recorded in memory.
+func (hc *HealthCheckerImpl) SetClient(cl client.Client) {
+	hc.client = cl
 }
 
-// Probe will check the ranges_underreplicated metric  for value 0 on all pods after the restart of a
-// pod, before continue the rolling update of the next pod
+// Probe runs the configured health checks after the restart of a pod, before the rolling
+// operation continues with the next pod. The results are recorded in the cluster status.
 func (hc *HealthCheckerImpl) Probe(ctx context.Context, l logr.Logger, logSuffix string, nodeID int) error {
 	l.V(int(zapcore.DebugLevel)).Info("Health check probe", "label", logSuffix, "nodeID", nodeID)
 	stsname := hc.cluster.StatefulSetName()
@@ -80,47 +107,89 @@ func (hc *HealthCheckerImpl) Probe(ctx context.Context, l logr.Logger, logSuffix
 		return errors.Wrapf(err, "error rolling update stategy on pod %d", nodeID)
 	}
 
-	// we check _status/vars on all cockroachdb pods looking for pairs like
-	// ranges_underreplicated{store="1"} 0 and wait if any are non-zero until all are 0.
-	// We can recheck every 10 seconds. We are waiting for this maximum 3 minutes
-	err = hc.waitUntilUnderReplicatedMetricIsZero(ctx, l, logSuffix, stsname, stsnamespace, *sts.Spec.Replicas)
+	// the checks are retried until they all pass or the timeout is reached
+	err = hc.waitUntilChecksPass(ctx, l, logSuffix, *sts.Spec.Replicas)
 	if err != nil {
 		return err
 	}
 
-	// we will wait 22 seconds and check again  _status/vars on all cockroachdb pods looking for pairs like
-	// ranges_underreplicated{store="1"} 0. This time we do not wait anymore. This suplimentary check
-	// is due to the fact that a node can be evicted in some cases
-	time.Sleep(22 * time.Second)
-	l.V(int(zapcore.DebugLevel)).Info("second wait loop for range_underreplicated metric", "label", logSuffix, "nodeID", nodeID)
-	err = hc.waitUntilUnderReplicatedMetricIsZero(ctx, l, logSuffix, stsname, stsnamespace, *sts.Spec.Replicas)
-	if err != nil {
-		return err
+	// we wait and run the checks again. This suplimentary check is due to the fact that
+	// a node can be evicted in some cases
+	delay := hc.cluster.HealthCheckStabilizationDelay()
+	l.V(int(zapcore.DebugLevel)).Info("waiting before the second health check", "label", logSuffix, "nodeID", nodeID, "delay", delay)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
 	}
-	return nil
+	return hc.waitUntilChecksPass(ctx, l, logSuffix, *sts.Spec.Replicas)
 }
 
-// waitUntilUnderReplicatedMetricIsZero will check _status/vars on all cockroachdb pods looking for pairs like
-// ranges_underreplicated{store="1"} 0 and wait if any are non-zero until all are 0.
-func (hc *HealthCheckerImpl) waitUntilUnderReplicatedMetricIsZero(ctx context.Context, l logr.Logger, logSuffix, stsname, stsnamespace string, replicas int32) error {
+// waitUntilChecksPass retries the checks with an exponential backoff until they all pass.
+func (hc *HealthCheckerImpl) waitUntilChecksPass(ctx context.Context, l logr.Logger, logSuffix string, replicas int32) error {
 	f := func() error {
-		return hc.checkUnderReplicatedMetricAllPods(ctx, l, logSuffix, stsname, stsnamespace, replicas)
+		return hc.runChecks(ctx, l, replicas)
 	}
 	b := backoff.NewExponentialBackOff()
-	b.MaxElapsedTime = 3 * time.Minute
-	b.MaxInterval = 10 * time.Second
-	if err := backoff.Retry(f, b); err != nil {
-		return errors.Wrapf(err, "replicas check probe failed for cluster %s", logSuffix)
+	b.MaxElapsedTime = hc.cluster.HealthCheckTimeout()
+	b.MaxInterval = hc.cluster.HealthCheckInterval()
+	if err := backoff.Retry(f, backoff.WithContext(b, ctx)); err != nil {
+		return errors.Wrapf(err, "health check probe failed for cluster %s", logSuffix)
 	}
 	return nil
 }
 
-// checkUnderReplicatedMetric will make an http get call to _status/vars on a specific pod looking for pairs like
-// ranges_underreplicated{store="1"} 0
-func (hc *HealthCheckerImpl) checkUnderReplicatedMetric(ctx context.Context, l logr.Logger, logSuffix, podname, stsname, stsnamespace string, partition int32) error {
-	l.V(int(zapcore.DebugLevel)).Info("checkUnderReplicatedMetric", "label", logSuffix, "podname", podname, "partition", partition)
+// runChecks runs every check once and records the results in the cluster status. It
+// returns the error of the first check that failed.
+func (hc *HealthCheckerImpl) runChecks(ctx context.Context, l logr.Logger, replicas int32) error {
+	var firstErr error
+	results := make([]api.HealthCheckResult, 0, len(hc.checks))
+	for _, c := range hc.checks {
+		err := c.run(ctx, l, replicas)
+		result := api.HealthCheckResult{
+			Name:          c.name,
+			Passed:        err == nil,
+			LastProbeTime: metav1.Now(),
+		}
+		if err != nil {
+			l.V(int(zapcore.DebugLevel)).Info("health check failed", "check", c.name, "reason", err.Error())
+			result.Message = err.Error()
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, "health check %s failed", c.name)
+			}
+		}
+		results = append(results, result)
+	}
+	hc.cluster.SetHealthCheckResults(results)
+	if err := hc.saveResults(ctx, results); err != nil {
+		l.Error(err, "failed to save the results of the health checks")
+	}
+	return firstErr
+}
+
+// saveResults patches the status of the cluster with the results of the checks, the rolling
+// operation runs for longer than the reconcile that saves the rest of the status.
+func (hc *HealthCheckerImpl) saveResults(ctx context.Context, results []api.HealthCheckResult) error {
+	if hc.client == nil {
+		return nil
+	}
+
+	cr := &api.CrdbCluster{}
+	key := kubetypes.NamespacedName{Namespace: hc.cluster.Namespace(), Name: hc.cluster.Name()}
+	if err := hc.client.Get(ctx, key, cr); err != nil {
+		return errors.Wrap(err, "failed to fetch the cluster")
+	}
+	clean := cr.DeepCopy()
+	cr.Status.HealthChecks = results
+	return errors.Wrap(hc.client.Status().Patch(ctx, cr, client.MergeFrom(clean)), "failed to patch the cluster status")
+}
+
+// get makes an http get call to path on a specific pod
+func (hc *HealthCheckerImpl) get(l logr.Logger, podname, path string) (*http.Response, error) {
+	stsname := hc.cluster.StatefulSetName()
+	stsnamespace := hc.cluster.Namespace()
 	port := strconv.FormatInt(int64(*hc.cluster.Spec().HTTPPort), 10)
-	url := fmt.Sprintf("https://%s.%s.%s:%s/_status/vars", podname, stsname, stsnamespace, port)
+	url := fmt.Sprintf("https://%s.%s.%s:%s%s", podname, stsname, stsnamespace, port, path)
 
 	runningInsideK8s := inK8s("/var/run/secrets/kubernetes.io/serviceaccount/token")
 
@@ -134,7 +203,7 @@ func (hc *HealthCheckerImpl) checkUnderReplicatedMetric(ctx context.Context, l l
 		if err != nil {
 			msg := "creating dialer failed"
 			l.Error(err, msg)
-			return errors.Wrap(err, msg)
+			return nil, errors.Wrap(err, msg)
 		}
 		tr := &http.Transport{
 			Dial: podDialer.Dial,
@@ -148,7 +217,7 @@ func (hc *HealthCheckerImpl) checkUnderReplicatedMetric(ctx context.Context, l l
 		if err != nil {
 			msg := "health check failed, http get failed"
 			l.Error(err, msg)
-			return errors.Wrapf(err, msg)
+			return nil, errors.Wrapf(err, msg)
 		}
 	} else {
 
@@ -159,89 +228,10 @@ func (hc *HealthCheckerImpl) checkUnderReplicatedMetric(ctx context.Context, l l
 		if err != nil {
 			msg := "health check failed, http get failed"
 			l.Error(err, msg)
-			return errors.Wrapf(err, msg)
+			return nil, errors.Wrapf(err, msg)
 		}
 	}
-
-	defer resp.Body.Close()
-	line, err := findLine(resp.Body)
-	if err != nil {
-		msg := "health check failed, error finding line in Body"
-		l.Error(err, msg)
-		return errors.Wrapf(err, msg)
-	}
-
-	if line == "" {
-		msg := "health check failed, failed unable to find metric line in response body"
-		l.Error(err, msg)
-		return errors.Wrapf(err, msg)
-	}
-
-	metric, err := extractMetric(l, line, underreplicatedmetric, partition)
-	l.V(int(zapcore.DebugLevel)).Info("after get ranges_underreplicated metric", "node", podname, "line", line, "metric", metric)
-	return err
-}
-
-// findLine finds the line with the phrase "ranges_underreplicated{" in it
-func findLine(r io.Reader) (string, error) {
-
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		text := scanner.Text()
-		if strings.Contains(text, "ranges_underreplicated{") {
-			return text, nil
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return "", err
-	}
-
-	return "", nil
-}
-
-// checkUnderReplicatedMetric will check _status/vars on all cockroachdb pods looking for pairs like
-// ranges_underreplicated{store="1"} 0
-func (hc *HealthCheckerImpl) checkUnderReplicatedMetricAllPods(ctx context.Context, l logr.Logger, logSuffix, stsname, stsnamespace string, replicas int32) error {
-	l.V(int(zapcore.DebugLevel)).Info("checkUnderReplicatedMetric", "label", logSuffix, "replicas", replicas)
-	for partition := replicas - 1; partition >= 0; partition-- {
-		podName := fmt.Sprintf("%s-%v", stsname, partition)
-		if err := hc.checkUnderReplicatedMetric(ctx, l, logSuffix, podName, stsname, stsnamespace, partition); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// extractMetric gets the value of the ranges_underreplicated metric for the specific store
-func extractMetric(l logr.Logger, output, underepmetric string, partition int32) (int, error) {
-	l.V(int(zapcore.DebugLevel)).Info("extractMetric", "output", output, "underepmetric", underepmetric, "partition", partition)
-	if output == "" {
-		l.V(int(zapcore.DebugLevel)).Info("output is empty")
-		return -1, errors.Errorf("non existing ranges_underreplicated metric for partition %v", partition)
-	}
-	if !strings.HasPrefix(output, underepmetric) {
-		msg := fmt.Sprintf("incorrect format of the output: actual='%s' expected to start with=%s", output, underepmetric)
-		l.V(int(zapcore.DebugLevel)).Info(msg)
-		return -1, errors.New(msg)
-	}
-	out := strings.Split(output, " ")
-	if out != nil && len(out) <= 1 {
-		return -1, errors.Errorf("incorrect format of the output: actual='%s' expected to start with=%s", output, underepmetric)
-	}
-	metric := strings.TrimSuffix(out[1], "\n")
-	//the value of the metric should be 0 to return nil
-	// TODO: bitsize must be either 32 or 64
-	// nolint
-	if i, err := strconv.ParseFloat(metric, 1); err != nil {
-		l.V(int(zapcore.DebugLevel)).Info(err.Error())
-		return -1, err
-	} else if i > 0 {
-		l.V(int(zapcore.DebugLevel)).Info("Metric is greater than 0", "under_replicated", i)
-		return -1, errors.Errorf("under replica is not zero for partition %v", partition)
-	}
-	return 0, nil
+	return resp, nil
 }
 
 // inK8s checks to see if the a file exists
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestRunChecksRecordsResults(t *testing.T) {
+	cluster := resource.NewCluster(&api.CrdbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "crdb", Namespace: "default"},
+		Spec: api.CrdbClusterSpec{
+			HealthChecks: &api.HealthCheckSpec{
+				Checks: []api.HealthCheck{
+					{Type: api.SQLCheck},
+					{Type: api.NodeLivenessCheck},
+					{Type: api.PrometheusExpressionCheck, Expression: "not an expression", Name: "custom"},
+				},
+			},
+		},
+	})
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	mock.ExpectQuery("SELECT n.node_id").WillReturnRows(sqlmock.NewRows([]string{"node_id"}).AddRow(2))
+
+	hc := NewHealthChecker(&cluster, nil, nil)
+	require.True(t, hc.NeedsDatabase())
+	hc.SetDatabase(db)
+
+	err = hc.runChecks(context.Background(), logf.Log, 3)
+	require.EqualError(t, err, "health check NodeLiveness failed: nodes [2] are not live")
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	results := cluster.Status().HealthChecks
+	require.Len(t, results, 3)
+	require.Equal(t, "SQL", results[0].Name)
+	require.True(t, results[0].Passed)
+	require.Equal(t, "NodeLiveness", results[1].Name)
+	require.False(t, results[1].Passed)
+	require.Equal(t, "nodes [2] are not live", results[1].Message)
+	require.Equal(t, "custom", results[2].Name)
+	require.False(t, results[2].Passed)
+	require.Contains(t, results[2].Message, "invalid expression")
+}
+
+func TestRunChecksSavesResults(t *testing.T) {
+	cr := &api.CrdbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "crdb", Namespace: "default"},
+		Spec: api.CrdbClusterSpec{
+			HealthChecks: &api.HealthCheckSpec{Checks: []api.HealthCheck{{Type: api.SQLCheck}}},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(testutil.InitScheme(t)).WithObjects(cr).WithStatusSubresource(cr).Build()
+	cluster := resource.NewCluster(cr)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+
+	hc := NewHealthChecker(&cluster, nil, nil)
+	hc.SetDatabase(db)
+	hc.SetClient(cl)
+	require.NoError(t, hc.runChecks(context.Background(), logf.Log, 3))
+
+	// the results reach the status before the rolling operation is over
+	saved := &api.CrdbCluster{}
+	require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "crdb"}, saved))
+	require.Len(t, saved.Status.HealthChecks, 1)
+	require.Equal(t, "SQL", saved.Status.HealthChecks[0].Name)
+	require.True(t, saved.Status.HealthChecks[0].Passed)
+}
+
+func TestDefaultHealthChecks(t *testing.T) {
+	cluster := resource.NewCluster(&api.CrdbCluster{})
+
+	hc := NewHealthChecker(&cluster, nil, nil)
+	require.False(t, hc.NeedsDatabase())
+	require.Len(t, hc.checks, 1)
+	require.Equal(t, "UnderReplicatedRanges", hc.checks[0].name)
+}
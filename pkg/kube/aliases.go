@@ -24,3 +24,5 @@ import (
 var IsNotFound = apierrors.IsNotFound
 
 var IgnoreNotFound = client.IgnoreNotFound
+
+var IsAlreadyExists = apierrors.IsAlreadyExists
@@ -27,6 +27,23 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	// OpenShiftDistribution is the distribution returned on OpenShift
+	OpenShiftDistribution = "kubernetes-operator-openshift"
+	// UnknownDistribution is the distribution returned when it is not recognized
+	UnknownDistribution = "kubernetes-operator-unknown"
+)
+
+// TelemetryChannel returns the COCKROACH_CHANNEL value of the nodes running on the distribution.
+// OpenShift was reported as an unknown distribution before it was detected, the value is kept so
+// that an upgrade of the operator does not restart the nodes of the running clusters.
+func TelemetryChannel(distribution string) string {
+	if distribution == OpenShiftDistribution {
+		return UnknownDistribution
+	}
+	return distribution
+}
+
 type KubernetesDistribution interface {
 	Get(ctx context.Context, clientset kubernetes.Interface, log logr.Logger) (string, error)
 }
@@ -71,14 +88,14 @@ func (kd kubernetesDistribution) Get(ctx context.Context, clientset kubernetes.I
 		return "kubernetes-operator-eks", nil
 	} else {
 		for key := range node.Annotations {
-			if strings.Contains("openshift", key) {
-				return "kubernetes-operator-openshift", nil
+			if strings.Contains(key, "openshift") {
+				return OpenShiftDistribution, nil
 			}
 		}
 	}
 
 	log.V(int(zapcore.WarnLevel)).Info("found unknown kubernetes distribution")
-	return "kubernetes-operator-unknown", nil
+	return UnknownDistribution, nil
 }
 
 type mockKubernetesDistribution struct{}
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/gosimple/slug"
 	corev1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -43,8 +44,24 @@ const (
 	CrdbRestartAnnotation        = "crdb.io/restart"
 	CrdbCertExpirationAnnotation = "crdb.io/certexpiration"
 	CrdbRestartTypeAnnotation    = "crdb.io/restarttype"
+	CrdbEncryptionKeyAnnotation  = "crdb.io/encryptionkey"
+	CrdbSnapshotAnnotation       = "crdb.io/snapshot"
+	CrdbDataStoreSizeAnnotation  = "crdb.io/datastoresize"
 
 	VersionCheckJobName = "vcheck"
+
+	defaultUpgradeSoakDuration = 10 * time.Minute
+
+	defaultHealthCheckTimeout            = 3 * time.Minute
+	defaultHealthCheckInterval           = 10 * time.Second
+	defaultHealthCheckStabilizationDelay = 22 * time.Second
+
+	defaultCertificateRenewBefore = 30 * 24 * time.Hour
+	defaultClientDatabase         = "defaultdb"
+	defaultBindingRoot            = "/bindings"
+
+	defaultAutoExpandThreshold = 80
+	defaultAutoExpandInterval  = 5 * time.Minute
 )
 
 func NewCluster(original *api.CrdbCluster) Cluster {
@@ -100,6 +117,71 @@ func (cluster Cluster) SetClusterVersion(version string) {
 func (cluster Cluster) SetSQLHost(host string) {
 	cluster.cr.Status.SQLHost = host
 }
+func (cluster Cluster) SetAutoExpandStatus(status *api.AutoExpandStatus) {
+	cluster.cr.Status.AutoExpand = status
+}
+func (cluster Cluster) SetStorageMigrationStatus(status *api.StorageMigrationStatus) {
+	cluster.cr.Status.StorageMigration = status
+}
+
+// SetRetainedPVCsStatus records the PVCs kept after the decommission of their nodes, nil once
+// they are pruned.
+func (cluster Cluster) SetRetainedPVCsStatus(status *api.RetainedPVCsStatus) {
+	cluster.cr.Status.RetainedPVCs = status
+}
+
+// SetCertificatesStatus records the CA and the additional names of the certificates issued.
+func (cluster Cluster) SetCertificatesStatus(status *api.CertificatesStatus) {
+	cluster.cr.Status.Certificates = status
+}
+
+// SetTLSMigrationStatus sets the progress of the migration of the cluster to or from TLS, nil once done.
+func (cluster Cluster) SetTLSMigrationStatus(status *api.TLSMigrationStatus) {
+	cluster.cr.Status.TLSMigration = status
+}
+
+// SetTemplateStatus sets the revision of the template merged into the spec, nil without template.
+func (cluster Cluster) SetTemplateStatus(status *api.TemplateStatus) {
+	cluster.cr.Status.Template = status
+}
+
+// SetTemplateApplied records that the revision of the template merged into the spec is rolled out.
+func (cluster Cluster) SetTemplateApplied() {
+	if template := cluster.cr.Status.Template; template != nil {
+		template.AppliedRevision = template.ObservedRevision
+	}
+}
+
+// TemplateRolloutWait returns how long a change of the template waits for the maintenance window of the
+// cluster, zero if it can be rolled out now. Only the clusters that are already initialized wait, a
+// deleted cluster is cleaned up without waiting.
+func (cluster Cluster) TemplateRolloutWait(now time.Time) time.Duration {
+	ref, template := cluster.cr.Spec.TemplateRef, cluster.cr.Status.Template
+	if ref == nil || cluster.IsBeingDeleted() || ref.MaintenanceWindow == nil || template == nil || template.AppliedRevision == template.ObservedRevision {
+		return 0
+	}
+	if !condition.True(api.CrdbInitializedCondition, cluster.cr.Status.Conditions) {
+		return 0
+	}
+	if open, wait := ref.MaintenanceWindow.Open(now); !open {
+		return wait
+	}
+	return 0
+}
+
+// SetSnapshotStatus records a snapshot in the status, replacing the snapshot with the same name.
+func (cluster Cluster) SetSnapshotStatus(snapshot api.SnapshotStatus) {
+	for i := range cluster.cr.Status.Snapshots {
+		if cluster.cr.Status.Snapshots[i].Name == snapshot.Name {
+			cluster.cr.Status.Snapshots[i] = snapshot
+			return
+		}
+	}
+	cluster.cr.Status.Snapshots = append(cluster.cr.Status.Snapshots, snapshot)
+}
+func (cluster Cluster) SetEncryptionStatus(status *api.EncryptionStatus) {
+	cluster.cr.Status.Encryption = status
+}
 func (cluster Cluster) SetCrdbContainerImage(containerimage string) {
 	cluster.cr.Status.CrdbContainerImage = containerimage
 }
@@ -163,6 +245,39 @@ func (cluster Cluster) PublicServiceName() string {
 	return fmt.Sprintf("%s-public", cluster.Name())
 }
 
+// SQLServiceName is the name of the optional service that only exposes the SQL port.
+func (cluster Cluster) SQLServiceName() string {
+	return fmt.Sprintf("%s-sql", cluster.Name())
+}
+
+// UIServiceName is the name of the optional service that only exposes the HTTP port.
+func (cluster Cluster) UIServiceName() string {
+	return fmt.Sprintf("%s-ui", cluster.Name())
+}
+
+// PublicServiceConfig returns the configuration of the public services, nil if none is given.
+func (cluster Cluster) PublicServiceConfig() *api.PublicServiceConfig {
+	if cluster.Spec().Services == nil {
+		return nil
+	}
+	return cluster.Spec().Services.Public
+}
+
+// SQLLoadBalancerServiceName returns the name of the LoadBalancer service that exposes the
+// SQL port, or an empty string if SQL is not exposed through a load balancer.
+func (cluster Cluster) SQLLoadBalancerServiceName() string {
+	config := cluster.PublicServiceConfig()
+	switch {
+	case config == nil:
+		return ""
+	case config.SQL != nil && config.SQL.Type == corev1.ServiceTypeLoadBalancer:
+		return cluster.SQLServiceName()
+	case config.Type == corev1.ServiceTypeLoadBalancer:
+		return cluster.PublicServiceName()
+	}
+	return ""
+}
+
 // PublicServiceAddress is the FQDN of the public service.
 // E.g. <name>-public.namespace.svc.cluster.local
 func (cluster Cluster) PublicServiceAddress() string {
@@ -224,6 +339,14 @@ func (cluster Cluster) GetAnnotationRestartType() string {
 	return cluster.getAnnotation(CrdbRestartTypeAnnotation)
 }
 
+func (cluster Cluster) GetAnnotationSnapshot() string {
+	return cluster.getAnnotation(CrdbSnapshotAnnotation)
+}
+
+func (cluster Cluster) GetAnnotationCertExpiration() string {
+	return cluster.getAnnotation(CrdbCertExpirationAnnotation)
+}
+
 func (cluster Cluster) GetAnnotationHistory() string {
 	return cluster.getAnnotation(CrdbHistoryAnnotation)
 }
@@ -253,6 +376,12 @@ func (cluster Cluster) SetAnnotationCertExpiration(certExpiration string) {
 	}
 	cluster.cr.Annotations[CrdbCertExpirationAnnotation] = certExpiration
 }
+func (cluster Cluster) SetAnnotationDataStoreSize(size string) {
+	if cluster.cr.Annotations == nil {
+		cluster.cr.Annotations = make(map[string]string)
+	}
+	cluster.cr.Annotations[CrdbDataStoreSizeAnnotation] = size
+}
 func (cluster Cluster) SetRestartTypeAnnotation(restartType string) {
 	if cluster.cr.Annotations == nil {
 		cluster.cr.Annotations = make(map[string]string)
@@ -266,6 +395,30 @@ func (cluster Cluster) DeleteRestartTypeAnnotation() {
 	delete(cluster.cr.Annotations, CrdbRestartTypeAnnotation)
 }
 
+func (cluster Cluster) DeleteSnapshotAnnotation() {
+	if cluster.cr.Annotations == nil {
+		return
+	}
+	delete(cluster.cr.Annotations, CrdbSnapshotAnnotation)
+}
+
+func (cluster Cluster) DeleteTLSMigrationAnnotation() {
+	if cluster.cr.Annotations == nil {
+		return
+	}
+	delete(cluster.cr.Annotations, api.CrdbTLSMigrationAnnotation)
+}
+
+// Snapshot returns the snapshot with the given name recorded in the status, or nil.
+func (cluster Cluster) Snapshot(name string) *api.SnapshotStatus {
+	for _, snapshot := range cluster.Status().Snapshots {
+		if snapshot.Name == name {
+			return &snapshot
+		}
+	}
+	return nil
+}
+
 func (cluster Cluster) GetCockroachDBImageName() string {
 	supportedImages := getSupportedCrdbImages()
 	if cluster.Spec().CockroachDBVersion != "" {
@@ -316,9 +469,85 @@ func (cluster Cluster) ClientTLSSecretName() string {
 	return fmt.Sprintf("%s-root", cluster.Name())
 }
 func (cluster Cluster) CASecretName() string {
+	if cluster.IsCAProvided() {
+		return cluster.Spec().Certificates.CASecret
+	}
+
 	return fmt.Sprintf("%s-ca", cluster.Name())
 }
 
+// IsCAProvided returns true if the node and client certificates are issued from a CA provided in a Secret.
+func (cluster Cluster) IsCAProvided() bool {
+	return cluster.Spec().Certificates != nil && cluster.Spec().Certificates.CASecret != ""
+}
+
+// CertificatesStatus returns the CA and the additional names of the certificates to issue, nil
+// for the CA generated by the operator without additional names.
+func (cluster Cluster) CertificatesStatus() *api.CertificatesStatus {
+	sans, clients := cluster.CertificateSANs(), cluster.ClientCertificates()
+	if !cluster.IsCAProvided() && len(sans) == 0 && len(clients) == 0 {
+		return nil
+	}
+	status := &api.CertificatesStatus{SANs: sans, Clients: clients}
+	if cluster.IsCAProvided() {
+		status.CASecret = cluster.CASecretName()
+	}
+	return status
+}
+
+// CertificateSANs returns the additional DNS names and IP addresses of the node certificate set in
+// spec.certificates, with the host of the DB Console ingress or route.
+func (cluster Cluster) CertificateSANs() []string {
+	config := cluster.Spec().Certificates
+	if config == nil {
+		return nil
+	}
+
+	var sans []string
+	if cluster.IsUIIngressEnabled() && cluster.Spec().Ingress.UI.Host != "" {
+		sans = append(sans, cluster.Spec().Ingress.UI.Host)
+	} else if cluster.IsUIRouteEnabled() && cluster.Spec().Gateway.UI.Host != "" {
+		sans = append(sans, cluster.Spec().Gateway.UI.Host)
+	}
+	sans = append(sans, config.DNSNames...)
+	return append(sans, config.IPAddresses...)
+}
+
+// ClientCertificates returns the client certificates to issue for SQL users, with their defaults.
+func (cluster Cluster) ClientCertificates() []api.ClientCertificate {
+	config := cluster.Spec().Certificates
+	if config == nil {
+		return nil
+	}
+
+	var clients []api.ClientCertificate
+	for _, c := range config.Clients {
+		if c.Namespace == "" {
+			c.Namespace = cluster.Namespace()
+		}
+		if c.SecretName == "" {
+			c.SecretName = fmt.Sprintf("%s-client-%s", cluster.Name(), c.User)
+		}
+		if c.Database == "" {
+			c.Database = defaultClientDatabase
+		}
+		if c.BindingRoot == "" {
+			c.BindingRoot = defaultBindingRoot
+		}
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// CertificateRenewBefore returns how long before their expiration the certificates issued by the
+// operator are reissued.
+func (cluster Cluster) CertificateRenewBefore() time.Duration {
+	if config := cluster.Spec().Certificates; config != nil && config.RenewBefore != nil {
+		return config.RenewBefore.Duration
+	}
+	return defaultCertificateRenewBefore
+}
+
 func (cluster Cluster) Domain() string {
 	return "svc.cluster.local"
 }
@@ -338,6 +567,275 @@ func (cluster Cluster) GetTerminationGracePeriod() int64 {
 	return cluster.Spec().TerminationGracePeriodSecs
 }
 
+// UpgradeCanaries returns the number of pods that are upgraded before the upgrade soaks.
+func (cluster Cluster) UpgradeCanaries() int32 {
+	if cluster.Spec().UpgradeStrategy == nil {
+		return 0
+	}
+	return cluster.Spec().UpgradeStrategy.Canaries
+}
+
+// UpgradeSoakDuration returns how long the canaries are watched before the upgrade continues.
+func (cluster Cluster) UpgradeSoakDuration() time.Duration {
+	strategy := cluster.Spec().UpgradeStrategy
+	if strategy == nil || strategy.SoakDuration == nil {
+		return defaultUpgradeSoakDuration
+	}
+	return strategy.SoakDuration.Duration
+}
+
+// SetUpgradeSoakStartTime records when the canaries of the upgrade started soaking, nil once
+// the soak is over.
+func (cluster Cluster) SetUpgradeSoakStartTime(start *metav1.Time) {
+	cluster.cr.Status.UpgradeSoakStartTime = start
+}
+
+// UpgradeFinalizeMode returns who finalizes a major upgrade, the user unless configured otherwise.
+func (cluster Cluster) UpgradeFinalizeMode() api.UpgradeFinalizeMode {
+	upgrade := cluster.Spec().Upgrade
+	if upgrade == nil || upgrade.Finalize == "" {
+		return api.UpgradeFinalizeManual
+	}
+	return upgrade.Finalize
+}
+
+// UpgradeAutoFinalizeDelay returns how long a completed major upgrade waits before the
+// operator finalizes it.
+func (cluster Cluster) UpgradeAutoFinalizeDelay() time.Duration {
+	upgrade := cluster.Spec().Upgrade
+	if upgrade == nil || upgrade.AutoFinalizeDelay == nil {
+		return 0
+	}
+	return upgrade.AutoFinalizeDelay.Duration
+}
+
+// UpgradeAutoRollback returns true if a failed upgrade is rolled back to the previous image.
+func (cluster Cluster) UpgradeAutoRollback() bool {
+	upgrade := cluster.Spec().Upgrade
+	return upgrade != nil && upgrade.AutoRollback
+}
+
+// SetUpgradeFinalization records the version preserved by the cluster.preserve_downgrade_option
+// cluster setting. An empty version means that the last major upgrade is finalized.
+func (cluster Cluster) SetUpgradeFinalization(preserveDowngradeVersion string) {
+	cluster.cr.Status.PreserveDowngradeVersion = preserveDowngradeVersion
+	cluster.cr.Status.UpgradeFinalizationCheckTime = nil
+	// The auto-finalize delay starts when the upgrade completes and not when the
+	// reconciliation that ran the upgrade started.
+	if preserveDowngradeVersion == "" {
+		condition.SetTrue(api.UpgradeFinalizedCondition, &cluster.cr.Status, metav1.Now())
+	} else {
+		condition.SetFalse(api.UpgradeFinalizedCondition, &cluster.cr.Status, metav1.Now())
+	}
+}
+
+// SetUpgradeFinalizationCheckTime records when the operator checks again whether the user finalized
+// the last major upgrade.
+func (cluster Cluster) SetUpgradeFinalizationCheckTime(at time.Time) {
+	cluster.cr.Status.UpgradeFinalizationCheckTime = &metav1.Time{Time: at}
+}
+
+// UpgradeFinalizationWait returns how long the finalization of the last major upgrade waits, for the
+// auto-finalize delay or for the next check of a manual finalization. It is zero when the finalization
+// is due or when there is no upgrade to finalize.
+func (cluster Cluster) UpgradeFinalizationWait(now time.Time) time.Duration {
+	if !condition.False(api.UpgradeFinalizedCondition, cluster.cr.Status.Conditions) {
+		return 0
+	}
+
+	due := cluster.cr.Status.UpgradeFinalizationCheckTime
+	if cluster.UpgradeFinalizeMode() == api.UpgradeFinalizeAuto {
+		due = &metav1.Time{Time: cluster.UpgradeCompletionTime().Add(cluster.UpgradeAutoFinalizeDelay())}
+	}
+	if due == nil || !now.Before(due.Time) {
+		return 0
+	}
+	return due.Sub(now)
+}
+
+// UpgradeCompletionTime returns when the last major upgrade that is not finalized completed.
+func (cluster Cluster) UpgradeCompletionTime() metav1.Time {
+	for _, cond := range cluster.cr.Status.Conditions {
+		if cond.Type == api.UpgradeFinalizedCondition {
+			return cond.LastTransitionTime
+		}
+	}
+	return metav1.Time{}
+}
+
+func (cluster Cluster) SetRolledBackVersion(version string) {
+	cluster.cr.Status.RolledBackVersion = version
+}
+
+// UpgradeDryRun returns true if version changes are only previewed.
+func (cluster Cluster) UpgradeDryRun() bool {
+	upgrade := cluster.Spec().Upgrade
+	return upgrade != nil && upgrade.DryRun
+}
+
+func (cluster Cluster) SetUpgradePreview(preview *api.UpgradePreview) {
+	cluster.cr.Status.UpgradePreview = preview
+}
+
+// HoldVersion makes the in-memory cluster keep the version and image deployed while a dry run
+// previews a version change, so that the statefulset is built without the requested version.
+// The change is not saved, the actors only persist the status of the cluster.
+func (cluster Cluster) HoldVersion(version, image string) {
+	cluster.cr.Spec.CockroachDBVersion = ""
+	if cluster.cr.Spec.Image == nil {
+		cluster.cr.Spec.Image = &api.PodImage{}
+	}
+	cluster.cr.Spec.Image.Name = image
+	cluster.SetAnnotationVersion(version)
+	cluster.SetAnnotationContainerImage(image)
+}
+
+// HealthChecks returns the checks that run between the pods of rolling updates and restarts,
+// the under-replicated ranges check unless configured otherwise.
+func (cluster Cluster) HealthChecks() []api.HealthCheck {
+	spec := cluster.Spec().HealthChecks
+	if spec == nil || len(spec.Checks) == 0 {
+		return []api.HealthCheck{{Type: api.UnderReplicatedRangesCheck}}
+	}
+	return spec.Checks
+}
+
+// HealthCheckTimeout returns how long the health checks are retried before a rolling operation fails.
+func (cluster Cluster) HealthCheckTimeout() time.Duration {
+	spec := cluster.Spec().HealthChecks
+	if spec == nil || spec.Timeout == nil {
+		return defaultHealthCheckTimeout
+	}
+	return spec.Timeout.Duration
+}
+
+// HealthCheckInterval returns the longest wait between two attempts of a failing health check.
+func (cluster Cluster) HealthCheckInterval() time.Duration {
+	spec := cluster.Spec().HealthChecks
+	if spec == nil || spec.Interval == nil {
+		return defaultHealthCheckInterval
+	}
+	return spec.Interval.Duration
+}
+
+// AutoExpandThreshold returns the percentage of the capacity of the store used by a node
+// before the data store is expanded.
+func (cluster Cluster) AutoExpandThreshold() int32 {
+	config := cluster.Spec().DataStore.AutoExpand
+	if config == nil || config.Threshold == 0 {
+		return defaultAutoExpandThreshold
+	}
+	return config.Threshold
+}
+
+// AutoExpandInterval returns the time between two checks of the capacity of the stores.
+func (cluster Cluster) AutoExpandInterval() time.Duration {
+	config := cluster.Spec().DataStore.AutoExpand
+	if config == nil || config.Interval == nil {
+		return defaultAutoExpandInterval
+	}
+	return config.Interval.Duration
+}
+
+// DataStoreSize returns the size of the persistent volumes of the data store: the requested
+// storage, or the size they were expanded to if it is larger.
+func (cluster Cluster) DataStoreSize() apiresource.Quantity {
+	var size apiresource.Quantity
+	if claim := cluster.Spec().DataStore.VolumeClaim; claim != nil {
+		size = *claim.PersistentVolumeClaimSpec.Resources.Requests.Storage()
+	}
+	if status := cluster.Status().AutoExpand; status != nil && status.Size != nil && status.Size.Cmp(size) > 0 {
+		size = *status.Size
+	}
+	// the annotation keeps the expanded size when the status is lost
+	if expanded, err := apiresource.ParseQuantity(cluster.getAnnotation(CrdbDataStoreSizeAnnotation)); err == nil && expanded.Cmp(size) > 0 {
+		size = expanded
+	}
+	return size
+}
+
+// StorageClassName returns the storage class of the persistent volumes of the data store, empty
+// for the default storage class.
+func (cluster Cluster) StorageClassName() string {
+	claim := cluster.Spec().DataStore.VolumeClaim
+	if claim == nil || claim.PersistentVolumeClaimSpec.StorageClassName == nil {
+		return ""
+	}
+	return *claim.PersistentVolumeClaimSpec.StorageClassName
+}
+
+// RetentionPolicy returns what happens to the persistent volumes of the nodes when the cluster is
+// deleted, Retain by default.
+func (cluster Cluster) RetentionPolicy() api.RetentionPolicy {
+	if policy := cluster.Spec().DataStore.RetentionPolicy; policy != "" {
+		return policy
+	}
+	return api.RetainPolicy
+}
+
+// NeedsFinalizer returns true if the deletion of the cluster is held by the operator finalizer, to
+// delete the volumes of the nodes with the Delete retention policy, or the Secrets of the client
+// certificates, which have no owner reference in the namespaces of the applications.
+func (cluster Cluster) NeedsFinalizer() bool {
+	if cluster.RetentionPolicy() == api.DeletePolicy || len(cluster.ClientCertificates()) > 0 {
+		return true
+	}
+	status := cluster.Status().Certificates
+	return status != nil && len(status.Clients) > 0
+}
+
+// IsBeingDeleted returns true once the cluster has been deleted and waits for its finalizers.
+func (cluster Cluster) IsBeingDeleted() bool {
+	return !cluster.cr.DeletionTimestamp.IsZero()
+}
+
+// HasFinalizer returns true if the operator registered its finalizer on the cluster.
+func (cluster Cluster) HasFinalizer() bool {
+	for _, finalizer := range cluster.cr.Finalizers {
+		if finalizer == api.CrdbClusterFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// HealthCheckStabilizationDelay returns how long to wait before the health checks run a second time.
+func (cluster Cluster) HealthCheckStabilizationDelay() time.Duration {
+	spec := cluster.Spec().HealthChecks
+	if spec == nil || spec.StabilizationDelay == nil {
+		return defaultHealthCheckStabilizationDelay
+	}
+	return spec.StabilizationDelay.Duration
+}
+
+// SetHealthCheckResults records the results of the last attempt of the health checks.
+func (cluster Cluster) SetHealthCheckResults(results []api.HealthCheckResult) {
+	cluster.cr.Status.HealthChecks = results
+}
+
+// UpgradeMultiHop returns true if the operator plans intermediate versions to reach the requested version.
+func (cluster Cluster) UpgradeMultiHop() bool {
+	upgrade := cluster.Spec().Upgrade
+	return upgrade != nil && upgrade.MultiHop
+}
+
+// SetUpgradeOrigin records the version and image the cluster ran when the upgrade started,
+// empty values clear them once the upgrade is over.
+func (cluster Cluster) SetUpgradeOrigin(version, image string) {
+	cluster.cr.Status.UpgradeFromVersion = version
+	cluster.cr.Status.UpgradeFromImage = image
+}
+
+// SetUpgradePlan records the versions of a multi-hop upgrade, the first one being rolled
+// out now. An empty plan clears the status once the requested version is reached.
+func (cluster Cluster) SetUpgradePlan(plan []string) {
+	cluster.cr.Status.UpgradePlan = plan
+	cluster.cr.Status.UpgradeHop = ""
+	if len(plan) > 0 {
+		cluster.cr.Status.UpgradeHop = plan[0]
+	}
+}
+
 func (cluster Cluster) LoggingConfiguration(fetcher Fetcher) (string, error) {
 	if cluster.Spec().LogConfigMap != "" {
 		cm := &corev1.ConfigMap{
@@ -396,6 +894,18 @@ func getSupportedCrdbImages() map[string]string {
 	}
 	return crdbSupportedImages
 }
+
+// SupportedCrdbVersions returns the CockroachDB versions the operator has images for.
+func SupportedCrdbVersions() []string {
+	return getSupportedCrdbVersions()
+}
+
+// SupportedCrdbImage returns the image of a supported CockroachDB version, or an empty
+// string if the version is not supported.
+func SupportedCrdbImage(version string) string {
+	return getSupportedCrdbImages()[version]
+}
+
 func getSupportedCrdbVersions() []string {
 	supportedVersions := make([]string, 0)
 	for _, e := range os.Environ() {
@@ -423,3 +933,73 @@ func (cluster Cluster) IsUIIngressEnabled() bool {
 func (cluster Cluster) IsSQLIngressEnabled() bool {
 	return cluster.Spec().Ingress != nil && cluster.Spec().Ingress.SQL != nil
 }
+
+// IsNetworkPolicyEnabled returns true if networkPolicy config is given in spec
+func (cluster Cluster) IsNetworkPolicyEnabled() bool {
+	return cluster.Spec().NetworkPolicy != nil
+}
+
+// NetworkPolicyName returns the name of the NetworkPolicy generated for the cluster
+func (cluster Cluster) NetworkPolicyName() string {
+	return cluster.Name()
+}
+
+// IsClone returns true if the volumes of the cluster are restored from a snapshot of another cluster
+func (cluster Cluster) IsClone() bool {
+	return cluster.Spec().DataStore.DataSource != nil
+}
+
+// CloneNetworkPolicyName returns the name of the NetworkPolicy that isolates a clone from the
+// cluster it was restored from
+func (cluster Cluster) CloneNetworkPolicyName() string {
+	return cluster.Name() + "-clone-isolation"
+}
+
+// IsEncryptionEnabled returns true if encryption config is given in spec
+func (cluster Cluster) IsEncryptionEnabled() bool {
+	return cluster.Spec().Encryption != nil
+}
+
+// EncryptionKeyReference returns the Secret and the key of the active store key as <secret>/<key>,
+// or an empty string if the store is not encrypted
+func (cluster Cluster) EncryptionKeyReference() string {
+	if !cluster.IsEncryptionEnabled() {
+		return ""
+	}
+	return cluster.Spec().Encryption.SecretName + "/" + cluster.Spec().Encryption.ActiveKey
+}
+
+// IsGatewayNeeded returns true if gateway config is given in spec
+func (cluster Cluster) IsGatewayNeeded() bool {
+	return cluster.Spec().Gateway != nil
+}
+
+// IsUIRouteEnabled returns true if a gateway route is configured for UI
+func (cluster Cluster) IsUIRouteEnabled() bool {
+	return cluster.Spec().Gateway != nil && cluster.Spec().Gateway.UI != nil
+}
+
+// IsSQLRouteEnabled returns true if a gateway route is configured for SQL
+func (cluster Cluster) IsSQLRouteEnabled() bool {
+	return cluster.Spec().Gateway != nil && cluster.Spec().Gateway.SQL != nil
+}
+
+// SQLRouteKind returns the kind of the gateway route used for SQL
+func (cluster Cluster) SQLRouteKind() api.GatewaySQLRouteKind {
+	if !cluster.IsSQLRouteEnabled() || cluster.Spec().Gateway.SQL.Kind == "" {
+		return api.TLSRoute
+	}
+	return cluster.Spec().Gateway.SQL.Kind
+}
+
+// SQLExternalHost returns the host SQL clients use through an ingress or a TLSRoute,
+// which the node certificate has to be valid for.
+func (cluster Cluster) SQLExternalHost() string {
+	if cluster.IsSQLIngressEnabled() {
+		return cluster.Spec().Ingress.SQL.Host
+	}
+	if cluster.IsSQLRouteEnabled() && cluster.SQLRouteKind() == api.TLSRoute {
+		return cluster.Spec().Gateway.SQL.Host
+	}
+	return ""
+}
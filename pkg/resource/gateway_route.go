@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"errors"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const gatewayGroup = "gateway.networking.k8s.io"
+
+var (
+	// HTTPRouteGVK is the Gateway API kind used to expose the DB Console
+	HTTPRouteGVK = schema.GroupVersionKind{Group: gatewayGroup, Version: "v1", Kind: "HTTPRoute"}
+	// TLSRouteGVK is the Gateway API kind used to expose SQL with TLS passthrough
+	TLSRouteGVK = schema.GroupVersionKind{Group: gatewayGroup, Version: "v1alpha2", Kind: "TLSRoute"}
+	// TCPRouteGVK is the Gateway API kind used to expose SQL on a dedicated listener
+	TCPRouteGVK = schema.GroupVersionKind{Group: gatewayGroup, Version: "v1alpha2", Kind: "TCPRoute"}
+)
+
+// UIRouteBuilder models the HTTPRoute that the operator maintains for the DB Console.
+type UIRouteBuilder struct {
+	*Cluster
+	Labels map[string]string
+}
+
+func (b UIRouteBuilder) ResourceName() string {
+	return "ui-" + b.IngressSuffix()
+}
+
+func (b UIRouteBuilder) Build(obj client.Object) error {
+	route, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return errors.New("failed to cast to HTTPRoute object")
+	}
+
+	config := b.Spec().Gateway
+	if config == nil || config.UI == nil {
+		return errors.New("gateway UI route config not found")
+	}
+
+	spec := routeSpec(config.UI, b.PublicServiceName(), *b.Spec().HTTPPort)
+	rules := spec["rules"].([]interface{})
+	rules[0].(map[string]interface{})["matches"] = []interface{}{
+		map[string]interface{}{
+			"path": map[string]interface{}{"type": "PathPrefix", "value": "/"},
+		},
+	}
+
+	buildRoute(route, b.ResourceName(), HTTPRouteGVK, b.Labels, b.Spec().AdditionalAnnotations, config.UI.Annotations, spec)
+	return nil
+}
+
+func (b UIRouteBuilder) Placeholder() client.Object {
+	return routePlaceholder(b.ResourceName(), HTTPRouteGVK)
+}
+
+// SQLRouteBuilder models the TLSRoute or TCPRoute that the operator maintains for SQL.
+type SQLRouteBuilder struct {
+	*Cluster
+	Labels map[string]string
+	// Kind overrides the kind of route configured for the cluster
+	Kind api.GatewaySQLRouteKind
+}
+
+func (b SQLRouteBuilder) ResourceName() string {
+	return "sql-" + b.IngressSuffix()
+}
+
+// GVK returns the kind of route configured for SQL
+func (b SQLRouteBuilder) GVK() schema.GroupVersionKind {
+	kind := b.Kind
+	if kind == "" {
+		kind = b.SQLRouteKind()
+	}
+	if kind == api.TCPRoute {
+		return TCPRouteGVK
+	}
+	return TLSRouteGVK
+}
+
+func (b SQLRouteBuilder) Build(obj client.Object) error {
+	route, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return errors.New("failed to cast to route object")
+	}
+
+	config := b.Spec().Gateway
+	if config == nil || config.SQL == nil {
+		return errors.New("gateway SQL route config not found")
+	}
+
+	spec := routeSpec(&config.SQL.GatewayRoute, b.PublicServiceName(), *b.Spec().SQLPort)
+	if b.GVK() == TCPRouteGVK {
+		delete(spec, "hostnames")
+	}
+
+	buildRoute(route, b.ResourceName(), b.GVK(), b.Labels, b.Spec().AdditionalAnnotations, config.SQL.Annotations, spec)
+	return nil
+}
+
+func (b SQLRouteBuilder) Placeholder() client.Object {
+	return routePlaceholder(b.ResourceName(), b.GVK())
+}
+
+func routePlaceholder(name string, gvk schema.GroupVersionKind) client.Object {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(gvk)
+	route.SetName(name)
+	return route
+}
+
+func buildRoute(route *unstructured.Unstructured, name string, gvk schema.GroupVersionKind,
+	labels, annotations, routeAnnotations map[string]string, spec map[string]interface{}) {
+	route.SetGroupVersionKind(gvk)
+	if route.GetName() == "" {
+		route.SetName(name)
+	}
+	route.SetLabels(labels)
+
+	var merged map[string]string
+	if len(annotations)+len(routeAnnotations) > 0 {
+		merged = map[string]string{}
+		kube.MergeAnnotations(merged, annotations)
+		kube.MergeAnnotations(merged, routeAnnotations)
+	}
+	route.SetAnnotations(merged)
+
+	route.Object["spec"] = spec
+}
+
+// routeSpec returns the spec shared by the route kinds, with the values defaulted by
+// the Gateway API so that the generated routes do not differ from the stored ones.
+func routeSpec(config *api.GatewayRoute, service string, port int32) map[string]interface{} {
+	parentRef := map[string]interface{}{
+		"group": gatewayGroup,
+		"kind":  "Gateway",
+		"name":  config.ParentRef.Name,
+	}
+	if config.ParentRef.Namespace != "" {
+		parentRef["namespace"] = config.ParentRef.Namespace
+	}
+	if config.ParentRef.SectionName != "" {
+		parentRef["sectionName"] = config.ParentRef.SectionName
+	}
+	if config.ParentRef.Port != nil {
+		parentRef["port"] = int64(*config.ParentRef.Port)
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"group":  "",
+						"kind":   "Service",
+						"name":   service,
+						"port":   int64(port),
+						"weight": int64(1),
+					},
+				},
+			},
+		},
+	}
+	if config.Host != "" {
+		spec["hostnames"] = []interface{}{config.Host}
+	}
+	return spec
+}
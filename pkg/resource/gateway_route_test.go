@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"testing"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/labels"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestUIRouteBuilder(t *testing.T) {
+	port := int32(443)
+	cluster := testutil.NewBuilder("test-cluster").Namespaced("test-ns").
+		WithAnnotations(map[string]string{"key": "test-route"}).
+		WithGateway(&api.GatewayConfig{UI: &api.GatewayRoute{
+			ParentRef:   api.GatewayParentReference{Name: "gateway", Namespace: "infra", SectionName: "https", Port: &port},
+			Host:        "ui.test.com",
+			Annotations: map[string]string{"route": "ui"},
+		}})
+	selector := labels.Common(cluster.Cr()).Selector(nil)
+
+	builder := resource.UIRouteBuilder{Cluster: cluster.Cluster(), Labels: selector}
+	actual := builder.Placeholder().(*unstructured.Unstructured)
+	require.NoError(t, builder.Build(actual))
+
+	expected := `apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  annotations:
+    key: test-route
+    route: ui
+  labels:
+    app.kubernetes.io/component: database
+    app.kubernetes.io/instance: test-cluster
+    app.kubernetes.io/name: cockroachdb
+  name: ui-test-cluster
+spec:
+  hostnames:
+  - ui.test.com
+  parentRefs:
+  - group: gateway.networking.k8s.io
+    kind: Gateway
+    name: gateway
+    namespace: infra
+    port: 443
+    sectionName: https
+  rules:
+  - backendRefs:
+    - group: ""
+      kind: Service
+      name: test-cluster-public
+      port: 8080
+      weight: 1
+    matches:
+    - path:
+        type: PathPrefix
+        value: /
+`
+	requireYAML(t, expected, actual)
+}
+
+func TestSQLRouteBuilder(t *testing.T) {
+	route := api.GatewayRoute{ParentRef: api.GatewayParentReference{Name: "gateway"}, Host: "sql.test.com"}
+	cluster := testutil.NewBuilder("test-cluster").Namespaced("test-ns").
+		WithGateway(&api.GatewayConfig{SQL: &api.GatewaySQLRoute{GatewayRoute: route}})
+
+	builder := resource.SQLRouteBuilder{Cluster: cluster.Cluster()}
+	actual := builder.Placeholder().(*unstructured.Unstructured)
+	require.NoError(t, builder.Build(actual))
+
+	expected := `apiVersion: gateway.networking.k8s.io/v1alpha2
+kind: TLSRoute
+metadata:
+  name: sql-test-cluster
+spec:
+  hostnames:
+  - sql.test.com
+  parentRefs:
+  - group: gateway.networking.k8s.io
+    kind: Gateway
+    name: gateway
+  rules:
+  - backendRefs:
+    - group: ""
+      kind: Service
+      name: test-cluster-public
+      port: 26257
+      weight: 1
+`
+	requireYAML(t, expected, actual)
+	require.Equal(t, "sql.test.com", cluster.Cluster().SQLExternalHost())
+
+	// a TCPRoute matches all the connections of the listener
+	cluster = cluster.WithGateway(&api.GatewayConfig{SQL: &api.GatewaySQLRoute{GatewayRoute: route, Kind: api.TCPRoute}})
+	builder = resource.SQLRouteBuilder{Cluster: cluster.Cluster()}
+	actual = builder.Placeholder().(*unstructured.Unstructured)
+	require.NoError(t, builder.Build(actual))
+	require.Equal(t, "TCPRoute", actual.GetKind())
+	_, found, err := unstructured.NestedSlice(actual.Object, "spec", "hostnames")
+	require.NoError(t, err)
+	require.False(t, found)
+	require.Empty(t, cluster.Cluster().SQLExternalHost())
+}
+
+func requireYAML(t *testing.T, expected string, actual *unstructured.Unstructured) {
+	t.Helper()
+	out, err := yaml.Marshal(actual.Object)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(out))
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"errors"
+
+	"github.com/cockroachdb/cockroach-operator/pkg/labels"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// operatorPodLabels are the labels of the operator pods in the install manifests
+var operatorPodLabels = map[string]string{"app": "cockroach-operator"}
+
+// namespaceNameLabel is the label the API server sets on every namespace with its name
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// NetworkPolicyBuilder models the NetworkPolicy that the operator maintains
+// when spec.networkPolicy is set.
+type NetworkPolicyBuilder struct {
+	*Cluster
+
+	Selector map[string]string
+}
+
+func (b NetworkPolicyBuilder) ResourceName() string {
+	return b.NetworkPolicyName()
+}
+
+func (b NetworkPolicyBuilder) Build(obj client.Object) error {
+	policy, ok := obj.(*networkingv1.NetworkPolicy)
+	if !ok {
+		return errors.New("failed to cast to NetworkPolicy object")
+	}
+
+	config := b.Spec().NetworkPolicy
+	if config == nil {
+		return errors.New("networkPolicy config not found")
+	}
+
+	if policy.ObjectMeta.Name == "" {
+		policy.ObjectMeta.Name = b.ResourceName()
+	}
+
+	if policy.ObjectMeta.Labels == nil {
+		policy.ObjectMeta.Labels = map[string]string{}
+	}
+
+	policy.Annotations = b.Spec().AdditionalAnnotations
+
+	grpc := b.policyPorts(*b.Spec().GRPCPort)
+	sql := b.policyPorts(*b.Spec().SQLPort)
+	http := b.policyPorts(*b.Spec().HTTPPort)
+	sqlAndHTTP := b.policyPorts(*b.Spec().SQLPort, *b.Spec().HTTPPort)
+
+	operator := config.Operator
+	if len(operator) == 0 {
+		operator = []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{},
+			PodSelector:       &metav1.LabelSelector{MatchLabels: operatorPodLabels},
+		}}
+	}
+
+	rules := []networkingv1.NetworkPolicyIngressRule{
+		// the nodes of the cluster
+		{
+			From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: b.Selector}}},
+			Ports: grpc,
+		},
+		// the operator connects to SQL (pkg/database) and HTTP (pkg/healthchecker)
+		{
+			From:  operator,
+			Ports: sqlAndHTTP,
+		},
+	}
+
+	if len(config.SQLClients) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{From: config.SQLClients, Ports: sql})
+	}
+	if len(config.HTTPClients) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{From: config.HTTPClients, Ports: http})
+	}
+
+	var exposed []networkingv1.NetworkPolicyPort
+	if b.IsSQLIngressEnabled() || b.IsSQLRouteEnabled() {
+		exposed = append(exposed, sql...)
+	}
+	if b.IsUIIngressEnabled() || b.IsUIRouteEnabled() {
+		exposed = append(exposed, http...)
+	}
+	if len(exposed) > 0 && len(config.IngressControllers) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{From: config.IngressControllers, Ports: exposed})
+	}
+
+	policy.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: b.Selector},
+		Ingress:     rules,
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	}
+
+	return nil
+}
+
+func (b NetworkPolicyBuilder) Placeholder() client.Object {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: b.ResourceName(),
+		},
+	}
+}
+
+func (b NetworkPolicyBuilder) policyPorts(ports ...int32) []networkingv1.NetworkPolicyPort {
+	var policyPorts []networkingv1.NetworkPolicyPort
+	for _, port := range ports {
+		protocol := corev1.ProtocolTCP
+		p := intstr.FromInt32(port)
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p})
+	}
+	return policyPorts
+}
+
+// CloneNetworkPolicyBuilder models the NetworkPolicy that isolates the nodes of a clone from the
+// nodes of the cluster its volumes were restored from, when spec.dataStore.dataSource is set. The
+// join list of the clone only names its own pods, but the restored stores keep the addresses of
+// the source nodes. The policy only admits the traffic from and to the pods of the cluster that
+// are not nodes of the source cluster, the traffic from and to outside the cluster is denied.
+type CloneNetworkPolicyBuilder struct {
+	*Cluster
+
+	Selector map[string]string
+}
+
+func (b CloneNetworkPolicyBuilder) ResourceName() string {
+	return b.CloneNetworkPolicyName()
+}
+
+func (b CloneNetworkPolicyBuilder) Build(obj client.Object) error {
+	policy, ok := obj.(*networkingv1.NetworkPolicy)
+	if !ok {
+		return errors.New("failed to cast to NetworkPolicy object")
+	}
+
+	source := b.Spec().DataStore.DataSource
+	if source == nil {
+		return errors.New("dataStore.dataSource not found")
+	}
+
+	if policy.ObjectMeta.Name == "" {
+		policy.ObjectMeta.Name = b.ResourceName()
+	}
+
+	if policy.ObjectMeta.Labels == nil {
+		policy.ObjectMeta.Labels = map[string]string{}
+	}
+
+	policy.Annotations = b.Spec().AdditionalAnnotations
+
+	peers := []networkingv1.NetworkPolicyPeer{
+		// the pods of the namespace, except the nodes of the source cluster
+		{
+			PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      labels.InstanceKey,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{source.Cluster},
+			}}},
+		},
+		// the pods of the other namespaces
+		{
+			NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      namespaceNameLabel,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{b.Namespace()},
+			}}},
+		},
+	}
+
+	policy.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: b.Selector},
+		Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+		Egress:      []networkingv1.NetworkPolicyEgressRule{{To: peers}},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+	}
+
+	return nil
+}
+
+func (b CloneNetworkPolicyBuilder) Placeholder() client.Object {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: b.ResourceName(),
+		},
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"fmt"
+	"testing"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/labels"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestNetworkPolicyBuilder(t *testing.T) {
+	apps := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "apps"}},
+	}}
+	monitoring := []networkingv1.NetworkPolicyPeer{{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus"}},
+	}}
+	ingressControllers := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"}},
+	}}
+
+	cluster := testutil.NewBuilder("test-cluster").Namespaced("test-ns")
+	selector := labels.Common(cluster.Cr()).Selector(nil)
+	operator := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{},
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cockroach-operator"}},
+	}}
+	grpc, sql, http := ports(26258), ports(26257), ports(8080)
+
+	tests := []struct {
+		name     string
+		cluster  *resource.Cluster
+		expected []networkingv1.NetworkPolicyIngressRule
+	}{
+		{
+			name:    "only admits the nodes and the operator by default",
+			cluster: cluster.WithNetworkPolicy(&api.NetworkPolicyConfig{}).Cluster(),
+			expected: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: selector}}}, Ports: grpc},
+				{From: operator, Ports: ports(26257, 8080)},
+			},
+		},
+		{
+			name: "admits the clients and the ingress controllers of the exposed ports",
+			cluster: cluster.WithNetworkPolicy(&api.NetworkPolicyConfig{
+				SQLClients:         apps,
+				HTTPClients:        monitoring,
+				IngressControllers: ingressControllers,
+				Operator:           monitoring,
+			}).WithIngress(&api.IngressConfig{UI: &api.Ingress{Host: "ui.test.com"}}).Cluster(),
+			expected: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: selector}}}, Ports: grpc},
+				{From: monitoring, Ports: ports(26257, 8080)},
+				{From: apps, Ports: sql},
+				{From: monitoring, Ports: http},
+				{From: ingressControllers, Ports: http},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := &networkingv1.NetworkPolicy{}
+			err := resource.NetworkPolicyBuilder{Cluster: tt.cluster, Selector: selector}.Build(actual)
+			require.NoError(t, err)
+
+			expected := &networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-cluster",
+					Labels: map[string]string{},
+				},
+				Spec: networkingv1.NetworkPolicySpec{
+					PodSelector: metav1.LabelSelector{MatchLabels: selector},
+					Ingress:     tt.expected,
+					PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				},
+			}
+			diff := cmp.Diff(expected, actual, testutil.RuntimeObjCmpOpts...)
+			if diff != "" {
+				assert.Fail(t, fmt.Sprintf("unexpected result (-want +got):\n%v", diff))
+			}
+		})
+	}
+}
+
+func TestCloneNetworkPolicyBuilder(t *testing.T) {
+	cr := testutil.NewBuilder("clone").Namespaced("test-ns").WithPVDataStore("1Gi").Cr()
+	cr.Spec.DataStore.DataSource = &api.SnapshotSource{Cluster: "crdb", Snapshot: "nightly"}
+	cluster := resource.NewCluster(cr)
+	selector := labels.Common(cr).Selector(nil)
+
+	actual := &networkingv1.NetworkPolicy{}
+	err := resource.CloneNetworkPolicyBuilder{Cluster: &cluster, Selector: selector}.Build(actual)
+	require.NoError(t, err)
+
+	peers := []networkingv1.NetworkPolicyPeer{
+		{PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key: "app.kubernetes.io/instance", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"crdb"},
+		}}}},
+		{NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"test-ns"},
+		}}}},
+	}
+	expected := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "clone-clone-isolation",
+			Labels: map[string]string{},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: selector},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+			Egress:      []networkingv1.NetworkPolicyEgressRule{{To: peers}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+	diff := cmp.Diff(expected, actual, testutil.RuntimeObjCmpOpts...)
+	if diff != "" {
+		assert.Fail(t, fmt.Sprintf("unexpected result (-want +got):\n%v", diff))
+	}
+
+	cr.Spec.DataStore.DataSource = nil
+	cluster = resource.NewCluster(cr)
+	require.Error(t, resource.CloneNetworkPolicyBuilder{Cluster: &cluster, Selector: selector}.Build(actual))
+}
+
+func ports(ports ...int32) []networkingv1.NetworkPolicyPort {
+	var policyPorts []networkingv1.NetworkPolicyPort
+	for _, port := range ports {
+		protocol := corev1.ProtocolTCP
+		p := intstr.FromInt32(port)
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p})
+	}
+	return policyPorts
+}
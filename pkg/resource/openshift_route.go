@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"errors"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OpenShiftRouteGVK is the kind of the OpenShift routes exposing the cluster
+var OpenShiftRouteGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// UIOpenShiftRouteBuilder models the OpenShift Route that the operator maintains for the DB Console
+// when spec.ingress uses the Route mode.
+type UIOpenShiftRouteBuilder struct {
+	*Cluster
+	Labels map[string]string
+}
+
+func (b UIOpenShiftRouteBuilder) ResourceName() string {
+	return "ui-" + b.IngressSuffix()
+}
+
+func (b UIOpenShiftRouteBuilder) Build(obj client.Object) error {
+	ingressConfig := b.Spec().Ingress
+	if ingressConfig == nil || ingressConfig.UI == nil {
+		return errors.New("ingressConfig not found")
+	}
+
+	// the DB Console only serves HTTPS on secure clusters, the router terminates TLS otherwise
+	termination := "passthrough"
+	if !b.Spec().TLSEnabled {
+		termination = "edge"
+	}
+
+	return buildOpenShiftRoute(obj, b.ResourceName(), b.Labels, b.Spec().AdditionalAnnotations,
+		ingressConfig.UI, b.PublicServiceName(), "http", termination)
+}
+
+func (b UIOpenShiftRouteBuilder) Placeholder() client.Object {
+	return routePlaceholder(b.ResourceName(), OpenShiftRouteGVK)
+}
+
+// SQLOpenShiftRouteBuilder models the OpenShift Route that the operator maintains for SQL
+// when spec.ingress uses the Route mode. The route passes TLS through to the nodes, which
+// requires a secure cluster and clients sending the host with SNI.
+type SQLOpenShiftRouteBuilder struct {
+	*Cluster
+	Labels map[string]string
+}
+
+func (b SQLOpenShiftRouteBuilder) ResourceName() string {
+	return "sql-" + b.IngressSuffix()
+}
+
+func (b SQLOpenShiftRouteBuilder) Build(obj client.Object) error {
+	ingressConfig := b.Spec().Ingress
+	if ingressConfig == nil || ingressConfig.SQL == nil {
+		return errors.New("ingressConfig not found")
+	}
+
+	return buildOpenShiftRoute(obj, b.ResourceName(), b.Labels, b.Spec().AdditionalAnnotations,
+		ingressConfig.SQL, b.PublicServiceName(), "sql", "passthrough")
+}
+
+func (b SQLOpenShiftRouteBuilder) Placeholder() client.Object {
+	return routePlaceholder(b.ResourceName(), OpenShiftRouteGVK)
+}
+
+func buildOpenShiftRoute(obj client.Object, name string, labels, annotations map[string]string,
+	config *api.Ingress, service, port, termination string) error {
+	route, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return errors.New("failed to cast to Route object")
+	}
+
+	route.SetGroupVersionKind(OpenShiftRouteGVK)
+	if route.GetName() == "" {
+		route.SetName(name)
+	}
+	route.SetLabels(labels)
+
+	merged := map[string]string{}
+	kube.MergeAnnotations(merged, annotations)
+	kube.MergeAnnotations(merged, config.Annotations)
+	if len(merged) == 0 {
+		merged = nil
+	}
+	route.SetAnnotations(merged)
+
+	// weight and wildcardPolicy are the values defaulted by OpenShift
+	route.Object["spec"] = map[string]interface{}{
+		"host": config.Host,
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   service,
+			"weight": int64(100),
+		},
+		"port": map[string]interface{}{
+			"targetPort": port,
+		},
+		"tls": map[string]interface{}{
+			"termination": termination,
+		},
+		"wildcardPolicy": "None",
+	}
+
+	return nil
+}
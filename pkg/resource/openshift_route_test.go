@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"testing"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/cockroach-operator/pkg/labels"
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestOpenShiftRouteBuilders(t *testing.T) {
+	cluster := testutil.NewBuilder("test-cluster").Namespaced("test-ns").WithTLS().
+		WithAnnotations(map[string]string{"key": "test-route"}).
+		WithIngress(&api.IngressConfig{
+			Mode: api.IngressModeRoute,
+			UI:   &api.Ingress{Host: "ui.test.com", Annotations: map[string]string{"route": "ui"}},
+			SQL:  &api.Ingress{Host: "sql.test.com"},
+		})
+	selector := labels.Common(cluster.Cr()).Selector(nil)
+
+	ui := resource.UIOpenShiftRouteBuilder{Cluster: cluster.Cluster(), Labels: selector}
+	actual := ui.Placeholder().(*unstructured.Unstructured)
+	require.NoError(t, ui.Build(actual))
+
+	expected := `apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  annotations:
+    key: test-route
+    route: ui
+  labels:
+    app.kubernetes.io/component: database
+    app.kubernetes.io/instance: test-cluster
+    app.kubernetes.io/name: cockroachdb
+  name: ui-test-cluster
+spec:
+  host: ui.test.com
+  port:
+    targetPort: http
+  tls:
+    termination: passthrough
+  to:
+    kind: Service
+    name: test-cluster-public
+    weight: 100
+  wildcardPolicy: None
+`
+	requireYAML(t, expected, actual)
+
+	sql := resource.SQLOpenShiftRouteBuilder{Cluster: cluster.Cluster(), Labels: selector}
+	actual = sql.Placeholder().(*unstructured.Unstructured)
+	require.NoError(t, sql.Build(actual))
+	require.Equal(t, "sql-test-cluster", actual.GetName())
+	port, _, _ := unstructured.NestedString(actual.Object, "spec", "port", "targetPort")
+	require.Equal(t, "sql", port)
+	termination, _, _ := unstructured.NestedString(actual.Object, "spec", "tls", "termination")
+	require.Equal(t, "passthrough", termination)
+
+	// the router terminates TLS for the DB Console of an insecure cluster
+	insecure := testutil.NewBuilder("test-cluster").Namespaced("test-ns").
+		WithIngress(&api.IngressConfig{UI: &api.Ingress{Host: "ui.test.com"}})
+	ui = resource.UIOpenShiftRouteBuilder{Cluster: insecure.Cluster()}
+	actual = ui.Placeholder().(*unstructured.Unstructured)
+	require.NoError(t, ui.Build(actual))
+	termination, _, _ = unstructured.NestedString(actual.Object, "spec", "tls", "termination")
+	require.Equal(t, "edge", termination)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"encoding/json"
+	"reflect"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
+	"github.com/cockroachdb/errors"
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// applyOverrides patches the built object with the overrides of its kind and name, in order.
+func (r Reconciler) applyOverrides(obj client.Object) error {
+	if len(r.Overrides) == 0 {
+		return nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	for _, override := range r.Overrides {
+		if override.Kind != gvk.Kind || override.Name != obj.GetName() {
+			continue
+		}
+		if err := ApplyOverride(obj, override); err != nil {
+			return errors.Wrapf(err, "failed to apply override of %s %s", override.Kind, override.Name)
+		}
+	}
+	return nil
+}
+
+// ApplyOverride patches obj with a strategic merge patch or a JSON patch.
+func ApplyOverride(obj client.Object, override api.ResourceOverride) error {
+	patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse patch")
+	}
+
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	if override.Type == api.JSONOverride {
+		p, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode JSON patch")
+		}
+		if patched, err = p.Apply(original); err != nil {
+			return errors.Wrap(err, "failed to apply JSON patch")
+		}
+	} else {
+		if patched, err = strategicpatch.StrategicMergePatch(original, patch, obj); err != nil {
+			return errors.Wrap(err, "failed to apply strategic merge patch")
+		}
+	}
+
+	// fields removed by the patch have to be cleared before decoding the result
+	v := reflect.ValueOf(obj).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return json.Unmarshal(patched, obj)
+}
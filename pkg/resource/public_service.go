@@ -19,6 +19,7 @@ package resource
 import (
 	"errors"
 
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,23 +45,16 @@ func (b PublicServiceBuilder) Build(obj client.Object) error {
 		service.ObjectMeta.Name = b.PublicServiceName()
 	}
 
-	if service.ObjectMeta.Labels == nil {
-		service.ObjectMeta.Labels = map[string]string{}
+	var config *api.ServiceConfig
+	if public := b.PublicServiceConfig(); public != nil {
+		config = &public.ServiceConfig
 	}
 
-	service.Annotations = b.Spec().AdditionalAnnotations
-
-	if service.Spec.Type != corev1.ServiceTypeClusterIP {
-		service.Spec = corev1.ServiceSpec{
-			Type: corev1.ServiceTypeClusterIP,
-			Ports: []corev1.ServicePort{
-				{Name: "grpc", Port: *b.Cluster.Spec().GRPCPort},
-				{Name: "http", Port: *b.Cluster.Spec().HTTPPort},
-				{Name: "sql", Port: *b.Cluster.Spec().SQLPort},
-			},
-		}
-	}
-	service.Spec.Selector = b.Selector
+	buildService(service, b.Spec().AdditionalAnnotations, config, b.Selector, []corev1.ServicePort{
+		{Name: "grpc", Port: *b.Cluster.Spec().GRPCPort},
+		{Name: "http", Port: *b.Cluster.Spec().HTTPPort},
+		{Name: "sql", Port: *b.Cluster.Spec().SQLPort},
+	})
 
 	return nil
 }
@@ -72,3 +66,149 @@ func (b PublicServiceBuilder) Placeholder() client.Object {
 		},
 	}
 }
+
+// SQLServiceBuilder builds the service that only exposes the SQL port, when
+// spec.services.public.sql is set.
+type SQLServiceBuilder struct {
+	*Cluster
+
+	Selector map[string]string
+}
+
+func (b SQLServiceBuilder) ResourceName() string {
+	return b.SQLServiceName()
+}
+
+func (b SQLServiceBuilder) Build(obj client.Object) error {
+	service, ok := obj.(*corev1.Service)
+	if !ok {
+		return errors.New("failed to cast to Service object")
+	}
+
+	if service.ObjectMeta.Name == "" {
+		service.ObjectMeta.Name = b.SQLServiceName()
+	}
+
+	var config *api.ServiceConfig
+	if public := b.PublicServiceConfig(); public != nil {
+		config = public.SQL
+	}
+
+	buildService(service, b.Spec().AdditionalAnnotations, config, b.Selector, []corev1.ServicePort{
+		{Name: "sql", Port: *b.Cluster.Spec().SQLPort},
+	})
+
+	return nil
+}
+
+func (b SQLServiceBuilder) Placeholder() client.Object {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: b.SQLServiceName(),
+		},
+	}
+}
+
+// UIServiceBuilder builds the service that only exposes the HTTP port, when
+// spec.services.public.ui is set.
+type UIServiceBuilder struct {
+	*Cluster
+
+	Selector map[string]string
+}
+
+func (b UIServiceBuilder) ResourceName() string {
+	return b.UIServiceName()
+}
+
+func (b UIServiceBuilder) Build(obj client.Object) error {
+	service, ok := obj.(*corev1.Service)
+	if !ok {
+		return errors.New("failed to cast to Service object")
+	}
+
+	if service.ObjectMeta.Name == "" {
+		service.ObjectMeta.Name = b.UIServiceName()
+	}
+
+	var config *api.ServiceConfig
+	if public := b.PublicServiceConfig(); public != nil {
+		config = public.UI
+	}
+
+	buildService(service, b.Spec().AdditionalAnnotations, config, b.Selector, []corev1.ServicePort{
+		{Name: "http", Port: *b.Cluster.Spec().HTTPPort},
+	})
+
+	return nil
+}
+
+func (b UIServiceBuilder) Placeholder() client.Object {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: b.UIServiceName(),
+		},
+	}
+}
+
+// buildService applies the configuration of an exposed service. Values allocated by
+// Kubernetes, like the cluster IP and the node ports, are kept as long as the type
+// of the service does not change.
+func buildService(service *corev1.Service, annotations map[string]string, config *api.ServiceConfig,
+	selector map[string]string, ports []corev1.ServicePort) {
+	if service.ObjectMeta.Labels == nil {
+		service.ObjectMeta.Labels = map[string]string{}
+	}
+
+	service.Annotations = annotations
+
+	serviceType := corev1.ServiceTypeClusterIP
+	if config != nil {
+		if config.Type != "" {
+			serviceType = config.Type
+		}
+		if len(config.Annotations) > 0 {
+			service.Annotations = make(map[string]string, len(annotations)+len(config.Annotations))
+			for k, v := range annotations {
+				service.Annotations[k] = v
+			}
+			for k, v := range config.Annotations {
+				service.Annotations[k] = v
+			}
+		}
+	}
+
+	if service.Spec.Type != serviceType {
+		service.Spec = corev1.ServiceSpec{
+			Type:           serviceType,
+			ClusterIP:      service.Spec.ClusterIP,
+			ClusterIPs:     service.Spec.ClusterIPs,
+			IPFamilies:     service.Spec.IPFamilies,
+			IPFamilyPolicy: service.Spec.IPFamilyPolicy,
+		}
+	}
+
+	// node ports are allocated by Kubernetes
+	nodePorts := map[string]int32{}
+	for _, port := range service.Spec.Ports {
+		nodePorts[port.Name] = port.NodePort
+	}
+	for i := range ports {
+		ports[i].NodePort = nodePorts[ports[i].Name]
+	}
+	service.Spec.Ports = ports
+	service.Spec.Selector = selector
+
+	if config == nil {
+		return
+	}
+	if serviceType == corev1.ServiceTypeLoadBalancer {
+		if config.LoadBalancerClass != nil {
+			service.Spec.LoadBalancerClass = config.LoadBalancerClass
+		}
+		service.Spec.LoadBalancerSourceRanges = config.LoadBalancerSourceRanges
+	}
+	if serviceType != corev1.ServiceTypeClusterIP && config.ExternalTrafficPolicy != "" {
+		service.Spec.ExternalTrafficPolicy = config.ExternalTrafficPolicy
+	}
+}
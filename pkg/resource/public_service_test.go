@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"testing"
 
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/labels"
 	"github.com/cockroachdb/cockroach-operator/pkg/resource"
 	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
@@ -34,6 +35,16 @@ func TestPublicServiceBuilder(t *testing.T) {
 	annotations := map[string]string{"key": "test-public-svc"}
 	cluster := testutil.NewBuilder("test-cluster").Namespaced("test-ns").WithAnnotations(annotations)
 	commonLabels := labels.Common(cluster.Cr())
+	selector := commonLabels.Selector(cluster.Cr().Spec.AdditionalLabels)
+	class := "internal"
+	lbCluster := testutil.NewBuilder("test-cluster").Namespaced("test-ns").WithAnnotations(annotations).
+		WithPublicServices(&api.PublicServiceConfig{ServiceConfig: api.ServiceConfig{
+			Type:                     corev1.ServiceTypeLoadBalancer,
+			LoadBalancerClass:        &class,
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+			ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+			Annotations:              map[string]string{"lb": "internal"},
+		}})
 
 	tests := []struct {
 		name     string
@@ -66,6 +77,30 @@ func TestPublicServiceBuilder(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "builds load balancer public service",
+			cluster:  lbCluster.Cluster(),
+			selector: selector,
+			expected: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-cluster-public",
+					Labels:      map[string]string{},
+					Annotations: map[string]string{"key": "test-public-svc", "lb": "internal"},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{
+						{Name: "grpc", Port: 26258},
+						{Name: "http", Port: 8080},
+						{Name: "sql", Port: 26257},
+					},
+					Selector:                 selector,
+					LoadBalancerClass:        &class,
+					LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+					ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,3 +120,53 @@ func TestPublicServiceBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestPublicServiceBuilderKeepsAllocatedValues(t *testing.T) {
+	cluster := testutil.NewBuilder("test-cluster").Namespaced("test-ns").
+		WithPublicServices(&api.PublicServiceConfig{ServiceConfig: api.ServiceConfig{Type: corev1.ServiceTypeNodePort}})
+
+	actual := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-public"},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeNodePort,
+			ClusterIP: "10.96.0.10",
+			Ports: []corev1.ServicePort{
+				{Name: "grpc", Port: 26258, NodePort: 30001},
+				{Name: "http", Port: 8080, NodePort: 30002},
+				{Name: "sql", Port: 26257, NodePort: 30003},
+			},
+		},
+	}
+	require.NoError(t, resource.PublicServiceBuilder{Cluster: cluster.Cluster()}.Build(actual))
+	require.Equal(t, "10.96.0.10", actual.Spec.ClusterIP)
+	require.Equal(t, int32(30003), actual.Spec.Ports[2].NodePort)
+
+	// switching back to a cluster IP service releases the node ports
+	cluster = testutil.NewBuilder("test-cluster").Namespaced("test-ns")
+	require.NoError(t, resource.PublicServiceBuilder{Cluster: cluster.Cluster()}.Build(actual))
+	require.Equal(t, corev1.ServiceTypeClusterIP, actual.Spec.Type)
+	require.Equal(t, "10.96.0.10", actual.Spec.ClusterIP)
+	require.Zero(t, actual.Spec.Ports[2].NodePort)
+}
+
+func TestSQLAndUIServiceBuilders(t *testing.T) {
+	cluster := testutil.NewBuilder("test-cluster").Namespaced("test-ns").
+		WithPublicServices(&api.PublicServiceConfig{
+			SQL: &api.ServiceConfig{Type: corev1.ServiceTypeLoadBalancer},
+			UI:  &api.ServiceConfig{},
+		}).Cluster()
+
+	sql := &corev1.Service{}
+	require.NoError(t, resource.SQLServiceBuilder{Cluster: cluster}.Build(sql))
+	require.Equal(t, "test-cluster-sql", sql.Name)
+	require.Equal(t, corev1.ServiceTypeLoadBalancer, sql.Spec.Type)
+	require.Equal(t, []corev1.ServicePort{{Name: "sql", Port: 26257}}, sql.Spec.Ports)
+
+	ui := &corev1.Service{}
+	require.NoError(t, resource.UIServiceBuilder{Cluster: cluster}.Build(ui))
+	require.Equal(t, "test-cluster-ui", ui.Name)
+	require.Equal(t, corev1.ServiceTypeClusterIP, ui.Spec.Type)
+	require.Equal(t, []corev1.ServicePort{{Name: "http", Port: 8080}}, ui.Spec.Ports)
+
+	require.Equal(t, "test-cluster-sql", cluster.SQLLoadBalancerServiceName())
+}
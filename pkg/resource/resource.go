@@ -18,6 +18,8 @@ package resource
 
 import (
 	"context"
+
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/kube"
 	"github.com/cockroachdb/cockroach-operator/pkg/labels"
 	"github.com/cockroachdb/errors"
@@ -63,15 +65,17 @@ func NewManagedKubeResource(ctx context.Context, client client.Client, cluster *
 	return ManagedResource{
 		Resource: NewKubeResource(ctx, client, cluster.Namespace(), persistFn),
 
-		Labels: labels.Common(cluster.Unwrap()),
+		Labels:    labels.Common(cluster.Unwrap()),
+		Overrides: cluster.Spec().Overrides,
 	}
 }
 
-// ManagedResource is a `Resource` with labels which can be reconciled by `Reconciler`
+// ManagedResource is a `Resource` with labels and overrides which can be reconciled by `Reconciler`
 type ManagedResource struct {
 	Resource
 
 	labels.Labels
+	Overrides []api.ResourceOverride
 }
 
 // Reconciler reconciles managed Kubernetes resource with `Builder` results
@@ -107,6 +111,10 @@ func (r Reconciler) CompleteBuild(current runtime.Object, desired client.Object)
 	if err := r.reconcileAnnotations(current, desired); err != nil {
 		return errors.Wrap(err, "failed to reconcile annotations")
 	}
+	// overrides go last so they can also change labels and annotations
+	if err := r.applyOverrides(desired); err != nil {
+		return err
+	}
 	if err := r.ensureIsOwned(desired); err != nil {
 		return errors.Wrap(err, "failed to set object ownership")
 	}
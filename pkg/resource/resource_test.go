@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"testing"
 
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/kube"
 	"github.com/cockroachdb/cockroach-operator/pkg/labels"
 	"github.com/cockroachdb/cockroach-operator/pkg/ptr"
@@ -70,6 +71,32 @@ func TestReconcile(t *testing.T) {
 			wantUpserted: false,
 			expected:     makeTestService(),
 		},
+		{
+			name: "applies overrides of the object kind and name",
+			cluster: testutil.NewBuilder("test-cluster").Namespaced("default").
+				WithUID("test-cluster-uid").WithOverrides(
+				api.ResourceOverride{
+					Kind:  "Service",
+					Name:  "test-cluster",
+					Type:  api.StrategicMergeOverride,
+					Patch: "metadata:\n  annotations:\n    team: db\nspec:\n  ports:\n  - port: 8080\n    appProtocol: https\n",
+				},
+				api.ResourceOverride{
+					Kind:  "Service",
+					Name:  "test-cluster",
+					Type:  api.JSONOverride,
+					Patch: `[{"op": "remove", "path": "/metadata/annotations/prometheus.io~1scrape"}]`,
+				},
+				api.ResourceOverride{
+					Kind:  "Service",
+					Name:  "test-cluster-public",
+					Patch: "spec:\n  type: LoadBalancer\n",
+				},
+			).Cluster(),
+			existingObjs: []runtime.Object{makeTestService()},
+			wantUpserted: true,
+			expected:     overrideTestService(makeTestService()),
+		},
 	}
 
 	for _, tt := range tests {
@@ -170,6 +197,15 @@ func modifyHTTPPort(newValue int32, service *corev1.Service) *corev1.Service {
 	return service
 }
 
+func overrideTestService(service *corev1.Service) *corev1.Service {
+	service.ObjectMeta.Annotations["team"] = "db"
+	delete(service.ObjectMeta.Annotations, "prometheus.io/scrape")
+	appProtocol := "https"
+	service.Spec.Ports[1].AppProtocol = &appProtocol
+
+	return service
+}
+
 func stripOutLastAppliedAnnotation(aa map[string]string) {
 	delete(aa, kube.LastAppliedAnnotation)
 }
@@ -17,11 +17,13 @@ limitations under the License.
 package resource
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	api "github.com/cockroachdb/cockroach-operator/apis/v1alpha1"
 	"github.com/cockroachdb/cockroach-operator/pkg/features"
 	"github.com/cockroachdb/cockroach-operator/pkg/labels"
 	"github.com/cockroachdb/cockroach-operator/pkg/ptr"
@@ -31,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -39,13 +42,23 @@ const (
 	grpcPortName = "grpc"
 	sqlPortName  = "sql"
 
-	dataDirName      = "datadir"
+	// DataDirName is the name of the volume claim template of the data store
+	DataDirName      = "datadir"
 	dataDirMountPath = "/cockroach/cockroach-data/"
 
 	certsDirName = "certs"
 	certCpCmd    = ">- cp -p /cockroach/cockroach-certs-prestage/..data/* /cockroach/cockroach-certs/ && chmod 600 /cockroach/cockroach-certs/*.key && chown 1000581000:1000581000 /cockroach/cockroach-certs/*.key"
 	emptyDirName = "emptydir"
 
+	encryptionDirName      = "encryption"
+	encryptionDirMountPath = "/cockroach/cockroach-encryption/"
+	// storePath is the path of the store relative to the working directory of the db container
+	storePath = "cockroach-data"
+
+	storesDirMountPath      = "/cockroach/stores/"
+	walFailoverDirName      = "wal-failover"
+	walFailoverDirMountPath = "/cockroach/wal-failover"
+
 	// DbContainerName is the name of the container definition in the pod spec
 	DbContainerName     = "db"
 	DbInitContainerName = "db-init"
@@ -58,6 +71,9 @@ type StatefulSetBuilder struct {
 
 	Selector  labels.Labels
 	Telemetry string
+	// Partition holds the pods on their current revision when the pod template changes,
+	// for the operator to restart them one at a time
+	Partition *int32
 }
 
 func (b StatefulSetBuilder) Build(obj client.Object) error {
@@ -76,11 +92,16 @@ func (b StatefulSetBuilder) Build(obj client.Object) error {
 	}
 	ss.Annotations[CrdbVersionAnnotation] = b.Cluster.GetVersionAnnotation()
 	ss.Annotations[CrdbContainerImageAnnotation] = b.Cluster.GetAnnotationContainerImage()
+	if b.IsEncryptionEnabled() {
+		ss.Annotations[CrdbEncryptionKeyAnnotation] = b.EncryptionKeyReference()
+	}
 	ss.Spec = appsv1.StatefulSetSpec{
 		ServiceName: b.Cluster.DiscoveryServiceName(),
 		Replicas:    ptr.Int32(b.Spec().Nodes),
 		UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
-			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{},
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: b.Partition,
+			},
 		},
 		PodManagementPolicy: appsv1.ParallelPodManagement,
 		Selector: &metav1.LabelSelector{
@@ -89,16 +110,33 @@ func (b StatefulSetBuilder) Build(obj client.Object) error {
 		Template: b.makePodTemplate(),
 	}
 
-	if err := b.Spec().DataStore.Apply(dataDirName, DbContainerName, dataDirMountPath, &ss.Spec,
+	if err := b.Spec().DataStore.Apply(DataDirName, DbContainerName, dataDirMountPath, &ss.Spec,
 		func(name string) metav1.ObjectMeta {
 			return metav1.ObjectMeta{
-				Name:   dataDirName,
+				Name:   DataDirName,
 				Labels: b.Selector,
 			}
 		}); err != nil {
 		return err
 	}
 
+	// the volumes of the data store may have been expanded beyond the requested storage
+	for i := range ss.Spec.VolumeClaimTemplates {
+		if pvct := &ss.Spec.VolumeClaimTemplates[i]; pvct.Name == DataDirName && pvct.Spec.Resources.Requests != nil {
+			pvct.Spec.Resources.Requests[corev1.ResourceStorage] = b.DataStoreSize()
+		}
+	}
+
+	if err := b.addStoreVolumes(&ss.Spec); err != nil {
+		return err
+	}
+
+	if b.IsEncryptionEnabled() {
+		if err := addEncryptionVolume(b.Spec().Encryption.SecretName, &ss.Spec.Template.Spec); err != nil {
+			return err
+		}
+	}
+
 	if b.Spec().TLSEnabled {
 		if err := addCertsVolumeMountOnInitContiners(DbContainerName, &ss.Spec.Template.Spec); err != nil {
 			return err
@@ -168,7 +206,7 @@ func (b StatefulSetBuilder) Build(obj client.Object) error {
 		})
 	}
 
-	return nil
+	return b.applyPodTemplate(&ss.Spec.Template)
 }
 
 func (b StatefulSetBuilder) ResourceName() string {
@@ -253,6 +291,31 @@ func (b StatefulSetBuilder) makePodTemplate() corev1.PodTemplateSpec {
 	return pod
 }
 
+// applyPodTemplate merges spec.podTemplate onto the generated pod template with a
+// strategic merge patch, so containers and volumes are merged by name.
+func (b StatefulSetBuilder) applyPodTemplate(template *corev1.PodTemplateSpec) error {
+	overlay := b.Spec().PodTemplate
+	if overlay == nil || len(overlay.Raw) == 0 {
+		return nil
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	merged, err := strategicpatch.StrategicMergePatch(original, overlay.Raw, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("failed to merge podTemplate: %w", err)
+	}
+
+	result := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(merged, &result); err != nil {
+		return fmt.Errorf("failed to merge podTemplate: %w", err)
+	}
+	*template = result
+	return nil
+}
+
 // MakeInitContainers creates a slice of corev1.Containers which includes a single
 // corev1.Container that is based on the CR.
 func (b StatefulSetBuilder) MakeInitContainers() []corev1.Container {
@@ -400,6 +463,18 @@ func (b StatefulSetBuilder) dbArgs() []string {
 		aa = append(aa, "--max-sql-memory $(expr $MEMORY_LIMIT_MIB / 4)MiB")
 	}
 
+	aa = append(aa, b.storeArgs()...)
+
+	if b.Spec().DataStore.WALFailover != nil {
+		aa = append(aa, "--wal-failover=path="+walFailoverDirMountPath)
+	}
+
+	if b.IsEncryptionEnabled() {
+		for _, path := range b.storePaths() {
+			aa = append(aa, b.encryptionArg(path))
+		}
+	}
+
 	aa = append(aa, b.Spec().AdditionalArgs...)
 
 	needsDefaultJoin := true
@@ -426,6 +501,183 @@ func (b StatefulSetBuilder) joinStr() string {
 
 	return strings.Join(seeds, ",")
 }
+
+// storeClaim is the volume claim template of an additional store or of the WAL failover volume.
+type storeClaim struct {
+	name string
+	path string
+	spec corev1.PersistentVolumeClaimSpec
+}
+
+// storeClaims returns the volume claim templates of the additional stores and of the WAL
+// failover volume.
+func (b StatefulSetBuilder) storeClaims() []storeClaim {
+	var claims []storeClaim
+	for _, store := range b.Spec().DataStore.Stores {
+		claims = append(claims, storeClaim{name: store.Name, path: storesDirMountPath + store.Name, spec: store.Spec})
+	}
+	if wal := b.Spec().DataStore.WALFailover; wal != nil {
+		claims = append(claims, storeClaim{name: walFailoverDirName, path: walFailoverDirMountPath, spec: wal.Spec})
+	}
+	return claims
+}
+
+// addStoreVolumes adds the volume claim templates of the additional stores and of the WAL failover
+// volume to the statefulset, and mounts them in the db container.
+func (b StatefulSetBuilder) addStoreVolumes(spec *appsv1.StatefulSetSpec) error {
+	claims := b.storeClaims()
+	if len(claims) == 0 {
+		return nil
+	}
+
+	c, err := findContainer(DbContainerName, &spec.Template.Spec)
+	if err != nil {
+		return err
+	}
+
+	for _, claim := range claims {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      claim.name,
+			MountPath: claim.path,
+		})
+		spec.VolumeClaimTemplates = append(spec.VolumeClaimTemplates, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   claim.name,
+				Labels: b.Selector,
+			},
+			Spec: claim.spec,
+		})
+	}
+	return nil
+}
+
+// storeArgs returns the --store flags of a node with several stores or with store attributes.
+// The single store of the other nodes is the default store of cockroach.
+func (b StatefulSetBuilder) storeArgs() []string {
+	ds := b.Spec().DataStore
+	if len(ds.Stores) == 0 && len(ds.Attributes) == 0 {
+		return nil
+	}
+
+	storeArg := func(path string, attributes []string) string {
+		arg := "--store=path=" + path
+		if len(attributes) > 0 {
+			arg += ",attrs=" + strings.Join(attributes, ":")
+		}
+		return arg
+	}
+
+	aa := []string{storeArg(storePath, ds.Attributes)}
+	for _, store := range ds.Stores {
+		aa = append(aa, storeArg(storesDirMountPath+store.Name, store.Attributes))
+	}
+	return aa
+}
+
+// storePaths returns the paths of the stores of a node and of its WAL failover volume.
+func (b StatefulSetBuilder) storePaths() []string {
+	paths := []string{storePath}
+	for _, claim := range b.storeClaims() {
+		paths = append(paths, claim.path)
+	}
+	return paths
+}
+
+// StoreMountPaths returns the paths of the stores of a node in the db container, without the WAL
+// failover volume.
+func StoreMountPaths(cluster *Cluster) []string {
+	paths := []string{strings.TrimSuffix(dataDirMountPath, "/")}
+	for _, store := range cluster.Spec().DataStore.Stores {
+		paths = append(paths, storesDirMountPath+store.Name)
+	}
+	return paths
+}
+
+// VolumeClaimTemplateSizes returns the storage requested by each volume claim template of the
+// statefulset of the cluster.
+func VolumeClaimTemplateSizes(cluster *Cluster) map[string]resource.Quantity {
+	sizes := make(map[string]resource.Quantity)
+	if cluster.Spec().DataStore.VolumeClaim != nil {
+		sizes[DataDirName] = cluster.DataStoreSize()
+	}
+	for _, claim := range (StatefulSetBuilder{Cluster: cluster}).storeClaims() {
+		sizes[claim.name] = *claim.spec.Resources.Requests.Storage()
+	}
+	return sizes
+}
+
+// DataStoreClaimTemplate returns the volume claim template of the data store of a statefulset, or
+// nil if the data store does not use persistent volumes.
+func DataStoreClaimTemplate(sts *appsv1.StatefulSet) *corev1.PersistentVolumeClaim {
+	for i := range sts.Spec.VolumeClaimTemplates {
+		if sts.Spec.VolumeClaimTemplates[i].Name == DataDirName {
+			return &sts.Spec.VolumeClaimTemplates[i]
+		}
+	}
+	return nil
+}
+
+// StatefulSetTLSEnabled returns whether the nodes of a statefulset are started in secure mode. The
+// second value is false if the statefulset has no CockroachDB container, e.g. it is not deployed yet.
+func StatefulSetTLSEnabled(sts *appsv1.StatefulSet) (bool, bool) {
+	c, err := findContainer(DbContainerName, &sts.Spec.Template.Spec)
+	if err != nil {
+		return false, false
+	}
+	return !strings.Contains(strings.Join(c.Command, " "), "--insecure"), true
+}
+
+func findContainer(container string, spec *corev1.PodSpec) (*corev1.Container, error) {
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == container {
+			return &spec.Containers[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find container %s to attach volume", container)
+}
+
+// encryptionArg returns the --enterprise-encryption flag of a store, with the paths of the
+// active and old store keys mounted from the encryption Secret.
+func (b StatefulSetBuilder) encryptionArg(path string) string {
+	keyPath := func(key string) string {
+		if key == "" || key == api.EncryptionPlainKey {
+			return api.EncryptionPlainKey
+		}
+		return encryptionDirMountPath + key
+	}
+
+	config := b.Spec().Encryption
+	return fmt.Sprintf("--enterprise-encryption=path=%s,key=%s,old-key=%s",
+		path, keyPath(config.ActiveKey), keyPath(config.OldKey))
+}
+
+// addEncryptionVolume mounts the Secret of the store keys in the db container.
+func addEncryptionVolume(secretName string, spec *corev1.PodSpec) error {
+	c, err := findContainer(DbContainerName, spec)
+	if err != nil {
+		return err
+	}
+
+	c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+		Name:      encryptionDirName,
+		MountPath: encryptionDirMountPath,
+		ReadOnly:  true,
+	})
+
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: encryptionDirName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  secretName,
+				DefaultMode: ptr.Int32(0400),
+			},
+		},
+	})
+
+	return nil
+}
+
 func addCertsVolumeMountOnInitContiners(container string, spec *corev1.PodSpec) error {
 	found := false
 	for i := range spec.InitContainers {
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// VolumeSnapshotGVK is the kind of the CSI snapshots of the volumes of the cluster
+var VolumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// VolumeSnapshotName returns the name of the VolumeSnapshot of a PVC in a snapshot.
+func VolumeSnapshotName(pvc, snapshot string) string {
+	return pvc + "-" + snapshot
+}
+
+// NewVolumeSnapshot returns the VolumeSnapshot of a PVC of the cluster in a snapshot. The
+// VolumeSnapshots are not owned by the cluster, they outlive it.
+func NewVolumeSnapshot(cluster *Cluster, pvc, snapshot string, labels map[string]string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvc,
+		},
+	}
+	if class := cluster.Spec().DataStore.VolumeSnapshotClassName; class != "" {
+		spec["volumeSnapshotClassName"] = class
+	}
+
+	vs := VolumeSnapshotPlaceholder(VolumeSnapshotName(pvc, snapshot))
+	vs.SetNamespace(cluster.Namespace())
+	vs.SetLabels(labels)
+	vs.Object["spec"] = spec
+	return vs
+}
+
+// VolumeSnapshotPlaceholder returns an empty VolumeSnapshot with the given name.
+func VolumeSnapshotPlaceholder(name string) *unstructured.Unstructured {
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(VolumeSnapshotGVK)
+	vs.SetName(name)
+	return vs
+}
+
+// VolumeSnapshotState returns whether a VolumeSnapshot is ready to be used as a data source, and
+// the error message it reports if the snapshot failed.
+func VolumeSnapshotState(vs *unstructured.Unstructured) (bool, string) {
+	ready, _, _ := unstructured.NestedBool(vs.Object, "status", "readyToUse")
+	message, _, _ := unstructured.NestedString(vs.Object, "status", "error", "message")
+	return ready, message
+}
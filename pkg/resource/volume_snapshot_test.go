@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach-operator/pkg/resource"
+	"github.com/cockroachdb/cockroach-operator/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestNewVolumeSnapshot(t *testing.T) {
+	cr := testutil.NewBuilder("test-cluster").Namespaced("test-ns").WithPVDataStore("1Gi").Cr()
+	cr.Spec.DataStore.VolumeSnapshotClassName = "csi-snapclass"
+	cluster := resource.NewCluster(cr)
+
+	actual := resource.NewVolumeSnapshot(&cluster, "datadir-test-cluster-0", "nightly",
+		map[string]string{resource.CrdbSnapshotAnnotation: "nightly"})
+
+	expected := `apiVersion: snapshot.storage.k8s.io/v1
+kind: VolumeSnapshot
+metadata:
+  labels:
+    crdb.io/snapshot: nightly
+  name: datadir-test-cluster-0-nightly
+  namespace: test-ns
+spec:
+  source:
+    persistentVolumeClaimName: datadir-test-cluster-0
+  volumeSnapshotClassName: csi-snapclass
+`
+	body, err := yaml.Marshal(actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(body))
+}
+
+func TestVolumeSnapshotState(t *testing.T) {
+	vs := resource.VolumeSnapshotPlaceholder("datadir-test-cluster-0-nightly")
+	ready, message := resource.VolumeSnapshotState(vs)
+	require.False(t, ready)
+	require.Empty(t, message)
+
+	require.NoError(t, unstructured.SetNestedField(vs.Object, true, "status", "readyToUse"))
+	ready, message = resource.VolumeSnapshotState(vs)
+	require.True(t, ready)
+	require.Empty(t, message)
+
+	require.NoError(t, unstructured.SetNestedField(vs.Object, false, "status", "readyToUse"))
+	require.NoError(t, unstructured.SetNestedField(vs.Object, "snapshot controller failed", "status", "error", "message"))
+	ready, message = resource.VolumeSnapshotState(vs)
+	require.False(t, ready)
+	require.Equal(t, "snapshot controller failed", message)
+}
@@ -17,14 +17,17 @@ limitations under the License.
 package resource
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach-operator/pkg/security"
 	"github.com/cockroachdb/errors"
 	"github.com/go-logr/logr"
 	"go.uber.org/zap/zapcore"
 	v1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -59,6 +62,12 @@ type ValidatingWebhookConfigInterface interface {
 	Update(context.Context, *v1.ValidatingWebhookConfiguration, metav1.UpdateOptions) (*v1.ValidatingWebhookConfiguration, error)
 }
 
+// WebhookCASecretsInterface is a subset of methods from client-go's v1.SecretsInterface needed to rotate the webhook CA.
+type WebhookCASecretsInterface interface {
+	SecretsInterface
+	Update(context.Context, *corev1.Secret, metav1.UpdateOptions) (*corev1.Secret, error)
+}
+
 // FindOrCreateWebhookCA ensures the webhook CA secret exists, creating it if it's not found. This certificate is used
 // to sign the webhook server certificate which allows secure communication with the K8s API.
 //
@@ -80,9 +89,54 @@ func FindOrCreateWebhookCA(ctx context.Context, api SecretsInterface) (security.
 	return ca, errors.Wrap(err, "failed to get webhook CA certificate")
 }
 
+// RenewWebhookCA replaces the webhook CA secret with a new CA certificate when the current one expires within
+// renewBefore of now. Since the webhook server certificate cannot outlive its CA, this is what keeps the webhook
+// available past the CA lifetime. The returned bool is true when the CA was replaced.
+func RenewWebhookCA(ctx context.Context, api WebhookCASecretsInterface, renewBefore time.Duration, now time.Time) (security.Certificate, bool, error) {
+	log := logr.FromContextOrDiscard(ctx).WithName("webhook-setup")
+
+	ca, err := FindOrCreateWebhookCA(ctx, api)
+	if err != nil {
+		// logging and wrapping already done in FindOrCreateWebhookCA
+		return nil, false, err
+	}
+
+	caCrt, err := security.ParseCertificate(ca.Certificate())
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to parse webhook CA certificate")
+	}
+
+	if !now.Add(renewBefore).After(caCrt.NotAfter) {
+		return ca, false, nil
+	}
+
+	log.Info("Renewing the webhook CA certificate", "notAfter", caCrt.NotAfter)
+	newCA, err := security.NewCACertificate(security.OrgOption(webhookSecretOrg))
+	if err != nil {
+		log.Error(err, "Failed to create webhook CA certificate")
+		return nil, false, errors.Wrap(err, "failed to create webhook CA certificate")
+	}
+
+	secret, err := api.Get(ctx, webhookCASecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get webhook CA secret")
+	}
+
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       newCA.Certificate(),
+		corev1.TLSPrivateKeyKey: newCA.PrivateKey(),
+	}
+
+	if _, err := api.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		log.Error(err, "Failed to update webhook CA secret")
+		return nil, false, errors.Wrap(err, "failed to update webhook CA secret")
+	}
+
+	return newCA, true, nil
+}
+
 // CreateWebhookCertificate generates a new server certificate signed with the webhook CA cert. This certificate is not
-// saved anywhere in K8s. The idea is that rotation of the certificate can be performed simply by rolling the manager
-// deployment.
+// saved anywhere in K8s. The running manager reissues it before it expires, see WebhookCertificateNeedsRenewal.
 func CreateWebhookCertificate(ctx context.Context, api SecretsInterface, ns string) (security.Certificate, error) {
 	log := logr.FromContextOrDiscard(ctx).WithName("webhook-setup")
 
@@ -186,3 +240,44 @@ func PatchValidatingWebhookConfig(ctx context.Context, api ValidatingWebhookConf
 
 	return errors.Wrap(err, "failed to set CABundle for validating webhook")
 }
+
+// WebhookCertificateNeedsRenewal returns true when the webhook server certificate expires within renewBefore of now,
+// or when it is not signed by the supplied CA, which happens after the cockroach-operator-webhook-ca secret has been
+// replaced.
+func WebhookCertificateNeedsRenewal(cert, ca security.Certificate, renewBefore time.Duration, now time.Time) (bool, error) {
+	crt, err := security.ParseCertificate(cert.Certificate())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse webhook certificate")
+	}
+
+	caCrt, err := security.ParseCertificate(ca.Certificate())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse webhook CA certificate")
+	}
+
+	if now.Add(renewBefore).After(crt.NotAfter) {
+		return true, nil
+	}
+
+	return crt.CheckSignatureFrom(caCrt) != nil, nil
+}
+
+// WebhookCABundle returns a certificate whose Certificate value is the concatenation of the supplied CA certificates,
+// skipping nil and duplicate entries. Patching the webhook configurations with the new and the previous CA lets the K8s
+// API server trust both the old and the new serving certificate while the manager switches between them.
+func WebhookCABundle(cas ...security.Certificate) security.Certificate {
+	bundle := &certificate{}
+	for _, ca := range cas {
+		if ca == nil || len(ca.Certificate()) == 0 || bytes.Contains(bundle.crt, ca.Certificate()) {
+			continue
+		}
+
+		if bundle.pk == nil {
+			bundle.pk = ca.PrivateKey()
+		}
+
+		bundle.crt = append(bundle.crt, ca.Certificate()...)
+	}
+
+	return bundle
+}
@@ -19,6 +19,7 @@ package resource_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/cockroachdb/cockroach-operator/pkg/resource"
 	"github.com/cockroachdb/cockroach-operator/pkg/security"
@@ -118,6 +119,83 @@ func TestCreateWebhookCertificate(t *testing.T) {
 	})
 }
 
+func TestWebhookCertificateNeedsRenewal(t *testing.T) {
+	ca, err := security.NewCACertificate(security.ExpOption(365 * 24 * time.Hour))
+	require.NoError(t, err)
+
+	otherCA, err := security.NewCACertificate()
+	require.NoError(t, err)
+
+	cert, err := security.NewCertificate(ca)
+	require.NoError(t, err)
+
+	now := time.Now()
+	renewBefore := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		ca       security.Certificate
+		now      time.Time
+		expected bool
+	}{
+		{name: "valid certificate", ca: ca, now: now},
+		{name: "expires soon", ca: ca, now: now.Add(340 * 24 * time.Hour), expected: true},
+		{name: "signed by another CA", ca: otherCA, now: now, expected: true},
+	}
+
+	for _, tt := range tests {
+		actual, err := WebhookCertificateNeedsRenewal(cert, tt.ca, renewBefore, tt.now)
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.expected, actual, tt.name)
+	}
+}
+
+func TestRenewWebhookCA(t *testing.T) {
+	ctx := context.Background()
+	name := "cockroach-operator-webhook-ca"
+	namespace := "bogus-ns"
+	renewBefore := 30 * 24 * time.Hour
+
+	api := fake.NewSimpleClientset().CoreV1().Secrets(namespace)
+
+	ca, err := FindOrCreateWebhookCA(ctx, api)
+	require.NoError(t, err)
+
+	t.Run("keeps a valid CA", func(t *testing.T) {
+		actual, rotated, err := RenewWebhookCA(ctx, api, renewBefore, time.Now())
+		require.NoError(t, err)
+		require.False(t, rotated)
+		require.Equal(t, ca.Certificate(), actual.Certificate())
+	})
+
+	t.Run("replaces a CA that expires soon", func(t *testing.T) {
+		actual, rotated, err := RenewWebhookCA(ctx, api, renewBefore, time.Now().Add(10*365*24*time.Hour))
+		require.NoError(t, err)
+		require.True(t, rotated)
+		require.NotEqual(t, ca.Certificate(), actual.Certificate())
+
+		s, err := api.Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, actual.Certificate(), s.Data[corev1.TLSCertKey])
+		require.Equal(t, actual.PrivateKey(), s.Data[corev1.TLSPrivateKeyKey])
+	})
+}
+
+func TestWebhookCABundle(t *testing.T) {
+	ca, err := security.NewCACertificate()
+	require.NoError(t, err)
+
+	previous, err := security.NewCACertificate()
+	require.NoError(t, err)
+
+	require.Equal(t, ca.Certificate(), WebhookCABundle(ca, nil).Certificate())
+	require.Equal(t, ca.Certificate(), WebhookCABundle(ca, ca).Certificate())
+
+	bundle := WebhookCABundle(ca, previous)
+	require.Equal(t, append(append([]byte{}, ca.Certificate()...), previous.Certificate()...), bundle.Certificate())
+	require.Equal(t, ca.PrivateKey(), bundle.PrivateKey())
+}
+
 func TestPatchMutatingWebhookConfig(t *testing.T) {
 	name := "cockroach-operator-mutating-webhook-configuration"
 
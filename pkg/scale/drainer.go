@@ -74,6 +74,20 @@ func NewCockroachNodeDrainer(logger logr.Logger, namespace, ssname string, confi
 	}
 }
 
+// NewCockroachDecommissionChecker ctor
+func NewCockroachDecommissionChecker(logger logr.Logger, namespace, ssname string, config *rest.Config, clientset kubernetes.Interface, secure bool) DecommissionChecker {
+	return &CockroachNodeDrainer{
+		Secure: secure,
+		Logger: logger,
+		Executor: &CockroachExecutor{
+			Namespace:   namespace,
+			StatefulSet: ssname,
+			Config:      config,
+			ClientSet:   clientset,
+		},
+	}
+}
+
 // Decommission commands the node to start training process and watches for it to complete or fail after timeout
 func (d *CockroachNodeDrainer) Decommission(ctx context.Context, replica uint, gRPCPort int32) error {
 	lastNodeID, err := d.findNodeID(ctx, replica, d.Executor.StatefulSet)
@@ -230,6 +244,68 @@ func (d *CockroachNodeDrainer) markNodeAsDecommissioned(ctx context.Context, id
 	return nil
 }
 
+// NodeDecommissioned returns the ID of the last node that ran on the volumes of a replica, 0 if
+// none did, and whether CockroachDB reports it fully decommissioned.
+func (d *CockroachNodeDrainer) NodeDecommissioned(ctx context.Context, replica uint) (uint, bool, error) {
+	cmd := []string{"./cockroach", "node", "status", "--all", "--decommission", "--format=csv"}
+
+	if d.Secure {
+		cmd = append(cmd, "--certs-dir=cockroach-certs")
+	} else {
+		cmd = append(cmd, "--insecure")
+	}
+
+	stdout, _, err := d.Executor.Exec(ctx, 0, cmd)
+	if err != nil {
+		return 0, false, err
+	}
+
+	host := fmt.Sprintf("%s-%d.%s.%s", d.Executor.StatefulSet,
+		replica, d.Executor.StatefulSet, d.Executor.Namespace)
+	return lastNodeMembership(strings.NewReader(stdout), host)
+}
+
+// lastNodeMembership returns the ID of the last node with the given host in the output of
+// `cockroach node status --all --decommission --format=csv`, and whether it is decommissioned.
+// Nodes that run on the same volumes again get a new ID, the highest.
+func lastNodeMembership(r io.Reader, host string) (uint, bool, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to read the node status")
+	}
+	if len(records) == 0 {
+		return 0, false, errors.New("empty node status")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	for _, name := range []string{"id", "address", "membership"} {
+		if _, ok := columns[name]; !ok {
+			return 0, false, fmt.Errorf("node status does not report the %s of the nodes", name)
+		}
+	}
+
+	var lastID uint64
+	decommissioned := false
+	for _, record := range records[1:] {
+		if !strings.Contains(record[columns["address"]], host) {
+			continue
+		}
+		id, err := strconv.ParseUint(record[columns["id"]], 10, 32)
+		if err != nil {
+			return 0, false, errors.Wrap(err, "failed to extract node id from string")
+		}
+		if id > lastID {
+			lastID = id
+			decommissioned = record[columns["membership"]] == "decommissioned"
+		}
+	}
+
+	return uint(lastID), decommissioned, nil
+}
+
 func (d *CockroachNodeDrainer) findNodeID(ctx context.Context, replica uint, stsName string) (uint, error) {
 	cmd := []string{"./cockroach", "node", "status", "--format=csv"}
 
@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scale
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastNodeMembership(t *testing.T) {
+	status := `id,address,sql_address,build,started_at,updated_at,locality,is_available,is_live,gossiped_replicas,is_decommissioning,membership,is_draining
+1,cockroachdb-0.cockroachdb.testns:26257,cockroachdb-0.cockroachdb.testns:26257,v23.1.11,,,,true,true,10,false,active,false
+4,cockroachdb-3.cockroachdb.testns:26257,cockroachdb-3.cockroachdb.testns:26257,v23.1.11,,,,false,false,0,true,decommissioned,true
+6,cockroachdb-3.cockroachdb.testns:26257,cockroachdb-3.cockroachdb.testns:26257,v23.1.11,,,,true,true,3,true,decommissioning,false
+5,cockroachdb-4.cockroachdb.testns:26257,cockroachdb-4.cockroachdb.testns:26257,v23.1.11,,,,false,false,0,true,decommissioned,true
+`
+
+	tests := []struct {
+		name           string
+		host           string
+		id             uint
+		decommissioned bool
+	}{
+		{name: "active node", host: "cockroachdb-0.cockroachdb.testns", id: 1},
+		{name: "the last node of the replica is decommissioning", host: "cockroachdb-3.cockroachdb.testns", id: 6},
+		{name: "decommissioned node", host: "cockroachdb-4.cockroachdb.testns", id: 5, decommissioned: true},
+		{name: "no node ran on the replica", host: "cockroachdb-5.cockroachdb.testns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, decommissioned, err := lastNodeMembership(strings.NewReader(status), tt.host)
+			require.NoError(t, err)
+			require.Equal(t, tt.id, id)
+			require.Equal(t, tt.decommissioned, decommissioned)
+		})
+	}
+
+	_, _, err := lastNodeMembership(strings.NewReader("id,address\n1,cockroachdb-0\n"), "cockroachdb-0")
+	require.EqualError(t, err, "node status does not report the membership of the nodes")
+}
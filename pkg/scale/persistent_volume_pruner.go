@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/go-logr/logr"
@@ -32,6 +34,18 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// PruneAfterAnnotation records on an unused PVC the time after which its
+// retention delay has passed and it can be deleted.
+const PruneAfterAnnotation = "crdb.io/prune-after"
+
+// retainedCheckInterval is the interval between two checks of the PVCs
+// retained until their node is decommissioned or their snapshot is ready.
+const retainedCheckInterval = 10 * time.Second
+
+// orphanedCheckInterval is the interval between two checks of the PVCs no
+// node ran on, that are kept until they are deleted manually.
+const orphanedCheckInterval = time.Hour
+
 // PersistentVolumePruner provides a .Prune method to remove unused statefulset
 // PVC and their underlying PVs. The underlying PVs SHOULD have their reclaim
 // policy set to delete.
@@ -40,6 +54,50 @@ type PersistentVolumePruner struct {
 	StatefulSet string
 	ClientSet   kubernetes.Interface
 	Logger      logr.Logger
+	// Checker, if set, verifies that the last node that ran on the volumes of
+	// a replica is fully decommissioned before they are deleted.
+	Checker DecommissionChecker
+	// Snapshotter, if set, takes a snapshot of each PVC before it is deleted.
+	Snapshotter VolumeSnapshotter
+	// RetentionDelay is how long an unused PVC is kept before it is deleted.
+	RetentionDelay time.Duration
+	// Audit, if set, is called with every PVC deleted and the ID of the node
+	// that ran on it, 0 if unknown.
+	Audit func(pvc corev1.PersistentVolumeClaim, nodeID uint)
+}
+
+// DecommissionChecker reports whether the volumes of a replica are safe to
+// delete.
+type DecommissionChecker interface {
+	// NodeDecommissioned returns the ID of the last node that ran on the
+	// volumes of the replica, 0 if none did, and whether that node is fully
+	// decommissioned.
+	NodeDecommissioned(ctx context.Context, replica uint) (uint, bool, error)
+}
+
+// VolumeSnapshotter takes a snapshot of a PVC before it is deleted.
+type VolumeSnapshotter interface {
+	// SnapshotVolume takes a snapshot of the PVC if it was not taken yet and
+	// returns true once it is ready to use.
+	SnapshotVolume(ctx context.Context, pvc corev1.PersistentVolumeClaim) (bool, error)
+}
+
+// PVCsRetainedErr is returned by Prune when unused PVCs are kept until their
+// node is decommissioned, their retention delay passes or their snapshot is
+// ready to use, or because no node ever ran on them. Prune should be called
+// again after RetryAfter.
+type PVCsRetainedErr struct {
+	PVCs       []string
+	Orphaned   []string
+	RetryAfter time.Duration
+}
+
+func (e PVCsRetainedErr) Error() string {
+	msg := fmt.Sprintf("PVCs retained before pruning: %s", strings.Join(e.PVCs, ", "))
+	if len(e.Orphaned) > 0 {
+		msg += fmt.Sprintf(", PVCs no node ran on: %s", strings.Join(e.Orphaned, ", "))
+	}
+	return msg
 }
 
 // watchStatefulset establishing a watch on the given statefulset in a
@@ -112,80 +170,82 @@ func (p *PersistentVolumePruner) watchStatefulset(
 // ordinal that is less than the number of expected replica for the given
 // statefulset.
 func (p *PersistentVolumePruner) pvcsToDelete(ctx context.Context, sts *appsv1.StatefulSet) ([]corev1.PersistentVolumeClaim, error) {
+	_, unused, err := statefulSetPVCs(ctx, p.ClientSet, sts)
+	return unused, err
+}
+
+// PVCsInUse locates all PVCs that were provisioned for the given statefulset
+// and are currently in use by its replicas, sorted by name.
+func PVCsInUse(ctx context.Context, clientset kubernetes.Interface, sts *appsv1.StatefulSet) ([]corev1.PersistentVolumeClaim, error) {
+	inUse, _, err := statefulSetPVCs(ctx, clientset, sts)
+	return inUse, err
+}
+
+// statefulSetPVCs locates all PVCs that were provisioned for the given
+// statefulset and splits them between the PVCs in use and the PVCs not in
+// use, both sorted by name.
+func statefulSetPVCs(ctx context.Context, clientset kubernetes.Interface, sts *appsv1.StatefulSet) (
+	inUse, unused []corev1.PersistentVolumeClaim, err error) {
 	// K8s doesn't provide a way to tell if a PVC or PV is currently in use by
 	// a pod. However, it is safe to assume that any PVCs with an ordinal great
 	// than or equal to the sts' Replicas is not in use. As only pods with with
 	// an ordinal < Replicas will exist. Any PVCs with an ordinal less than
 	// Replicas is in use. To detect this, we build a map of PVCs that we
-	// consider to be in use and skip and PVCs that it contains
-	// the name of.
+	// consider to be in use.
 	prefixes := make([]string, len(sts.Spec.VolumeClaimTemplates))
-	pvcsToKeep := make(map[string]bool, int(*sts.Spec.Replicas)*len(sts.Spec.VolumeClaimTemplates))
+	pvcsInUse := make(map[string]bool, int(*sts.Spec.Replicas)*len(sts.Spec.VolumeClaimTemplates))
 	for j, pvct := range sts.Spec.VolumeClaimTemplates {
 		prefixes[j] = fmt.Sprintf("%s-%s-", pvct.Name, sts.Name)
 
 		for i := int32(0); i < *sts.Spec.Replicas; i++ {
 			name := fmt.Sprintf("%s-%s-%d", pvct.Name, sts.Name, i)
-			pvcsToKeep[name] = true
+			pvcsInUse[name] = true
 		}
 	}
 
 	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
 	if err != nil {
-		return nil, errors.Wrap(err, "converting statefulset selector to metav1 selector")
+		return nil, nil, errors.Wrap(err, "converting statefulset selector to metav1 selector")
 	}
 
-	pvcs, err := p.ClientSet.CoreV1().PersistentVolumeClaims(p.Namespace).List(ctx, metav1.ListOptions{
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(sts.Namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "listing PVCs to consider deleting")
+		return nil, nil, errors.Wrap(err, "listing PVCs of the statefulset")
 	}
 
-	i := 0
+	// Lexically sort pvcs to ensure we're deleting from lowest to highest.
+	// This isn't incredibly important but may save us from some race
+	// conditions. PVCs will be provisioned/reused from lowest to highest. If a
+	// new replica is created while we're pruning and we can't detect it or
+	// detect it fast enough, we'll _hopefully_ the requested PVCs will be
+	// deleting forcing a new one to be created.
+	// This is not a guarantee of any kind, just hedging our bets.
+	// However, it is still unexpected that this operation would happen
+	// concurrently due to our coarse grain cluster locking.
+	sort.Slice(pvcs.Items, func(i, j int) bool {
+		return pvcs.Items[i].Name < pvcs.Items[j].Name
+	})
+
 	for _, pvc := range pvcs.Items {
-		// Don't delete PVCs that are still in use.
-		if pvcsToKeep[pvc.Name] {
+		if pvcsInUse[pvc.Name] {
+			inUse = append(inUse, pvc)
 			continue
 		}
 
 		// Ensure that any PVC we consider deleting matches the expected naming
 		// convention for PVCs managed by a statefulset.
 		// <mount name>-<sts name>-<ordinal>
-		matched := false
 		for _, prefix := range prefixes {
 			if strings.HasPrefix(pvc.Name, prefix) {
-				matched = true
+				unused = append(unused, pvc)
 				break
 			}
 		}
-
-		if !matched {
-			continue
-		}
-
-		pvcs.Items[i] = pvc
-		i++
 	}
 
-	// Filter out any pvcs that are in use OR don't start with one of our
-	// expected prefixes.
-	pvcs.Items = pvcs.Items[:i]
-
-	// Lexically sort pvcs to ensure we're deleting from lowest to highest.
-	// This isn't incredibly important but may save us from some race
-	// conditions. PVCs will be provisioned/reused from lowest to highest. If a
-	// new replica is created while we're pruning and we can't detect it or
-	// detect it fast enough, we'll _hopefully_ the requested PVCs will be
-	// deleting forcing a new one to be created.
-	// This is not a guarantee of any kind, just hedging our bets.
-	// However, it is still unexpected that this operation would happen
-	// concurrently due to our coarse grain cluster locking.
-	sort.Slice(pvcs.Items, func(i, j int) bool {
-		return pvcs.Items[i].Name < pvcs.Items[j].Name
-	})
-
-	return pvcs.Items, nil
+	return inUse, unused, nil
 }
 
 // Prune locates and removes all PVCs that belong to a given statefulset but
@@ -195,6 +255,8 @@ func (p *PersistentVolumePruner) pvcsToDelete(ctx context.Context, sts *appsv1.S
 // .Spec.Replicas field changes will this operation is running.
 // The underlying PVs' reclaim policy should be set to delete, other options
 // may result in leaking volumes which cost us money.
+// PVCs that must be kept a while longer are skipped and reported by a
+// PVCsRetainedErr once the other PVCs are removed.
 func (p *PersistentVolumePruner) Prune(ctx context.Context) error {
 	sts, err := p.ClientSet.AppsV1().StatefulSets(p.Namespace).Get(
 		ctx,
@@ -229,6 +291,10 @@ func (p *PersistentVolumePruner) Prune(ctx context.Context) error {
 	gracePeriod := int64(60)
 	propagationPolicy := metav1.DeletePropagationForeground
 
+	now := time.Now()
+	nodes := make(map[uint]replicaNode)
+	var retained PVCsRetainedErr
+
 	for _, pvc := range pvcs {
 		// Ensure that our context is still active. It will be canceled if a
 		// change to sts.Spec.Replicas is detected.
@@ -238,6 +304,22 @@ func (p *PersistentVolumePruner) Prune(ctx context.Context) error {
 		default:
 		}
 
+		nodeID, keep, retryAfter, err := p.retain(ctx, pvc, now, nodes)
+		if err != nil {
+			return err
+		}
+		if keep {
+			if p.Checker != nil && nodeID == 0 {
+				retained.Orphaned = append(retained.Orphaned, pvc.Name)
+			} else {
+				retained.PVCs = append(retained.PVCs, pvc.Name)
+			}
+			if retained.RetryAfter == 0 || retryAfter < retained.RetryAfter {
+				retained.RetryAfter = retryAfter
+			}
+			continue
+		}
+
 		p.Logger.V(int(zapcore.DebugLevel)).Info("deleting PVC", "name", pvc.Name)
 		if err := p.ClientSet.CoreV1().PersistentVolumeClaims(p.Namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{
 			GracePeriodSeconds: &gracePeriod,
@@ -258,7 +340,94 @@ func (p *PersistentVolumePruner) Prune(ctx context.Context) error {
 		}); err != nil {
 			return errors.Wrapf(err, "delting pvc %s", pvc.Name)
 		}
+
+		if p.Audit != nil {
+			p.Audit(pvc, nodeID)
+		}
 	}
 
+	if len(retained.PVCs) > 0 || len(retained.Orphaned) > 0 {
+		return retained
+	}
 	return nil
 }
+
+// replicaNode is the last node that ran on the volumes of a replica.
+type replicaNode struct {
+	id             uint
+	decommissioned bool
+}
+
+// retain returns whether an unused PVC must be kept, and when to check it
+// again. A PVC is kept until the node that ran on it is decommissioned, its
+// retention delay has passed and its snapshot is ready to use. A PVC no node
+// ever ran on is kept until it is deleted manually. The nodes already checked
+// are cached in nodes by replica.
+func (p *PersistentVolumePruner) retain(ctx context.Context, pvc corev1.PersistentVolumeClaim, now time.Time,
+	nodes map[uint]replicaNode) (nodeID uint, keep bool, retryAfter time.Duration, err error) {
+	if p.Checker != nil {
+		replica, err := pvcOrdinal(pvc.Name)
+		if err != nil {
+			return 0, false, 0, err
+		}
+		node, ok := nodes[replica]
+		if !ok {
+			node.id, node.decommissioned, err = p.Checker.NodeDecommissioned(ctx, replica)
+			if err != nil {
+				return 0, false, 0, errors.Wrapf(err, "checking the node of pvc %s", pvc.Name)
+			}
+			nodes[replica] = node
+		}
+
+		nodeID = node.id
+		if node.id == 0 {
+			p.Logger.V(int(zapcore.WarnLevel)).Info("keeping PVC, no node ran on it", "name", pvc.Name)
+			return nodeID, true, orphanedCheckInterval, nil
+		}
+		if !node.decommissioned {
+			p.Logger.V(int(zapcore.WarnLevel)).Info("keeping PVC, its node is not decommissioned", "name", pvc.Name, "node", node.id)
+			return nodeID, true, retainedCheckInterval, nil
+		}
+	}
+
+	if p.RetentionDelay > 0 {
+		pruneAfter, err := time.Parse(time.RFC3339, pvc.Annotations[PruneAfterAnnotation])
+		if err != nil {
+			pruneAfter = now.Add(p.RetentionDelay)
+			if pvc.Annotations == nil {
+				pvc.Annotations = make(map[string]string)
+			}
+			pvc.Annotations[PruneAfterAnnotation] = pruneAfter.UTC().Format(time.RFC3339)
+			if _, err := p.ClientSet.CoreV1().PersistentVolumeClaims(p.Namespace).Update(ctx, &pvc, metav1.UpdateOptions{}); err != nil {
+				return nodeID, false, 0, errors.Wrapf(err, "annotating pvc %s", pvc.Name)
+			}
+		}
+		if now.Before(pruneAfter) {
+			p.Logger.V(int(zapcore.InfoLevel)).Info("keeping PVC until its retention delay passes", "name", pvc.Name, "until", pruneAfter)
+			return nodeID, true, pruneAfter.Sub(now), nil
+		}
+	}
+
+	if p.Snapshotter != nil {
+		ready, err := p.Snapshotter.SnapshotVolume(ctx, pvc)
+		if err != nil {
+			return nodeID, false, 0, errors.Wrapf(err, "taking a snapshot of pvc %s", pvc.Name)
+		}
+		if !ready {
+			p.Logger.V(int(zapcore.InfoLevel)).Info("keeping PVC until its snapshot is ready to use", "name", pvc.Name)
+			return nodeID, true, retainedCheckInterval, nil
+		}
+	}
+
+	return nodeID, false, 0, nil
+}
+
+// pvcOrdinal returns the ordinal of the replica of a PVC named
+// <mount name>-<sts name>-<ordinal>.
+func pvcOrdinal(name string) (uint, error) {
+	ordinal, err := strconv.ParseUint(name[strings.LastIndex(name, "-")+1:], 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing the ordinal of pvc %s", name)
+	}
+	return uint(ordinal), nil
+}
@@ -261,3 +261,182 @@ func TestPersistentVolumePruner_Prune(t *testing.T) {
 func NewFakeLogger(t *testing.T) logr.Logger {
 	return log.NewTestLogger(t)
 }
+
+func TestPVCsInUse(t *testing.T) {
+	replicas := int32(2)
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "cockroachdb", Namespace: "testns"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cockroach"}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "datadir"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "wal-failover"}},
+			},
+		},
+	}
+
+	var objs []runtime.Object
+	for _, name := range []string{
+		"datadir-cockroachdb-1",
+		"datadir-cockroachdb-0",
+		"datadir-cockroachdb-2",
+		"wal-failover-cockroachdb-0",
+		"wal-failover-cockroachdb-1",
+		"other-0",
+	} {
+		objs = append(objs, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "testns",
+			Labels:    map[string]string{"app": "cockroach"},
+		}})
+	}
+
+	pvcs, err := PVCsInUse(context.Background(), fake.NewSimpleClientset(objs...), sts)
+	require.NoError(t, err)
+
+	var names []string
+	for _, pvc := range pvcs {
+		names = append(names, pvc.Name)
+	}
+	require.Equal(t, []string{
+		"datadir-cockroachdb-0",
+		"datadir-cockroachdb-1",
+		"wal-failover-cockroachdb-0",
+		"wal-failover-cockroachdb-1",
+	}, names)
+}
+
+type fakeDecommissionChecker map[uint]replicaNode
+
+func (c fakeDecommissionChecker) NodeDecommissioned(_ context.Context, replica uint) (uint, bool, error) {
+	return c[replica].id, c[replica].decommissioned, nil
+}
+
+type fakeSnapshotter struct {
+	ready bool
+}
+
+func (s fakeSnapshotter) SnapshotVolume(_ context.Context, _ corev1.PersistentVolumeClaim) (bool, error) {
+	return s.ready, nil
+}
+
+func TestPersistentVolumePruner_PruneRetainedPVCs(t *testing.T) {
+	decommissioned := fakeDecommissionChecker{3: {id: 4, decommissioned: true}, 4: {id: 5, decommissioned: true}}
+	pastDelay := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+	testCases := []struct {
+		Name        string
+		Pruner      PersistentVolumePruner
+		Annotations map[string]string
+		Retained    []string
+		Orphaned    []string
+		Remaining   []string
+		Audited     map[string]uint
+	}{
+		{
+			Name: "only the PVCs of decommissioned nodes are deleted",
+			Pruner: PersistentVolumePruner{Checker: fakeDecommissionChecker{
+				3: {id: 4, decommissioned: true},
+				4: {id: 5},
+			}},
+			Retained:  []string{"datadir-cockroachdb-4"},
+			Remaining: []string{"datadir-cockroachdb-4"},
+			Audited:   map[string]uint{"datadir-cockroachdb-3": 4},
+		},
+		{
+			Name:      "PVCs no node ran on are kept and reported",
+			Pruner:    PersistentVolumePruner{Checker: fakeDecommissionChecker{3: {id: 4, decommissioned: true}}},
+			Orphaned:  []string{"datadir-cockroachdb-4"},
+			Remaining: []string{"datadir-cockroachdb-4"},
+			Audited:   map[string]uint{"datadir-cockroachdb-3": 4},
+		},
+		{
+			Name:      "PVCs are kept for the retention delay",
+			Pruner:    PersistentVolumePruner{Checker: decommissioned, RetentionDelay: time.Hour},
+			Retained:  []string{"datadir-cockroachdb-3", "datadir-cockroachdb-4"},
+			Remaining: []string{"datadir-cockroachdb-3", "datadir-cockroachdb-4"},
+			Audited:   map[string]uint{},
+		},
+		{
+			Name:        "PVCs are deleted after the retention delay",
+			Pruner:      PersistentVolumePruner{Checker: decommissioned, RetentionDelay: time.Hour},
+			Annotations: map[string]string{PruneAfterAnnotation: pastDelay},
+			Audited:     map[string]uint{"datadir-cockroachdb-3": 4, "datadir-cockroachdb-4": 5},
+		},
+		{
+			Name:      "PVCs are kept until their snapshot is ready",
+			Pruner:    PersistentVolumePruner{Checker: decommissioned, Snapshotter: fakeSnapshotter{}},
+			Retained:  []string{"datadir-cockroachdb-3", "datadir-cockroachdb-4"},
+			Remaining: []string{"datadir-cockroachdb-3", "datadir-cockroachdb-4"},
+			Audited:   map[string]uint{},
+		},
+		{
+			Name:    "PVCs are deleted once their snapshot is ready",
+			Pruner:  PersistentVolumePruner{Checker: decommissioned, Snapshotter: fakeSnapshotter{ready: true}},
+			Audited: map[string]uint{"datadir-cockroachdb-3": 4, "datadir-cockroachdb-4": 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			replicas := int32(3)
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "cockroachdb", Namespace: "testns"},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cockroach"}},
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+						{ObjectMeta: metav1.ObjectMeta{Name: "datadir", Namespace: "testns"}},
+					},
+				},
+			}
+			objects := []runtime.Object{sts}
+			for i := 0; i < 5; i++ {
+				objects = append(objects, &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("datadir-cockroachdb-%d", i),
+						Namespace:   "testns",
+						Labels:      map[string]string{"app": "cockroach"},
+						Annotations: tc.Annotations,
+					},
+				})
+			}
+			cs := fake.NewSimpleClientset(objects...)
+
+			audited := make(map[string]uint)
+			pruner := tc.Pruner
+			pruner.Namespace = "testns"
+			pruner.StatefulSet = "cockroachdb"
+			pruner.ClientSet = cs
+			pruner.Logger = NewFakeLogger(t)
+			pruner.Audit = func(pvc corev1.PersistentVolumeClaim, nodeID uint) {
+				audited[pvc.Name] = nodeID
+			}
+
+			err := pruner.Prune(ctx)
+			if tc.Retained != nil || tc.Orphaned != nil {
+				var retained PVCsRetainedErr
+				require.ErrorAs(t, err, &retained)
+				require.Equal(t, tc.Retained, retained.PVCs)
+				require.Equal(t, tc.Orphaned, retained.Orphaned)
+				require.Greater(t, retained.RetryAfter, time.Duration(0))
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.Audited, audited)
+
+			pvcs, err := cs.CoreV1().PersistentVolumeClaims("testns").List(ctx, metav1.ListOptions{})
+			require.NoError(t, err)
+			var remaining []string
+			for _, pvc := range pvcs.Items[3:] {
+				remaining = append(remaining, pvc.Name)
+				if tc.Pruner.RetentionDelay > 0 {
+					require.NotEmpty(t, pvc.Annotations[PruneAfterAnnotation])
+				}
+			}
+			require.Equal(t, tc.Remaining, remaining)
+		})
+	}
+}
@@ -57,8 +57,11 @@ func (s *Scaler) EnsureScale(ctx context.Context, scale uint, gRPCPort int32, pr
 	// make use of reclaim policy = delete. A reclaim policy of retain is fine
 	// but will result in wasted money, recycle should be considered unsafe and
 	// is officially deprecated by kubernetes.
+	// PVCs retained by the pruner only prevent scaling up, which would reuse
+	// them.
+	var retained PVCsRetainedErr
 	if prunePVC {
-		if err := s.PVCPruner.Prune(ctx); err != nil {
+		if err := s.PVCPruner.Prune(ctx); err != nil && !errors.As(err, &retained) {
 			return errors.Wrap(err, "initial PVC pruning")
 		}
 	} else {
@@ -132,6 +135,9 @@ func (s *Scaler) EnsureScale(ctx context.Context, scale uint, gRPCPort int32, pr
 	// Final note: It does appear possible to alter immutable fields in statefulsets by deleting them
 	// with cascade = false and recreating them. They will "adopt" the old/existing pods and in theory not
 	// have an affect on the cluster as a whole.
+	if crdbScale < scale && len(retained.PVCs) > 0 {
+		return errors.Wrap(retained, "initial PVC pruning")
+	}
 	for crdbScale < scale {
 		s.Logger.V(int(zapcore.DebugLevel)).Info("scaling up stateful set", "have", crdbScale, "want", (crdbScale + 1))
 		if err := s.CRDB.SetReplicas(ctx, crdbScale+1); err != nil {
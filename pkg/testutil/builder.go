@@ -161,11 +161,36 @@ func (b ClusterBuilder) WithIngress(ingress *api.IngressConfig) ClusterBuilder {
 	return b
 }
 
+func (b ClusterBuilder) WithNetworkPolicy(config *api.NetworkPolicyConfig) ClusterBuilder {
+	b.cluster.Spec.NetworkPolicy = config
+	return b
+}
+
+func (b ClusterBuilder) WithEncryption(config *api.EncryptionConfig) ClusterBuilder {
+	b.cluster.Spec.Encryption = config
+	return b
+}
+
+func (b ClusterBuilder) WithGateway(gateway *api.GatewayConfig) ClusterBuilder {
+	b.cluster.Spec.Gateway = gateway
+	return b
+}
+
 func (b ClusterBuilder) WithResources(resources corev1.ResourceRequirements) ClusterBuilder {
 	b.cluster.Spec.Resources = resources
 	return b
 }
 
+func (b ClusterBuilder) WithOverrides(overrides ...api.ResourceOverride) ClusterBuilder {
+	b.cluster.Spec.Overrides = overrides
+	return b
+}
+
+func (b ClusterBuilder) WithPublicServices(config *api.PublicServiceConfig) ClusterBuilder {
+	b.cluster.Spec.Services = &api.ServicesConfig{Public: config}
+	return b
+}
+
 func (b ClusterBuilder) WithStatus(status api.CrdbClusterStatus) ClusterBuilder {
 	b.cluster.Status = status
 	return b
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/cockroachdb/cockroach-operator/pkg/clustersql"
+	"github.com/go-logr/logr"
+)
+
+// estimatedPodUpdateDuration is a rough estimate of the time it takes to restart a
+// pod with a new image and to pass the health checks afterwards.
+var estimatedPodUpdateDuration = 2 * time.Minute
+
+// UpgradePreview describes what UpdateClusterCockroachVersion would do.
+type UpgradePreview struct {
+	// Kind is the release type of the upgrade: PATCH, MAJOR_UPGRADE, MAJOR_ROLLBACK or UNKNOWN
+	Kind string
+	// Steps are the actions taken in order
+	Steps []string
+	// Duration is a rough estimate of how long the steps take
+	Duration time.Duration
+	// Blockers are the reasons the upgrade would not run
+	Blockers []string
+}
+
+// PreviewUpgrade computes the steps of updating the StatefulSet to the wanted version
+// without touching it. The preserve downgrade option and node liveness are checked
+// when update.Db is set, they are skipped for hops that follow another one.
+func PreviewUpgrade(
+	ctx context.Context,
+	update *UpdateRoach,
+	cluster *UpdateCluster,
+	replicas int32,
+	l logr.Logger,
+) UpgradePreview {
+	preview := UpgradePreview{Kind: upgradeKind(update.WantVersion, update.CurrentVersion)}
+
+	if update.Db != nil {
+		kind, err := kindAndCheckPreserveDowngradeSetting(ctx, update.WantVersion, update.CurrentVersion, update.Db, l)
+		preview.Kind = kind
+		if err != nil {
+			preview.Blockers = append(preview.Blockers, err.Error())
+		}
+
+		nodes, err := clustersql.NonLiveNodes(ctx, update.Db)
+		if err != nil {
+			preview.Blockers = append(preview.Blockers, err.Error())
+		} else if len(nodes) > 0 {
+			preview.Blockers = append(preview.Blockers, fmt.Sprintf("nodes %v are not live", nodes))
+		}
+	} else if preview.Kind == "UNKNOWN" {
+		preview.Blockers = append(preview.Blockers, UpdateNotAllowed{
+			cur:   update.CurrentVersion,
+			want:  update.WantVersion,
+			extra: "only patches, rolling forward one major version, & rolling back one major version supported",
+		}.Error())
+	}
+
+	if preview.Kind == "MAJOR_UPGRADE" {
+		preview.Steps = append(preview.Steps, fmt.Sprintf("set %s to %d.%d", PreserveDowngradeOptionClusterSetting,
+			update.CurrentVersion.Major(), update.CurrentVersion.Minor()))
+	}
+
+	for partition := replicas - 1; partition >= 0; partition-- {
+		preview.Steps = append(preview.Steps, fmt.Sprintf("update pod %s-%d to %s", update.StsName, partition, update.WantImageName))
+		preview.Duration += estimatedPodUpdateDuration
+
+		if cluster.Canaries > 0 && partition == replicas-cluster.Canaries {
+			preview.Steps = append(preview.Steps, fmt.Sprintf("soak %d canary pod(s) for %s", cluster.Canaries, cluster.SoakDuration))
+			preview.Duration += cluster.SoakDuration
+		}
+	}
+
+	return preview
+}
+
+// upgradeKind returns the release type of moving from currentVersion to wantVersion.
+func upgradeKind(wantVersion, currentVersion *semver.Version) string {
+	switch {
+	case isPatch(wantVersion, currentVersion):
+		return "PATCH"
+	case isMajorUpgradeAllowed(wantVersion, currentVersion):
+		return "MAJOR_UPGRADE"
+	case isMajorRollbackAllowed(wantVersion, currentVersion):
+		return "MAJOR_ROLLBACK"
+	}
+	return "UNKNOWN"
+}
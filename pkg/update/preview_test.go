@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/require"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestPreviewUpgrade(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      string
+		want         string
+		canaries     int32
+		kind         string
+		steps        []string
+		duration     time.Duration
+		blockerCount int
+	}{
+		{
+			name:    "patch",
+			current: "v21.1.0",
+			want:    "v21.1.1",
+			kind:    "PATCH",
+			steps: []string{
+				"update pod crdb-1 to img",
+				"update pod crdb-0 to img",
+			},
+			duration: 2 * estimatedPodUpdateDuration,
+		},
+		{
+			name:     "major upgrade with a canary",
+			current:  "v21.1.0",
+			want:     "v21.2.0",
+			canaries: 1,
+			kind:     "MAJOR_UPGRADE",
+			steps: []string{
+				"set cluster.preserve_downgrade_option to 21.1",
+				"update pod crdb-1 to img",
+				"soak 1 canary pod(s) for 10m0s",
+				"update pod crdb-0 to img",
+			},
+			duration: 2*estimatedPodUpdateDuration + 10*time.Minute,
+		},
+		{
+			name:    "unsupported upgrade",
+			current: "v20.1.0",
+			want:    "v21.2.0",
+			kind:    "UNKNOWN",
+			steps: []string{
+				"update pod crdb-1 to img",
+				"update pod crdb-0 to img",
+			},
+			duration:     2 * estimatedPodUpdateDuration,
+			blockerCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := &UpdateRoach{
+				CurrentVersion: semver.MustParse(tt.current),
+				WantVersion:    semver.MustParse(tt.want),
+				WantImageName:  "img",
+				StsName:        "crdb",
+			}
+			cluster := &UpdateCluster{Canaries: tt.canaries, SoakDuration: 10 * time.Minute}
+
+			preview := PreviewUpgrade(context.Background(), update, cluster, 2, logf.Log)
+			require.Equal(t, tt.kind, preview.Kind)
+			require.Equal(t, tt.steps, preview.Steps)
+			require.Equal(t, tt.duration, preview.Duration)
+			require.Len(t, preview.Blockers, tt.blockerCount)
+		})
+	}
+}
@@ -77,6 +77,52 @@ type UpdateTimer struct {
 	healthChecker         healthchecker.HealthChecker
 	// TODO check that this func is actually correct
 	waitUntilAllPodsReadyFunc func(context.Context, logr.Logger) error
+	// canaries is the number of pods that are updated before soakCanariesFunc is run.
+	canaries int32
+	// soakCanariesFunc watches the health of the cluster once the canaries are updated.
+	soakCanariesFunc func(context.Context, logr.Logger) error
+}
+
+// CanaryFailedErr is returned when the health checks fail while the canaries of an
+// update are soaking. The pods that are not canaries are left untouched.
+type CanaryFailedErr struct {
+	Canaries int32
+	Err      error
+}
+
+func (e CanaryFailedErr) Error() string {
+	return fmt.Sprintf("%d canary pod(s) failed health checks while soaking: %v", e.Canaries, e.Err)
+}
+
+func (e CanaryFailedErr) Unwrap() error {
+	return e.Err
+}
+
+// CanariesSoakingErr is returned while the canaries of an update are healthy but have not
+// soaked for the whole soak duration yet. The update holds, and the next call with the same
+// start time checks the canaries again and continues once the soak is over.
+type CanariesSoakingErr struct {
+	StartTime  time.Time
+	CheckAfter time.Duration
+}
+
+func (e CanariesSoakingErr) Error() string {
+	return fmt.Sprintf("canaries are soaking since %s, checking them again in %s", e.StartTime.Format(time.RFC3339), e.CheckAfter)
+}
+
+// HealthCheckFailedErr is returned when the health checker probe fails after a pod
+// of the StatefulSet has been updated.
+type HealthCheckFailedErr struct {
+	Partition int32
+	Err       error
+}
+
+func (e HealthCheckFailedErr) Error() string {
+	return fmt.Sprintf("health check failed after updating pod %d: %v", e.Partition, e.Err)
+}
+
+func (e HealthCheckFailedErr) Unwrap() error {
+	return e.Err
 }
 
 func NewUpdateFunctionSuite(
@@ -90,24 +136,21 @@ func NewUpdateFunctionSuite(
 }
 
 // TODO rewrite docs
-// TODO too many parmeters, just found a bug where I reversed namespace and sts name
-// Refactor this into a struct
 
 // UpdateClusterRegionStatefulSet is the regional version of
 // updateClusterStatefulSets. See its documentation for more information on the
 // parameters passed to this function.
 func UpdateClusterRegionStatefulSet(
 	ctx context.Context,
-	clientset kubernetes.Interface,
+	cluster *UpdateCluster,
 	name string,
 	namespace string,
 	updateSuite *updateFunctionSuite,
 	waitUntilAllPodsReadyFunc func(context.Context, logr.Logger) error,
-	podUpdateTimeout time.Duration,
-	podMaxPollingInterval time.Duration,
-	healthChecker healthchecker.HealthChecker,
+	soakCanariesFunc func(context.Context, logr.Logger) error,
 	l logr.Logger,
 ) (bool, error) {
+	clientset := cluster.Clientset
 	l = l.WithName(namespace)
 
 	sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -132,10 +175,12 @@ func UpdateClusterRegionStatefulSet(
 	}
 
 	updateTimer := &UpdateTimer{
-		podUpdateTimeout:          podUpdateTimeout,
-		podMaxPollingInterval:     podMaxPollingInterval,
-		healthChecker:             healthChecker,
+		podUpdateTimeout:          cluster.PodUpdateTimeout,
+		podMaxPollingInterval:     cluster.PodMaxPollingInterval,
+		healthChecker:             cluster.HealthChecker,
 		waitUntilAllPodsReadyFunc: waitUntilAllPodsReadyFunc,
+		canaries:                  cluster.Canaries,
+		soakCanariesFunc:          soakCanariesFunc,
 	}
 	// updateStrategyFunc is responsible for controlling the rollout of the
 	// changed StatefulSet definition across the pods in the Statefulset.
@@ -159,6 +204,11 @@ func UpdateClusterRegionStatefulSet(
 // takes a Kubernetes clientset, the StatefulSet being modified, and the pod
 // number of the Statefulset that has just been updated. If it returns an error,
 // the update is halted.
+//
+// When canaries are configured, the strategy holds once the highest numbered
+// canary pods are updated and runs the soakCanariesFunc. The remaining pods are
+// only updated if the canaries stay healthy, otherwise a CanaryFailedErr is
+// returned.
 func PartitionedRollingUpdateStrategy(perPodVerificationFunc func(*UpdateSts, int, logr.Logger) error,
 ) func(updateSts *UpdateSts, updateTimer *UpdateTimer, l logr.Logger) (bool, error) {
 	return func(updateSts *UpdateSts, updateTimer *UpdateTimer, l logr.Logger) (bool, error) {
@@ -176,6 +226,9 @@ func PartitionedRollingUpdateStrategy(perPodVerificationFunc func(*UpdateSts, in
 			if err := perPodVerificationFunc(updateSts, int(partition), l); err == nil {
 				l.V(int(zapcore.DebugLevel)).Info("already updated, skipping sleep", "partition", partition)
 				skipSleep = true
+				if err := soakCanariesIfNeeded(updateSts, updateTimer, partition, l); err != nil {
+					return skipSleep, err
+				}
 				continue
 			}
 
@@ -228,6 +281,9 @@ func PartitionedRollingUpdateStrategy(perPodVerificationFunc func(*UpdateSts, in
 				return false, handleStsError(err, l, stsName, stsNamespace)
 			}
 			if err := updateTimer.healthChecker.Probe(updateSts.ctx, l, fmt.Sprintf("between updating pods for %s", stsName), int(partition)); err != nil {
+				return skipSleep, HealthCheckFailedErr{Partition: partition, Err: err}
+			}
+			if err := soakCanariesIfNeeded(updateSts, updateTimer, partition, l); err != nil {
 				return skipSleep, err
 			}
 		}
@@ -235,6 +291,27 @@ func PartitionedRollingUpdateStrategy(perPodVerificationFunc func(*UpdateSts, in
 	}
 }
 
+// soakCanariesIfNeeded runs the soakCanariesFunc once the pod at the given partition
+// is the last canary to be updated.
+func soakCanariesIfNeeded(updateSts *UpdateSts, updateTimer *UpdateTimer, partition int32, l logr.Logger) error {
+	if updateTimer.canaries <= 0 || updateTimer.soakCanariesFunc == nil {
+		return nil
+	}
+	if partition != *updateSts.sts.Spec.Replicas-updateTimer.canaries {
+		return nil
+	}
+
+	l.V(int(zapcore.InfoLevel)).Info("canaries updated, soaking before updating the remaining pods", "canaries", updateTimer.canaries)
+	if err := updateTimer.soakCanariesFunc(updateSts.ctx, l); err != nil {
+		var soaking CanariesSoakingErr
+		if errors.As(err, &soaking) {
+			return err
+		}
+		return CanaryFailedErr{Canaries: updateTimer.canaries, Err: err}
+	}
+	return nil
+}
+
 func waitUntilPerPodVerificationFuncVerifies(
 	updateSts *UpdateSts,
 	perPodVerificationFunc func(*UpdateSts, int, logr.Logger) error,
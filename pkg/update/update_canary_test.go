@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeHealthChecker struct {
+	probes int
+	err    error
+}
+
+func (f *fakeHealthChecker) Probe(_ context.Context, _ logr.Logger, _ string, _ int) error {
+	f.probes++
+	return f.err
+}
+
+func TestSoakCanariesIfNeeded(t *testing.T) {
+	replicas := int32(5)
+	sts := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: &replicas}}
+	updateSts := &UpdateSts{ctx: context.Background(), sts: sts}
+	l := logf.Log.WithName("test")
+
+	var soaks int
+	soakErr := errors.New("node 5 is not live")
+	timer := &UpdateTimer{
+		canaries: 2,
+		soakCanariesFunc: func(context.Context, logr.Logger) error {
+			soaks++
+			return soakErr
+		},
+	}
+
+	// only the partition of the last canary soaks
+	require.NoError(t, soakCanariesIfNeeded(updateSts, timer, 4, l))
+	require.NoError(t, soakCanariesIfNeeded(updateSts, timer, 2, l))
+	require.Equal(t, 0, soaks)
+
+	err := soakCanariesIfNeeded(updateSts, timer, 3, l)
+	require.Equal(t, 1, soaks)
+	var canaryErr CanaryFailedErr
+	require.True(t, errors.As(err, &canaryErr))
+	require.Equal(t, int32(2), canaryErr.Canaries)
+	require.ErrorIs(t, err, soakErr)
+
+	// soaking canaries hold the update without failing it
+	soakErr = CanariesSoakingErr{StartTime: time.Now(), CheckAfter: time.Second}
+	err = soakCanariesIfNeeded(updateSts, timer, 3, l)
+	require.Equal(t, soakErr, err)
+
+	// no canaries configured
+	timer.canaries = 0
+	require.NoError(t, soakCanariesIfNeeded(updateSts, timer, 5, l))
+	require.Equal(t, 2, soaks)
+}
+
+func TestMakeSoakCanariesFunc(t *testing.T) {
+	defer func(interval time.Duration) { soakCheckInterval = interval }(soakCheckInterval)
+	soakCheckInterval = 10 * time.Second
+	l := logf.Log.WithName("test")
+	update := &UpdateRoach{StsName: "crdb"}
+
+	t.Run("healthy canaries hold the update until the soak ends", func(t *testing.T) {
+		hc := &fakeHealthChecker{}
+		cluster := &UpdateCluster{HealthChecker: hc, SoakDuration: time.Hour}
+
+		err := makeSoakCanariesFunc(cluster, update)(context.Background(), l)
+		var soaking CanariesSoakingErr
+		require.True(t, errors.As(err, &soaking))
+		require.WithinDuration(t, time.Now(), soaking.StartTime, time.Minute)
+		require.Equal(t, soakCheckInterval, soaking.CheckAfter)
+		require.Equal(t, 1, hc.probes)
+
+		// the next check keeps the start of the soak
+		cluster.SoakStartTime = soaking.StartTime.Add(-59*time.Minute - 59*time.Second)
+		err = makeSoakCanariesFunc(cluster, update)(context.Background(), l)
+		require.True(t, errors.As(err, &soaking))
+		require.Equal(t, cluster.SoakStartTime, soaking.StartTime)
+		require.LessOrEqual(t, soaking.CheckAfter, time.Second)
+
+		cluster.SoakStartTime = time.Now().Add(-time.Hour)
+		require.NoError(t, makeSoakCanariesFunc(cluster, update)(context.Background(), l))
+		require.Equal(t, 3, hc.probes)
+	})
+
+	t.Run("the first failed check stops the soak", func(t *testing.T) {
+		hc := &fakeHealthChecker{err: errors.New("unavailable ranges")}
+		cluster := &UpdateCluster{HealthChecker: hc, SoakDuration: time.Hour}
+
+		require.EqualError(t, makeSoakCanariesFunc(cluster, update)(context.Background(), l), "unavailable ranges")
+		require.Equal(t, 1, hc.probes)
+	})
+}
@@ -64,8 +64,17 @@ type UpdateCluster struct {
 	PodUpdateTimeout      time.Duration
 	PodMaxPollingInterval time.Duration
 	HealthChecker         healthchecker.HealthChecker
+	// Canaries is the number of pods that are updated before the update soaks
+	Canaries int32
+	// SoakDuration is how long the health of the canaries is watched
+	SoakDuration time.Duration
+	// SoakStartTime is when the canaries started soaking, zero until they are updated
+	SoakStartTime time.Time
 }
 
+// soakCheckInterval is the longest pause between two health checks while the canaries soak.
+var soakCheckInterval = 30 * time.Second
+
 // UpdateClusterCockroachVersion, and allows specifying custom pod timeouts,
 // among other things, in order to enable unit testing.
 func UpdateClusterCockroachVersion(
@@ -129,14 +138,12 @@ func updateClusterStatefulSets(
 	// It is the first param returned by UpdateClusterRegionStatefulSet
 	_, err := UpdateClusterRegionStatefulSet(
 		ctx,
-		cluster.Clientset,
+		cluster,
 		update.StsName,
 		update.StsNamespace,
 		updateSuite,
 		makeWaitUntilAllPodsReadyFunc(ctx, cluster, update),
-		cluster.PodUpdateTimeout,
-		cluster.PodMaxPollingInterval,
-		cluster.HealthChecker,
+		makeSoakCanariesFunc(cluster, update),
 		l)
 	if err != nil {
 		return err
@@ -180,6 +187,66 @@ func makeWaitUntilAllPodsReadyFunc(
 	}
 }
 
+// makeSoakCanariesFunc returns a function which checks the health of the cluster once
+// the canaries run the new version. Until the soak duration has passed since the soak
+// started, a healthy check returns a CanariesSoakingErr so that the update is continued
+// later instead of holding the caller. A failed health check stops the soak and is returned.
+func makeSoakCanariesFunc(
+	cluster *UpdateCluster,
+	update *UpdateRoach,
+) func(ctx context.Context, l logr.Logger) error {
+	return func(ctx context.Context, l logr.Logger) error {
+		if err := CheckClusterHealth(ctx, update, cluster, l); err != nil {
+			return err
+		}
+
+		start := cluster.SoakStartTime
+		if start.IsZero() {
+			start = time.Now()
+		}
+		remaining := time.Until(start.Add(cluster.SoakDuration))
+		if remaining <= 0 {
+			l.V(int(zapcore.InfoLevel)).Info("canaries stayed healthy for the whole soak", "duration", cluster.SoakDuration.String())
+			return nil
+		}
+		if remaining > soakCheckInterval {
+			remaining = soakCheckInterval
+		}
+		return CanariesSoakingErr{StartTime: start, CheckAfter: remaining}
+	}
+}
+
+// CheckClusterHealth runs the health checker probe and verifies that the cluster
+// serves SQL queries and that every active node is live.
+func CheckClusterHealth(
+	ctx context.Context,
+	update *UpdateRoach,
+	cluster *UpdateCluster,
+	l logr.Logger,
+) error {
+	if err := cluster.HealthChecker.Probe(ctx, l, fmt.Sprintf("checking health of %s", update.StsName), 0); err != nil {
+		return err
+	}
+
+	if update.Db == nil {
+		return nil
+	}
+
+	if _, err := update.Db.ExecContext(ctx, "SELECT 1"); err != nil {
+		return errors.Wrap(err, "sql health check failed")
+	}
+
+	nodes, err := clustersql.NonLiveNodes(ctx, update.Db)
+	if err != nil {
+		return err
+	}
+	if len(nodes) > 0 {
+		return errors.Errorf("nodes %v are not live", nodes)
+	}
+
+	return nil
+}
+
 func kindAndCheckPreserveDowngradeSetting(
 	ctx context.Context,
 	wantVersion *semver.Version,
@@ -287,3 +354,47 @@ func setDowngradeOption(ctx context.Context, wantVersion *semver.Version, curren
 
 	return nil
 }
+
+// PreserveDowngradeVersion returns the version the cluster.preserve_downgrade_option
+// cluster setting is set to, or an empty string once the running version is finalized.
+func PreserveDowngradeVersion(ctx context.Context, db *sql.DB) (string, error) {
+	preserve, err := preserveDowngradeSetting(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	if preserve.Compare(&semver.Version{}) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%d.%d", preserve.Major(), preserve.Minor()), nil
+}
+
+// FinalizeUpgrade resets the cluster.preserve_downgrade_option cluster setting so
+// that CockroachDB finalizes the running version. Afterwards the cluster can no
+// longer be downgraded to the previous major version.
+func FinalizeUpgrade(ctx context.Context, db *sql.DB, l logr.Logger) error {
+	if err := clustersql.ResetClusterSetting(ctx, db, PreserveDowngradeOptionClusterSetting); err != nil {
+		return errors.Wrapf(err, "finalizing upgrade failed")
+	}
+
+	l.V(int(zapcore.InfoLevel)).Info("finalized upgrade, reset downgrade option")
+	return nil
+}
+
+// RollbackAllowed returns true if the cluster running currentVersion can be moved
+// back to wantVersion. Patches can always be rolled back, a major upgrade only
+// while the preserve downgrade option still holds the previous version.
+func RollbackAllowed(ctx context.Context, wantVersion *semver.Version, currentVersion *semver.Version, db *sql.DB) (bool, error) {
+	if isPatch(wantVersion, currentVersion) {
+		return true, nil
+	}
+	if !isMajorRollbackAllowed(wantVersion, currentVersion) {
+		return false, nil
+	}
+
+	_, err := CheckDowngradeSetting(ctx, wantVersion, currentVersion, db)
+	var notAllowed UpdateNotAllowed
+	if errors.As(err, &notAllowed) {
+		return false, nil
+	}
+	return err == nil, err
+}
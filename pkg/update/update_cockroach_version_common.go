@@ -41,10 +41,13 @@ func makeUpdateCockroachVersionFunction(
 ) func(sts *v1.StatefulSet) (*v1.StatefulSet, error) {
 	return func(sts *v1.StatefulSet) (*v1.StatefulSet, error) {
 		timeNow := metav1.Now()
-		if val, ok := sts.Annotations[resource.CrdbHistoryAnnotation]; !ok {
-			sts.Annotations[resource.CrdbHistoryAnnotation] = fmt.Sprintf("%s=%s", timeNow.Format(time.RFC3339), oldVersion)
-		} else {
-			sts.Annotations[resource.CrdbHistoryAnnotation] = fmt.Sprintf("%s %s=%s", val, timeNow.Format(time.RFC3339), oldVersion)
+		// A resumed update does not change the version, so there is nothing to record.
+		if oldVersion != version {
+			if val, ok := sts.Annotations[resource.CrdbHistoryAnnotation]; !ok {
+				sts.Annotations[resource.CrdbHistoryAnnotation] = fmt.Sprintf("%s=%s", timeNow.Format(time.RFC3339), oldVersion)
+			} else {
+				sts.Annotations[resource.CrdbHistoryAnnotation] = fmt.Sprintf("%s %s=%s", val, timeNow.Format(time.RFC3339), oldVersion)
+			}
 		}
 		sts.Annotations[resource.CrdbVersionAnnotation] = version
 		sts.Annotations[resource.CrdbContainerImageAnnotation] = cockroachImage
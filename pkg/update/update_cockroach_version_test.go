@@ -17,8 +17,10 @@ limitations under the License.
 package update
 
 import (
+	"context"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	semver "github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/require"
 )
@@ -72,3 +74,42 @@ func TestPerserveMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestRollbackAllowed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	t.Run("patches can always be rolled back", func(t *testing.T) {
+		allowed, err := RollbackAllowed(ctx, semver.MustParse("24.1.5"), semver.MustParse("24.1.6"), db)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+
+	t.Run("major upgrade that preserves the previous version", func(t *testing.T) {
+		mock.ExpectQuery("SHOW CLUSTER SETTING cluster.preserve_downgrade_option").
+			WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("24.1"))
+
+		allowed, err := RollbackAllowed(ctx, semver.MustParse("24.1.5"), semver.MustParse("24.3.1"), db)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+
+	t.Run("major upgrade that is finalized", func(t *testing.T) {
+		mock.ExpectQuery("SHOW CLUSTER SETTING cluster.preserve_downgrade_option").
+			WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(""))
+
+		allowed, err := RollbackAllowed(ctx, semver.MustParse("24.1.5"), semver.MustParse("24.3.1"), db)
+		require.NoError(t, err)
+		require.False(t, allowed)
+	})
+
+	t.Run("versions that are too far apart", func(t *testing.T) {
+		allowed, err := RollbackAllowed(ctx, semver.MustParse("22.2.5"), semver.MustParse("24.3.1"), db)
+		require.NoError(t, err)
+		require.False(t, allowed)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"fmt"
+
+	semver "github.com/Masterminds/semver/v3"
+)
+
+// PlanUpgrade returns the versions a cluster running currentVersion moves through to
+// reach wantVersion, ending with wantVersion itself. When wantVersion can not be
+// reached directly, every hop goes to the most recent release series that can be
+// reached from the previous hop, using the latest patch of that series found in the
+// available versions.
+func PlanUpgrade(currentVersion *semver.Version, wantVersion *semver.Version, available []string) ([]string, error) {
+	var plan []string
+	hop := currentVersion
+	for !isPatch(wantVersion, hop) && !isMajorUpgradeAllowed(wantVersion, hop) {
+		if !olderSeries(hop, wantVersion) {
+			return nil, fmt.Errorf("can not plan an upgrade from %s to %s", currentVersion.Original(), wantVersion.Original())
+		}
+
+		var next *semver.Version
+		for _, v := range available {
+			candidate, err := semver.NewVersion(v)
+			if err != nil || candidate.Prerelease() != "" {
+				continue
+			}
+			if !olderSeries(candidate, wantVersion) || !isMajorUpgradeAllowed(candidate, hop) {
+				continue
+			}
+			if next == nil || candidate.GreaterThan(next) {
+				next = candidate
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("no supported version to upgrade from %s towards %s", hop.Original(), wantVersion.Original())
+		}
+
+		plan = append(plan, next.Original())
+		hop = next
+	}
+
+	return append(plan, wantVersion.Original()), nil
+}
+
+// ValidateVersionChange returns an error when a cluster running currentVersion cannot be updated to
+// wantVersion: a patch, a major upgrade allowed by isMajorUpgradeAllowed or a rollback allowed by
+// isMajorRollbackAllowed. With multiHop, any newer release series is accepted, PlanUpgrade moves
+// the cluster through the releases in between.
+func ValidateVersionChange(currentVersion, wantVersion string, multiHop bool) error {
+	want, err := semver.NewVersion(wantVersion)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid version: %v", wantVersion, err)
+	}
+
+	// the version of the cluster is not known, the update actor checks it
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case isPatch(want, current), isMajorUpgradeAllowed(want, current), isMajorRollbackAllowed(want, current):
+		return nil
+	case multiHop && olderSeries(current, want):
+		return nil
+	case olderSeries(current, want):
+		return fmt.Errorf("can not upgrade from %s to %s in one step, upgrade through the releases in between or set upgrade.multiHop", currentVersion, wantVersion)
+	}
+
+	return fmt.Errorf("can not roll back from %s to %s", currentVersion, wantVersion)
+}
+
+// olderSeries returns true if v belongs to a release series before the one of other.
+func olderSeries(v *semver.Version, other *semver.Version) bool {
+	return v.Major() < other.Major() || (v.Major() == other.Major() && v.Minor() < other.Minor())
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Cockroach Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"testing"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanUpgrade(t *testing.T) {
+	available := []string{
+		"v22.2.5", "v22.2.19",
+		"v23.1.3", "v23.1.28",
+		"v23.2.1", "v23.2.20",
+		"v24.1.0", "v24.1.5",
+		"v24.2.3",
+		"v24.3.1", "v24.3.8",
+		"v25.1.0-beta.1",
+	}
+
+	tests := []struct {
+		name    string
+		current string
+		want    string
+		plan    []string
+		errMsg  string
+	}{
+		{
+			name:    "patch",
+			current: "v23.1.3",
+			want:    "v23.1.28",
+			plan:    []string{"v23.1.28"},
+		},
+		{
+			name:    "single major upgrade",
+			current: "v23.1.28",
+			want:    "v23.2.20",
+			plan:    []string{"v23.2.20"},
+		},
+		{
+			name:    "one intermediate release series",
+			current: "v23.1.3",
+			want:    "v24.1.5",
+			plan:    []string{"v23.2.20", "v24.1.5"},
+		},
+		{
+			name:    "innovative releases are skipped",
+			current: "v22.2.5",
+			want:    "v24.3.8",
+			plan:    []string{"v23.1.28", "v23.2.20", "v24.1.5", "v24.3.8"},
+		},
+		{
+			name:    "missing release series",
+			current: "v21.2.0",
+			want:    "v23.1.28",
+			errMsg:  "no supported version to upgrade from v21.2.0 towards v23.1.28",
+		},
+		{
+			name:    "downgrade",
+			current: "v24.1.5",
+			want:    "v23.1.28",
+			errMsg:  "can not plan an upgrade from v24.1.5 to v23.1.28",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := PlanUpgrade(semver.MustParse(tt.current), semver.MustParse(tt.want), available)
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.plan, plan)
+		})
+	}
+}
+
+func TestValidateVersionChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		want     string
+		multiHop bool
+		errMsg   string
+	}{
+		{name: "patch", current: "v23.1.3", want: "v23.1.28"},
+		{name: "major upgrade", current: "v23.1.28", want: "v23.2.20"},
+		{name: "major rollback", current: "v23.2.20", want: "v23.1.28"},
+		{
+			name:    "version jump",
+			current: "v22.2.5",
+			want:    "v23.2.20",
+			errMsg:  "can not upgrade from v22.2.5 to v23.2.20 in one step, upgrade through the releases in between or set upgrade.multiHop",
+		},
+		{name: "version jump with multiHop", current: "v22.2.5", want: "v23.2.20", multiHop: true},
+		{
+			name:    "rollback jump",
+			current: "v23.2.20",
+			want:    "v22.2.5",
+			errMsg:  "can not roll back from v23.2.20 to v22.2.5",
+		},
+		{
+			name:    "invalid version",
+			current: "v23.1.3",
+			want:    "latest",
+			errMsg:  "latest is not a valid version: Invalid Semantic Version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateVersionChange(tt.current, tt.want, tt.multiHop)
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}